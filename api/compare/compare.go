@@ -0,0 +1,193 @@
+package compare
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+type Change string
+
+const (
+	ChangeNewlyFailing Change = "newly_failing"
+	ChangeNewlyPassing Change = "newly_passing"
+	ChangeStillFailing Change = "still_failing"
+	ChangeUnchanged    Change = "unchanged"
+	ChangeAdded        Change = "added"
+	ChangeRemoved      Change = "removed"
+)
+
+// Run identifies one side of a comparison
+type Run struct {
+	ID        string      `json:"id"`
+	Status    exec.Status `json:"status"`
+	BaseURL   string      `json:"base_url"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// Endpoint is the comparison of one endpoint across both runs. The base
+// fields are empty for added endpoints and the head fields for removed ones.
+type Endpoint struct {
+	Method            string   `json:"method"`
+	Path              string   `json:"path"`
+	Change            Change   `json:"change"`
+	BaseStatus        string   `json:"base_status,omitempty"`
+	HeadStatus        string   `json:"head_status,omitempty"`
+	BaseStatusCode    int      `json:"base_status_code,omitempty"`
+	HeadStatusCode    int      `json:"head_status_code,omitempty"`
+	StatusCodeChanged bool     `json:"status_code_changed"`
+	BaseLatencyMs     *float64 `json:"base_latency_ms,omitempty"`
+	HeadLatencyMs     *float64 `json:"head_latency_ms,omitempty"`
+	LatencyDeltaMs    *float64 `json:"latency_delta_ms,omitempty"`
+}
+
+// Summary counts the endpoints of each kind of change
+type Summary struct {
+	NewlyFailing      int `json:"newly_failing"`
+	NewlyPassing      int `json:"newly_passing"`
+	StillFailing      int `json:"still_failing"`
+	Unchanged         int `json:"unchanged"`
+	Added             int `json:"added"`
+	Removed           int `json:"removed"`
+	StatusCodeChanges int `json:"status_code_changes"`
+}
+
+// Report is the difference between a base run and a later head run
+type Report struct {
+	Base      Run        `json:"base"`
+	Head      Run        `json:"head"`
+	Summary   Summary    `json:"summary"`
+	Endpoints []Endpoint `json:"endpoints"`
+}
+
+// endpointKey aligns the checks of two runs. A run may check the same
+// endpoint more than once, so repeated checks are told apart by the order
+// they appear in.
+type endpointKey struct {
+	method     string
+	path       string
+	occurrence int
+}
+
+func keyResults(results []exec.CheckResult) ([]endpointKey, map[endpointKey]exec.CheckResult) {
+	keys := make([]endpointKey, 0, len(results))
+	byKey := make(map[endpointKey]exec.CheckResult, len(results))
+	for _, result := range results {
+		key := endpointKey{method: strings.ToUpper(result.Method), path: result.Path}
+		if key.method == "" {
+			key.method = "GET"
+		}
+		for {
+			if _, seen := byKey[key]; !seen {
+				break
+			}
+			key.occurrence++
+		}
+		keys = append(keys, key)
+		byKey[key] = result
+	}
+	return keys, byKey
+}
+
+func latency(result exec.CheckResult) *float64 {
+	if result.Timings == nil {
+		return nil
+	}
+	total := result.Timings.TotalMs
+	return &total
+}
+
+func passed(result exec.CheckResult) bool {
+	return result.StatusCode == string(exec.StatusPass)
+}
+
+// Compare aligns the checks of two runs by method and path. Endpoints are
+// listed in the order of the head run, followed by those only in the base run.
+func Compare(base, head db.TestResult) Report {
+	report := Report{
+		Base:      runOf(base),
+		Head:      runOf(head),
+		Endpoints: []Endpoint{},
+	}
+	baseKeys, baseResults := keyResults(base.Results)
+	headKeys, headResults := keyResults(head.Results)
+
+	for _, key := range headKeys {
+		headResult := headResults[key]
+		endpoint := Endpoint{
+			Method:         key.method,
+			Path:           key.path,
+			HeadStatus:     headResult.StatusCode,
+			HeadStatusCode: headResult.ActualStatus,
+			HeadLatencyMs:  latency(headResult),
+		}
+		baseResult, ok := baseResults[key]
+		if !ok {
+			endpoint.Change = ChangeAdded
+			report.add(endpoint)
+			continue
+		}
+		endpoint.BaseStatus = baseResult.StatusCode
+		endpoint.BaseStatusCode = baseResult.ActualStatus
+		endpoint.BaseLatencyMs = latency(baseResult)
+		endpoint.StatusCodeChanged = baseResult.ActualStatus != headResult.ActualStatus
+		if endpoint.BaseLatencyMs != nil && endpoint.HeadLatencyMs != nil {
+			delta := *endpoint.HeadLatencyMs - *endpoint.BaseLatencyMs
+			endpoint.LatencyDeltaMs = &delta
+		}
+		switch {
+		case passed(baseResult) && !passed(headResult):
+			endpoint.Change = ChangeNewlyFailing
+		case !passed(baseResult) && passed(headResult):
+			endpoint.Change = ChangeNewlyPassing
+		case !passed(headResult):
+			endpoint.Change = ChangeStillFailing
+		default:
+			endpoint.Change = ChangeUnchanged
+		}
+		report.add(endpoint)
+	}
+
+	for _, key := range baseKeys {
+		if _, ok := headResults[key]; ok {
+			continue
+		}
+		baseResult := baseResults[key]
+		report.add(Endpoint{
+			Method:         key.method,
+			Path:           key.path,
+			Change:         ChangeRemoved,
+			BaseStatus:     baseResult.StatusCode,
+			BaseStatusCode: baseResult.ActualStatus,
+			BaseLatencyMs:  latency(baseResult),
+		})
+	}
+	return report
+}
+
+func runOf(result db.TestResult) Run {
+	return Run{ID: result.ID, Status: result.Status, BaseURL: result.BaseURL, CreatedAt: result.CreatedAt}
+}
+
+func (r *Report) add(endpoint Endpoint) {
+	r.Endpoints = append(r.Endpoints, endpoint)
+	if endpoint.StatusCodeChanged {
+		r.Summary.StatusCodeChanges++
+	}
+	switch endpoint.Change {
+	case ChangeNewlyFailing:
+		r.Summary.NewlyFailing++
+	case ChangeNewlyPassing:
+		r.Summary.NewlyPassing++
+	case ChangeStillFailing:
+		r.Summary.StillFailing++
+	case ChangeUnchanged:
+		r.Summary.Unchanged++
+	case ChangeAdded:
+		r.Summary.Added++
+	case ChangeRemoved:
+		r.Summary.Removed++
+	}
+}
@@ -0,0 +1,117 @@
+package compare
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	exec "github.com/jgfranco17/aeternum/execution"
+	"github.com/stretchr/testify/assert"
+)
+
+func check(method, path string, actual int, status exec.Status, totalMs float64) exec.CheckResult {
+	result := exec.CheckResult{Path: path, Method: method, ActualStatus: actual, StatusCode: string(status)}
+	if totalMs > 0 {
+		result.Timings = &exec.Timings{TotalMs: totalMs}
+	}
+	return result
+}
+
+func ms(value float64) *float64 {
+	return &value
+}
+
+func TestCompare(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	base := db.TestResult{
+		ID:        "run-1",
+		BaseURL:   "https://example.com",
+		Status:    exec.StatusFail,
+		CreatedAt: created,
+		Results: []exec.CheckResult{
+			check("GET", "/health", 200, exec.StatusPass, 40),
+			check("GET", "/users", 500, exec.StatusFail, 120),
+			check("GET", "/orders", 502, exec.StatusFail, 0),
+			check("GET", "/items", 200, exec.StatusPass, 30),
+			check("DELETE", "/legacy", 204, exec.StatusPass, 10),
+		},
+	}
+	head := db.TestResult{
+		ID:        "run-2",
+		BaseURL:   "https://example.com",
+		Status:    exec.StatusFail,
+		CreatedAt: created.Add(time.Hour),
+		Results: []exec.CheckResult{
+			check("get", "/health", 503, exec.StatusFail, 65),
+			check("GET", "/users", 200, exec.StatusPass, 100),
+			check("GET", "/orders", 504, exec.StatusFail, 0),
+			check("GET", "/items", 200, exec.StatusPass, 30),
+			check("POST", "/users", 201, exec.StatusPass, 80),
+		},
+	}
+
+	report := Compare(base, head)
+	assert.Equal(t, Run{ID: "run-1", Status: exec.StatusFail, BaseURL: "https://example.com", CreatedAt: created}, report.Base)
+	assert.Equal(t, "run-2", report.Head.ID)
+	assert.Equal(t, Summary{
+		NewlyFailing:      1,
+		NewlyPassing:      1,
+		StillFailing:      1,
+		Unchanged:         1,
+		Added:             1,
+		Removed:           1,
+		StatusCodeChanges: 3,
+	}, report.Summary)
+	assert.Equal(t, []Endpoint{
+		{
+			Method: "GET", Path: "/health", Change: ChangeNewlyFailing,
+			BaseStatus: "PASS", HeadStatus: "FAIL", BaseStatusCode: 200, HeadStatusCode: 503, StatusCodeChanged: true,
+			BaseLatencyMs: ms(40), HeadLatencyMs: ms(65), LatencyDeltaMs: ms(25),
+		},
+		{
+			Method: "GET", Path: "/users", Change: ChangeNewlyPassing,
+			BaseStatus: "FAIL", HeadStatus: "PASS", BaseStatusCode: 500, HeadStatusCode: 200, StatusCodeChanged: true,
+			BaseLatencyMs: ms(120), HeadLatencyMs: ms(100), LatencyDeltaMs: ms(-20),
+		},
+		{
+			Method: "GET", Path: "/orders", Change: ChangeStillFailing,
+			BaseStatus: "FAIL", HeadStatus: "FAIL", BaseStatusCode: 502, HeadStatusCode: 504, StatusCodeChanged: true,
+		},
+		{
+			Method: "GET", Path: "/items", Change: ChangeUnchanged,
+			BaseStatus: "PASS", HeadStatus: "PASS", BaseStatusCode: 200, HeadStatusCode: 200,
+			BaseLatencyMs: ms(30), HeadLatencyMs: ms(30), LatencyDeltaMs: ms(0),
+		},
+		{
+			Method: "POST", Path: "/users", Change: ChangeAdded,
+			HeadStatus: "PASS", HeadStatusCode: 201, HeadLatencyMs: ms(80),
+		},
+		{
+			Method: "DELETE", Path: "/legacy", Change: ChangeRemoved,
+			BaseStatus: "PASS", BaseStatusCode: 204, BaseLatencyMs: ms(10),
+		},
+	}, report.Endpoints)
+}
+
+func TestCompareRepeatedEndpoints(t *testing.T) {
+	base := db.TestResult{Results: []exec.CheckResult{
+		check("GET", "/health", 200, exec.StatusPass, 0),
+		check("GET", "/health", 200, exec.StatusPass, 0),
+	}}
+	head := db.TestResult{Results: []exec.CheckResult{
+		check("GET", "/health", 200, exec.StatusPass, 0),
+		check("GET", "/health", 500, exec.StatusFail, 0),
+		check("GET", "/health", 200, exec.StatusPass, 0),
+	}}
+
+	report := Compare(base, head)
+	assert.Equal(t, Summary{NewlyFailing: 1, Unchanged: 1, Added: 1, StatusCodeChanges: 1}, report.Summary)
+	assert.Equal(t, ChangeNewlyFailing, report.Endpoints[1].Change)
+}
+
+func TestCompareEmptyRuns(t *testing.T) {
+	report := Compare(db.TestResult{ID: "run-1"}, db.TestResult{ID: "run-2"})
+	assert.Equal(t, Summary{}, report.Summary)
+	assert.Empty(t, report.Endpoints)
+	assert.NotNil(t, report.Endpoints)
+}
@@ -14,8 +14,11 @@ const (
 	EnvVarMongoUser     string = "AETERNUM_MONGO_USER"
 	EnvVarMongoPassword string = "AETERNUM_MONGO_PASSWORD"
 	EnvVarMongoUri      string = "AETERNUM_MONGO_URI"
+	EnvVarMongoDatabase string = "AETERNUM_MONGO_DATABASE"
 )
 
+const defaultMongoDatabase = "aeternum"
+
 type GithubConfig interface {
 	GithubToken() string
 	GithubBaseUrl() string
@@ -26,6 +29,7 @@ type EnvironmentConfig struct {
 	EnvMongoUser     string
 	EnvMongoPassword string
 	EnvMongoUri      string
+	EnvMongoDatabase string
 }
 
 func (c *EnvironmentConfig) LogLevel() string {
@@ -44,6 +48,13 @@ func (c *EnvironmentConfig) MongoUri() string {
 	return c.EnvMongoUri
 }
 
+func (c *EnvironmentConfig) MongoDatabase() string {
+	if c.EnvMongoDatabase == "" {
+		return defaultMongoDatabase
+	}
+	return c.EnvMongoDatabase
+}
+
 func getMongoConfigs() (string, string, string, error) {
 	missingEnvVars := []string{}
 	username := environment.GetEnvWithDefault(EnvVarMongoUser, "")
@@ -75,6 +86,7 @@ func NewConfigFromSecrets() (*EnvironmentConfig, error) {
 		EnvMongoUser:     mongoUser,
 		EnvMongoPassword: mongoPassword,
 		EnvMongoUri:      mongoUri,
+		EnvMongoDatabase: environment.GetEnvWithDefault(EnvVarMongoDatabase, defaultMongoDatabase),
 	}
 	log.Infof("Loaded configs from environment")
 	return &config, nil
@@ -19,6 +19,12 @@ func TestLoadConfigCompleteConfig(t *testing.T) {
 	assert.Equal(t, "my-user", config.MongoUser())
 	assert.Equal(t, "mongo-token", config.MongoPassword())
 	assert.Equal(t, "some-uri", config.MongoUri())
+	assert.Equal(t, "aeternum", config.MongoDatabase())
+
+	t.Setenv("AETERNUM_MONGO_DATABASE", "monitoring")
+	config, err = NewConfigFromSecrets()
+	assert.NoError(t, err)
+	assert.Equal(t, "monitoring", config.MongoDatabase())
 }
 
 func TestLoadConfigMissingMongoConfigs(t *testing.T) {
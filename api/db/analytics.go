@@ -0,0 +1,180 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/logging"
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+// EndpointHealth aggregates the checks of one endpoint across the runs of a
+// time window. A run counts once per endpoint, as passing only if every
+// check of the endpoint in it passed; checks that were cancelled or skipped
+// are left out.
+type EndpointHealth struct {
+	BaseURL  string  `json:"base_url"`
+	Method   string  `json:"method"`
+	Path     string  `json:"path"`
+	Runs     int     `json:"runs"`
+	Passed   int     `json:"passed"`
+	PassRate float64 `json:"pass_rate"`
+	// Flips counts the PASS to FAIL and FAIL to PASS transitions between
+	// consecutive runs, and FlipRate is their share of all transitions
+	Flips                int     `json:"flips"`
+	FlipRate             float64 `json:"flip_rate"`
+	LongestFailingStreak int     `json:"longest_failing_streak"`
+	Recoveries           int     `json:"recoveries"`
+	// MeanTimeToRecoverySeconds is the mean time from the first failing run of a
+	// streak to the next passing run, nil if the endpoint never recovered
+	MeanTimeToRecoverySeconds *float64    `json:"mean_time_to_recovery_seconds"`
+	LastStatus                exec.Status `json:"last_status"`
+	LastCheckedAt             time.Time   `json:"last_checked_at"`
+	// Flaky is left for callers to set from FlipRate
+	Flaky bool `json:"flaky"`
+}
+
+type endpoint struct {
+	baseURL string
+	method  string
+	path    string
+}
+
+func endpointOf(baseURL string, check exec.CheckResult) endpoint {
+	method := strings.ToUpper(check.Method)
+	if method == "" {
+		method = "GET"
+	}
+	return endpoint{baseURL: baseURL, method: method, path: check.Path}
+}
+
+// countsTowardsHealth reports whether a check has an outcome
+func countsTowardsHealth(check exec.CheckResult) bool {
+	switch exec.Status(check.StatusCode) {
+	case exec.StatusPass, exec.StatusFail, exec.StatusError:
+		return true
+	}
+	return false
+}
+
+// recoverySeconds rounds to the millisecond, the precision SQLite computes
+// durations with
+func recoverySeconds(failedAt, recoveredAt time.Time) float64 {
+	return math.Round(recoveredAt.Sub(failedAt).Seconds()*1000) / 1000
+}
+
+// endpointHealth aggregates the checks of results in Go, for backends that
+// cannot run the aggregation themselves
+func endpointHealth(results []TestResult) []EndpointHealth {
+	results = slices.Clone(results)
+	slices.SortFunc(results, func(a, b TestResult) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID, b.ID))
+	})
+
+	type history struct {
+		health     EndpointHealth
+		failedAt   *time.Time
+		streak     int
+		recoveries float64
+	}
+	histories := map[endpoint]*history{}
+	for _, result := range results {
+		passed := map[endpoint]bool{}
+		order := []endpoint{}
+		for _, check := range result.Results {
+			if !countsTowardsHealth(check) {
+				continue
+			}
+			key := endpointOf(result.BaseURL, check)
+			previous, seen := passed[key]
+			if !seen {
+				order = append(order, key)
+				previous = true
+			}
+			passed[key] = previous && exec.Status(check.StatusCode) == exec.StatusPass
+		}
+
+		for _, key := range order {
+			h, ok := histories[key]
+			if !ok {
+				h = &history{health: EndpointHealth{BaseURL: key.baseURL, Method: key.method, Path: key.path}}
+				histories[key] = h
+			}
+			status := exec.StatusFail
+			if passed[key] {
+				status = exec.StatusPass
+				h.health.Passed++
+			}
+			if h.health.Runs > 0 && status != h.health.LastStatus {
+				h.health.Flips++
+			}
+			if status == exec.StatusFail {
+				if h.failedAt == nil {
+					failedAt := result.CreatedAt
+					h.failedAt = &failedAt
+				}
+				h.streak++
+				h.health.LongestFailingStreak = max(h.health.LongestFailingStreak, h.streak)
+			} else if h.failedAt != nil {
+				h.health.Recoveries++
+				h.recoveries += recoverySeconds(*h.failedAt, result.CreatedAt)
+				h.failedAt = nil
+				h.streak = 0
+			}
+			h.health.Runs++
+			h.health.LastStatus = status
+			h.health.LastCheckedAt = result.CreatedAt
+		}
+	}
+
+	health := make([]EndpointHealth, 0, len(histories))
+	for _, h := range histories {
+		if h.health.Recoveries > 0 {
+			mean := h.recoveries / float64(h.health.Recoveries)
+			h.health.MeanTimeToRecoverySeconds = &mean
+		}
+		health = append(health, h.health)
+	}
+	return sortedHealth(health)
+}
+
+// sortedHealth derives the rates of each endpoint and orders them by base
+// URL, path and method
+func sortedHealth(health []EndpointHealth) []EndpointHealth {
+	for i := range health {
+		if health[i].Runs > 0 {
+			health[i].PassRate = float64(health[i].Passed) / float64(health[i].Runs)
+		}
+		if health[i].Runs > 1 {
+			health[i].FlipRate = float64(health[i].Flips) / float64(health[i].Runs-1)
+		}
+	}
+	slices.SortFunc(health, func(a, b EndpointHealth) int {
+		return cmp.Or(strings.Compare(a.BaseURL, b.BaseURL), strings.Compare(a.Path, b.Path), strings.Compare(a.Method, b.Method))
+	})
+	return health
+}
+
+// GetEndpointHealth aggregates the checks of the test results of a user
+// matching the filters of a query by endpoint. PostgREST cannot run the
+// aggregation, so the matching results are fetched and aggregated in Go.
+func (s *SupabaseClient) GetEndpointHealth(ctx context.Context, userID string, query ResultQuery) ([]EndpointHealth, error) {
+	log := logging.FromContext(ctx)
+
+	// PostgREST caps the rows of a single response, so the results are read
+	// a page at a time
+	results, err := allPages(query, func(query ResultQuery) (*ResultPage, error) {
+		return s.resultPage(userID, "id,base_url,results,created_at", query)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve test results for endpoint health: %w", err)
+	}
+
+	log.Infof("Aggregating endpoint health of %d test results for user: %s", len(results), userID)
+	return endpointHealth(results), nil
+}
@@ -0,0 +1,557 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/configs"
+	exec "github.com/jgfranco17/aeternum/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// backends returns every storage backend that can run in this environment.
+// PostgreSQL is only tested when AETERNUM_TEST_POSTGRES_DSN points at a
+// disposable database, and MongoDB when AETERNUM_TEST_MONGO_URI points at a
+// deployment whose aeternum_test database can be dropped.
+func backends(t *testing.T) map[string]func(t *testing.T) DatabaseClient {
+	clients := map[string]func(t *testing.T) DatabaseClient{
+		DriverMemory: func(t *testing.T) DatabaseClient {
+			return NewMemoryClient()
+		},
+		DriverSQLite: func(t *testing.T) DatabaseClient {
+			client, err := NewSQLiteClient(context.Background(), filepath.Join(t.TempDir(), "aeternum.db"))
+			require.NoError(t, err)
+			t.Cleanup(func() { client.Close() })
+			_, err = client.MigrateUp(context.Background())
+			require.NoError(t, err)
+			return client
+		},
+	}
+	if dsn := os.Getenv("AETERNUM_TEST_POSTGRES_DSN"); dsn != "" {
+		clients[DriverPostgres] = func(t *testing.T) DatabaseClient {
+			client, err := NewPostgresClient(context.Background(), dsn)
+			require.NoError(t, err)
+			_, err = client.MigrateUp(context.Background())
+			require.NoError(t, err)
+			for _, table := range []string{"test_results", "test_suites", "monitors", "webhooks", "webhook_deliveries", "chat_destinations"} {
+				_, err := client.exec(context.Background(), "DELETE FROM "+table)
+				require.NoError(t, err)
+			}
+			t.Cleanup(func() { client.Close() })
+			return client
+		}
+	}
+	if uri := os.Getenv("AETERNUM_TEST_MONGO_URI"); uri != "" {
+		clients[DriverMongo] = func(t *testing.T) DatabaseClient {
+			config := &configs.EnvironmentConfig{EnvMongoUri: uri, EnvMongoDatabase: "aeternum_test"}
+			client, err := NewMongoClient(context.Background(), config)
+			require.NoError(t, err)
+			require.NoError(t, client.database.Drop(context.Background()))
+			require.NoError(t, client.ensureIndexes(context.Background()))
+			t.Cleanup(func() {
+				client.database.Drop(context.Background())
+				client.Close()
+			})
+			return client
+		}
+	}
+	return clients
+}
+
+func TestBackendsImplementDatabaseClient(t *testing.T) {
+	var _ DatabaseClient = (*SQLClient)(nil)
+	var _ DatabaseClient = (*MemoryClient)(nil)
+	var _ DatabaseClient = (*MongoClient)(nil)
+}
+
+func TestBackendTestResults(t *testing.T) {
+	for name, newClient := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			client := newClient(t)
+
+			first := &exec.CheckResponse{
+				RequestID: "run-1",
+				BaseURL:   "https://example.com",
+				Status:    exec.StatusRunning,
+				Results:   []exec.CheckResult{},
+			}
+			require.NoError(t, client.StoreTestResult(ctx, "user", first))
+			first.Status = exec.StatusFail
+			first.Results = []exec.CheckResult{{Path: "/health", ExpectedStatus: 200, ActualStatus: 500, StatusCode: "FAIL"}}
+			require.NoError(t, client.UpdateTestResult(ctx, "user", first))
+			time.Sleep(5 * time.Millisecond)
+			require.NoError(t, client.StoreTestResult(ctx, "user", &exec.CheckResponse{
+				RequestID: "run-2",
+				SuiteID:   "suite",
+				BaseURL:   "https://example.com",
+				Status:    exec.StatusPass,
+				Scenarios: []exec.ScenarioResult{{Name: "login", Status: exec.StatusPass, Steps: []exec.StepResult{}}},
+			}))
+
+			stored, err := client.GetTestResult(ctx, "user", "run-1")
+			require.NoError(t, err)
+			assert.Equal(t, exec.StatusFail, stored.Status)
+			require.Len(t, stored.Results, 1)
+			assert.Equal(t, 500, stored.Results[0].ActualStatus)
+			assert.Equal(t, float64(1), stored.Metadata["failed_count"])
+
+			_, err = client.GetTestResult(ctx, "other-user", "run-1")
+			assert.ErrorIs(t, err, ErrTestResultNotFound)
+
+			history, err := client.GetUserTestResults(ctx, "user", ResultQuery{})
+			require.NoError(t, err)
+			require.Len(t, history.Results, 2)
+			assert.Equal(t, "run-2", history.Results[0].RequestID)
+			require.Len(t, history.Results[0].Scenarios, 1)
+			assert.Empty(t, history.NextCursor)
+
+			limited, err := client.GetUserTestResults(ctx, "user", ResultQuery{Limit: 1})
+			require.NoError(t, err)
+			assert.Len(t, limited.Results, 1)
+			assert.NotEmpty(t, limited.NextCursor)
+
+			suiteHistory, err := client.GetSuiteTestResults(ctx, "user", "suite", 10)
+			require.NoError(t, err)
+			require.Len(t, suiteHistory, 1)
+			assert.Equal(t, "run-2", suiteHistory[0].RequestID)
+		})
+	}
+}
+
+func TestBackendTestHistory(t *testing.T) {
+	for name, newClient := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			client := newClient(t)
+
+			runs := []exec.CheckResponse{
+				{RequestID: "run-a", BaseURL: "https://a.example.com", Status: exec.StatusPass, Tags: []string{"prod", "smoke"}},
+				{RequestID: "run-b", BaseURL: "https://a.example.com", Status: exec.StatusFail, Tags: []string{"prod"}},
+				{RequestID: "run-c", BaseURL: "https://b.example.com", Status: exec.StatusError, SuiteID: "suite"},
+				{RequestID: "run-d", BaseURL: "https://a.example.com", Status: exec.StatusFail, Tags: []string{"staging", "smoke"}},
+				{RequestID: "run-e", BaseURL: "https://b.example.com", Status: exec.StatusPass, SuiteID: "suite"},
+			}
+			var middle time.Time
+			for i := range runs {
+				runs[i].Results = []exec.CheckResult{}
+				require.NoError(t, client.StoreTestResult(ctx, "user", &runs[i]))
+				time.Sleep(5 * time.Millisecond)
+				if i == 2 {
+					middle = time.Now()
+					time.Sleep(5 * time.Millisecond)
+				}
+			}
+			require.NoError(t, client.StoreTestResult(ctx, "other-user", &exec.CheckResponse{RequestID: "run-x", BaseURL: "https://a.example.com", Status: exec.StatusPass}))
+
+			ids := func(query ResultQuery) []string {
+				page, err := client.GetUserTestResults(ctx, "user", query)
+				require.NoError(t, err)
+				found := []string{}
+				for _, result := range page.Results {
+					found = append(found, result.RequestID)
+				}
+				return found
+			}
+
+			assert.Equal(t, []string{"run-e", "run-d", "run-c", "run-b", "run-a"}, ids(ResultQuery{}))
+			assert.Equal(t, []string{"run-a", "run-b", "run-c", "run-d", "run-e"}, ids(ResultQuery{Ascending: true}))
+			assert.Equal(t, []string{"run-d", "run-b"}, ids(ResultQuery{Statuses: []exec.Status{exec.StatusFail}}))
+			assert.Equal(t, []string{"run-e", "run-c", "run-a"}, ids(ResultQuery{Statuses: []exec.Status{exec.StatusPass, exec.StatusError}}))
+			assert.Equal(t, []string{"run-e", "run-c"}, ids(ResultQuery{BaseURL: "https://b.example.com"}))
+			assert.Equal(t, []string{"run-e", "run-c"}, ids(ResultQuery{SuiteID: "suite"}))
+			assert.Equal(t, []string{"run-d", "run-a"}, ids(ResultQuery{Tags: []string{"smoke"}}))
+			assert.Equal(t, []string{"run-a"}, ids(ResultQuery{Tags: []string{"smoke", "prod"}}))
+			assert.Equal(t, []string{"run-e", "run-d"}, ids(ResultQuery{CreatedFrom: &middle}))
+			assert.Equal(t, []string{"run-c", "run-b", "run-a"}, ids(ResultQuery{CreatedTo: &middle}))
+
+			tagged, err := client.GetTestResult(ctx, "user", "run-a")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"prod", "smoke"}, tagged.Tags)
+
+			for _, ascending := range []bool{false, true} {
+				query := ResultQuery{Limit: 2, Ascending: ascending, Statuses: []exec.Status{exec.StatusPass, exec.StatusFail, exec.StatusError}}
+				paged := []string{}
+				for pages := 0; pages < 5; pages++ {
+					page, err := client.GetUserTestResults(ctx, "user", query)
+					require.NoError(t, err)
+					assert.LessOrEqual(t, len(page.Results), 2)
+					for _, result := range page.Results {
+						paged = append(paged, result.RequestID)
+					}
+					if page.NextCursor == "" {
+						break
+					}
+					query.Cursor = page.NextCursor
+				}
+				assert.Equal(t, ids(ResultQuery{Ascending: ascending}), paged, "pages cover every result once")
+			}
+
+			_, err = client.GetUserTestResults(ctx, "user", ResultQuery{Cursor: "not-a-cursor"})
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+
+			pageSize := resultPageSize
+			t.Cleanup(func() { resultPageSize = pageSize })
+			resultPageSize = 2
+			all, err := AllTestResults(ctx, client, "user", ResultQuery{Limit: 1, Ascending: true})
+			require.NoError(t, err)
+			found := []string{}
+			for _, result := range all {
+				found = append(found, result.RequestID)
+			}
+			assert.Equal(t, []string{"run-a", "run-b", "run-c", "run-d", "run-e"}, found)
+		})
+	}
+}
+
+func TestBackendEndpointHealth(t *testing.T) {
+	for name, newClient := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			client := newClient(t)
+
+			check := func(method, path string, status exec.Status) exec.CheckResult {
+				return exec.CheckResult{Method: method, Path: path, StatusCode: string(status)}
+			}
+			runs := []exec.CheckResponse{
+				{BaseURL: "https://a.example.com", Results: []exec.CheckResult{
+					check("GET", "/health", exec.StatusPass), check("GET", "/users", exec.StatusFail), check("POST", "/users", exec.StatusPass),
+				}},
+				{BaseURL: "https://a.example.com", Results: []exec.CheckResult{
+					check("GET", "/health", exec.StatusError), check("GET", "/users", exec.StatusPass),
+					check("post", "/users", exec.StatusPass), check("GET", "/health", exec.StatusPass),
+				}},
+				{BaseURL: "https://a.example.com", Results: []exec.CheckResult{
+					check("GET", "/health", exec.StatusFail), check("GET", "/users", exec.StatusFail), check("POST", "/users", exec.StatusCancelled),
+				}},
+				{BaseURL: "https://a.example.com", Results: []exec.CheckResult{
+					check("GET", "/health", exec.StatusPass), check("GET", "/users", exec.StatusFail),
+				}},
+				{BaseURL: "https://a.example.com", Results: []exec.CheckResult{
+					check("GET", "/health", exec.StatusPass), check("GET", "/users", exec.StatusPass),
+				}},
+				{BaseURL: "https://b.example.com", Results: []exec.CheckResult{check("GET", "/health", exec.StatusFail)}},
+			}
+			created := make([]time.Time, len(runs))
+			for i := range runs {
+				runs[i].RequestID = fmt.Sprintf("run-%d", i+1)
+				runs[i].Status = exec.StatusFail
+				require.NoError(t, client.StoreTestResult(ctx, "user", &runs[i]))
+				stored, err := client.GetTestResult(ctx, "user", runs[i].RequestID)
+				require.NoError(t, err)
+				created[i] = stored.CreatedAt
+				time.Sleep(5 * time.Millisecond)
+			}
+			require.NoError(t, client.StoreTestResult(ctx, "other-user", &exec.CheckResponse{
+				RequestID: "run-x", BaseURL: "https://a.example.com", Status: exec.StatusFail,
+				Results: []exec.CheckResult{check("GET", "/health", exec.StatusFail)},
+			}))
+
+			health, err := client.GetEndpointHealth(ctx, "user", ResultQuery{})
+			require.NoError(t, err)
+			require.Len(t, health, 4)
+			seconds := func(from, to int) float64 { return created[to].Sub(created[from]).Seconds() }
+
+			assertHealth := func(expected EndpointHealth, mttr *float64, actual EndpointHealth) {
+				if mttr == nil {
+					assert.Nil(t, actual.MeanTimeToRecoverySeconds)
+				} else if assert.NotNil(t, actual.MeanTimeToRecoverySeconds) {
+					assert.InDelta(t, *mttr, *actual.MeanTimeToRecoverySeconds, 0.01)
+				}
+				assert.True(t, expected.LastCheckedAt.Equal(actual.LastCheckedAt), "last checked %s, got %s", expected.LastCheckedAt, actual.LastCheckedAt)
+				actual.MeanTimeToRecoverySeconds = nil
+				expected.LastCheckedAt, actual.LastCheckedAt = time.Time{}, time.Time{}
+				assert.Equal(t, expected, actual)
+			}
+			healthMTTR := seconds(1, 3)
+			assertHealth(EndpointHealth{
+				BaseURL: "https://a.example.com", Method: "GET", Path: "/health",
+				Runs: 5, Passed: 3, PassRate: 0.6, Flips: 2, FlipRate: 0.5, LongestFailingStreak: 2, Recoveries: 1,
+				LastStatus: exec.StatusPass, LastCheckedAt: created[4],
+			}, &healthMTTR, health[0])
+			usersMTTR := (seconds(0, 1) + seconds(2, 4)) / 2
+			assertHealth(EndpointHealth{
+				BaseURL: "https://a.example.com", Method: "GET", Path: "/users",
+				Runs: 5, Passed: 2, PassRate: 0.4, Flips: 3, FlipRate: 0.75, LongestFailingStreak: 2, Recoveries: 2,
+				LastStatus: exec.StatusPass, LastCheckedAt: created[4],
+			}, &usersMTTR, health[1])
+			assertHealth(EndpointHealth{
+				BaseURL: "https://a.example.com", Method: "POST", Path: "/users",
+				Runs: 2, Passed: 2, PassRate: 1, LastStatus: exec.StatusPass, LastCheckedAt: created[1],
+			}, nil, health[2])
+			assertHealth(EndpointHealth{
+				BaseURL: "https://b.example.com", Method: "GET", Path: "/health",
+				Runs: 1, LongestFailingStreak: 1, LastStatus: exec.StatusFail, LastCheckedAt: created[5],
+			}, nil, health[3])
+
+			windowed, err := client.GetEndpointHealth(ctx, "user", ResultQuery{BaseURL: "https://a.example.com", CreatedFrom: &created[2]})
+			require.NoError(t, err)
+			require.Len(t, windowed, 2)
+			assert.Equal(t, 3, windowed[0].Runs)
+			assert.Equal(t, 1, windowed[0].LongestFailingStreak)
+			assert.Equal(t, 1, windowed[1].Recoveries)
+
+			empty, err := client.GetEndpointHealth(ctx, "nobody", ResultQuery{})
+			require.NoError(t, err)
+			assert.Empty(t, empty)
+		})
+	}
+}
+
+func TestBackendDeleteTestResults(t *testing.T) {
+	for name, newClient := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			client := newClient(t)
+
+			store := func(userID, requestID, suiteID string, status exec.Status) {
+				require.NoError(t, client.StoreTestResult(ctx, userID, &exec.CheckResponse{
+					RequestID: requestID, SuiteID: suiteID, BaseURL: "https://example.com", Status: status, Results: []exec.CheckResult{},
+				}))
+				time.Sleep(2 * time.Millisecond)
+			}
+			remaining := func(userID string) []string {
+				page, err := client.GetUserTestResults(ctx, userID, ResultQuery{Ascending: true})
+				require.NoError(t, err)
+				ids := []string{}
+				for _, result := range page.Results {
+					ids = append(ids, result.RequestID)
+				}
+				return ids
+			}
+
+			store("user", "a-1", "a", exec.StatusFail)
+			store("user", "a-2", "a", exec.StatusPass)
+			store("other-user", "x-1", "a", exec.StatusFail)
+			store("user", "b-1", "b", exec.StatusFail)
+			cutoff := time.Now()
+			time.Sleep(2 * time.Millisecond)
+			store("user", "a-3", "a", exec.StatusPass)
+			store("user", "a-4", "a", exec.StatusFail)
+			store("user", "adhoc", "", exec.StatusFail)
+
+			require.NoError(t, client.DeleteTestResult(ctx, "user", "adhoc"))
+			assert.ErrorIs(t, client.DeleteTestResult(ctx, "user", "adhoc"), ErrTestResultNotFound)
+			assert.ErrorIs(t, client.DeleteTestResult(ctx, "user", "x-1"), ErrTestResultNotFound, "results of other users are not deleted")
+
+			trimmed, err := client.TrimSuiteTestResults(ctx, 2)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), trimmed)
+			assert.Equal(t, []string{"b-1", "a-3", "a-4"}, remaining("user"))
+			assert.Equal(t, []string{"x-1"}, remaining("other-user"), "suites are trimmed per user")
+
+			deleted, err := client.DeleteTestResults(ctx, "user", ResultQuery{Statuses: []exec.Status{exec.StatusFail}, SuiteID: "a"})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), deleted)
+			assert.Equal(t, []string{"b-1", "a-3"}, remaining("user"))
+			assert.Equal(t, []string{"x-1"}, remaining("other-user"))
+
+			expired, err := client.DeleteTestResultsBefore(ctx, cutoff)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), expired)
+			assert.Equal(t, []string{"a-3"}, remaining("user"))
+			assert.Empty(t, remaining("other-user"))
+		})
+	}
+}
+
+func TestBackendSuites(t *testing.T) {
+	for name, newClient := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			client := newClient(t)
+
+			created, err := client.CreateSuite(ctx, "user", &Suite{
+				Name:    "smoke",
+				Request: exec.TestExecutionRequest{BaseURL: "https://example.com", Endpoints: []exec.Endpoint{{Path: "/health"}}},
+			})
+			require.NoError(t, err)
+			assert.NotEmpty(t, created.ID)
+
+			created.Name = "renamed"
+			_, err = client.UpdateSuite(ctx, "user", created)
+			require.NoError(t, err)
+
+			previous, err := client.SetSuiteStatus(ctx, "user", created.ID, exec.StatusFail)
+			require.NoError(t, err)
+			assert.Equal(t, exec.Status(""), previous)
+			previous, err = client.SetSuiteStatus(ctx, "user", created.ID, exec.StatusPass)
+			require.NoError(t, err)
+			assert.Equal(t, exec.StatusFail, previous)
+
+			// Runs finishing together see the change of status only once
+			var wg sync.WaitGroup
+			changes := make(chan exec.Status, 8)
+			for range 8 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					previous, err := client.SetSuiteStatus(ctx, "user", created.ID, exec.StatusFail)
+					assert.NoError(t, err)
+					changes <- previous
+				}()
+			}
+			wg.Wait()
+			close(changes)
+			passed := 0
+			for previous := range changes {
+				if previous == exec.StatusPass {
+					passed++
+				}
+			}
+			assert.Equal(t, 1, passed)
+			_, err = client.SetSuiteStatus(ctx, "user", created.ID, exec.StatusPass)
+			require.NoError(t, err)
+
+			stored, err := client.GetSuite(ctx, "user", created.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "renamed", stored.Name)
+			assert.Equal(t, exec.StatusPass, stored.LastStatus)
+			assert.Equal(t, "/health", stored.Request.Endpoints[0].Path)
+
+			suites, err := client.ListSuites(ctx, "user")
+			require.NoError(t, err)
+			assert.Len(t, suites, 1)
+
+			_, err = client.GetSuite(ctx, "other-user", created.ID)
+			assert.ErrorIs(t, err, ErrSuiteNotFound)
+			require.NoError(t, client.DeleteSuite(ctx, "user", created.ID))
+			assert.ErrorIs(t, client.DeleteSuite(ctx, "user", created.ID), ErrSuiteNotFound)
+		})
+	}
+}
+
+func TestBackendMonitors(t *testing.T) {
+	for name, newClient := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			client := newClient(t)
+			now := time.Now().UTC().Truncate(time.Second)
+
+			due, err := client.CreateMonitor(ctx, "user", &Monitor{SuiteID: "suite", Name: "due", IntervalSeconds: 60, NextRunAt: now.Add(-time.Minute)})
+			require.NoError(t, err)
+			_, err = client.CreateMonitor(ctx, "user", &Monitor{SuiteID: "suite", Name: "later", IntervalSeconds: 60, NextRunAt: now.Add(time.Hour)})
+			require.NoError(t, err)
+			_, err = client.CreateMonitor(ctx, "user", &Monitor{SuiteID: "suite", Name: "paused", IntervalSeconds: 60, Paused: true, NextRunAt: now.Add(-time.Hour)})
+			require.NoError(t, err)
+
+			monitors, err := client.ListDueMonitors(ctx, now)
+			require.NoError(t, err)
+			require.Len(t, monitors, 1)
+			assert.Equal(t, due.ID, monitors[0].ID)
+			assert.True(t, monitors[0].NextRunAt.Equal(due.NextRunAt))
+
+			claimed, err := client.ClaimMonitorRun(ctx, &monitors[0], now.Add(time.Minute))
+			require.NoError(t, err)
+			assert.True(t, claimed)
+			claimed, err = client.ClaimMonitorRun(ctx, &monitors[0], now.Add(time.Minute))
+			require.NoError(t, err)
+			assert.False(t, claimed, "a run can only be claimed once")
+
+			stored, err := client.GetMonitor(ctx, "user", due.ID)
+			require.NoError(t, err)
+			assert.NotNil(t, stored.LastRunAt)
+			assert.True(t, stored.NextRunAt.Equal(now.Add(time.Minute)))
+
+			stored.Paused = true
+			_, err = client.UpdateMonitor(ctx, "user", stored)
+			require.NoError(t, err)
+			monitors, err = client.ListDueMonitors(ctx, now.Add(time.Hour))
+			require.NoError(t, err)
+			assert.Len(t, monitors, 1, "only the later monitor is due once the first is paused")
+
+			_, err = client.UpdateMonitor(ctx, "other-user", stored)
+			assert.ErrorIs(t, err, ErrMonitorNotFound)
+			all, err := client.ListMonitors(ctx, "user")
+			require.NoError(t, err)
+			assert.Len(t, all, 3)
+			require.NoError(t, client.DeleteMonitor(ctx, "user", due.ID))
+			assert.ErrorIs(t, client.DeleteMonitor(ctx, "user", due.ID), ErrMonitorNotFound)
+		})
+	}
+}
+
+func TestBackendWebhooksAndChat(t *testing.T) {
+	for name, newClient := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			client := newClient(t)
+
+			webhook, err := client.CreateWebhook(ctx, "user", &Webhook{URL: "https://hooks.example.com", Secret: "secret", Events: []string{"run.completed"}, Active: true})
+			require.NoError(t, err)
+			_, err = client.UpdateWebhook(ctx, "user", &Webhook{ID: webhook.ID, URL: "https://hooks.example.com/v2", Active: false})
+			require.NoError(t, err)
+			stored, err := client.GetWebhook(ctx, "user", webhook.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "https://hooks.example.com/v2", stored.URL)
+			assert.Equal(t, "secret", stored.Secret, "the secret is kept when none is given")
+			assert.Empty(t, stored.Events)
+			assert.False(t, stored.Active)
+
+			for attempt := 1; attempt <= 3; attempt++ {
+				require.NoError(t, client.StoreWebhookDelivery(ctx, &WebhookDelivery{
+					WebhookID: webhook.ID,
+					UserID:    "user",
+					Event:     "run.completed",
+					Attempt:   attempt,
+					Success:   attempt == 3,
+					CreatedAt: time.Now().UTC().Add(time.Duration(attempt) * time.Second),
+				}))
+			}
+			deliveries, err := client.ListWebhookDeliveries(ctx, "user", webhook.ID, 2)
+			require.NoError(t, err)
+			require.Len(t, deliveries, 2)
+			assert.Equal(t, 3, deliveries[0].Attempt)
+			assert.True(t, deliveries[0].Success)
+
+			webhooks, err := client.ListWebhooks(ctx, "user")
+			require.NoError(t, err)
+			assert.Len(t, webhooks, 1)
+			require.NoError(t, client.DeleteWebhook(ctx, "user", webhook.ID))
+			assert.ErrorIs(t, client.DeleteWebhook(ctx, "user", webhook.ID), ErrWebhookNotFound)
+
+			destination, err := client.CreateChatDestination(ctx, "user", &ChatDestination{Name: "ops", Platform: "slack", WebhookURL: "https://hooks.slack.com/x"})
+			require.NoError(t, err)
+			fetched, err := client.GetChatDestination(ctx, "user", destination.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "slack", fetched.Platform)
+			destinations, err := client.ListChatDestinations(ctx, "user")
+			require.NoError(t, err)
+			assert.Len(t, destinations, 1)
+			require.NoError(t, client.DeleteChatDestination(ctx, "user", destination.ID))
+			_, err = client.GetChatDestination(ctx, "user", destination.ID)
+			assert.ErrorIs(t, err, ErrChatDestinationNotFound)
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	client, err := Open(context.Background(), Config{Driver: DriverMemory})
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryClient{}, client)
+
+	client, err = Open(context.Background(), Config{Driver: DriverSQLite, DSN: filepath.Join(t.TempDir(), "open.db")})
+	require.NoError(t, err)
+	assert.IsType(t, &SQLClient{}, client)
+	client.(*SQLClient).Close()
+
+	t.Setenv(configs.EnvVarMongoUri, "")
+	_, err = Open(context.Background(), Config{Driver: DriverMongo})
+	assert.ErrorContains(t, err, configs.EnvVarMongoUri)
+
+	_, err = Open(context.Background(), Config{Driver: "oracle"})
+	assert.ErrorContains(t, err, "unsupported database driver")
+}
+
+func TestRebind(t *testing.T) {
+	postgres := &SQLClient{numbered: true}
+	assert.Equal(t, "SELECT * FROM t WHERE a = $1 AND b = $2", postgres.rebind("SELECT * FROM t WHERE a = ? AND b = ?"))
+	sqlite := &SQLClient{}
+	assert.Equal(t, "SELECT * FROM t WHERE a = ?", sqlite.rebind("SELECT * FROM t WHERE a = ?"))
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jgfranco17/aeternum/api/logging"
+)
+
+// ChatDestination is a chat channel that alerts are posted to, for all of
+// a user's runs or only for the runs of one suite
+type ChatDestination struct {
+	ID         string    `json:"id" bson:"_id"`
+	UserID     string    `json:"user_id"`
+	SuiteID    string    `json:"suite_id,omitempty"`
+	Name       string    `json:"name"`
+	Platform   string    `json:"platform"`
+	WebhookURL string    `json:"webhook_url"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateChatDestination stores a new chat destination, assigning its ID
+func (s *SupabaseClient) CreateChatDestination(ctx context.Context, userID string, destination *ChatDestination) (*ChatDestination, error) {
+	log := logging.FromContext(ctx)
+
+	created := *destination
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = time.Now()
+
+	_, _, err := s.client.From("chat_destinations").Insert(created, false, "", "", "").Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to store chat destination: %w", err)
+	}
+
+	log.Infof("Successfully created chat destination with ID: %s", created.ID)
+	return &created, nil
+}
+
+// GetChatDestination retrieves a chat destination by ID
+func (s *SupabaseClient) GetChatDestination(ctx context.Context, userID, destinationID string) (*ChatDestination, error) {
+	data, _, err := s.client.From("chat_destinations").
+		Select("*", "exact", false).
+		Eq("id", destinationID).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve chat destination: %w", err)
+	}
+
+	var destinations []ChatDestination
+	if err := json.Unmarshal(data, &destinations); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chat destination: %w", err)
+	}
+	if len(destinations) == 0 {
+		return nil, ErrChatDestinationNotFound
+	}
+	return &destinations[0], nil
+}
+
+// ListChatDestinations retrieves all chat destinations for a user
+func (s *SupabaseClient) ListChatDestinations(ctx context.Context, userID string) ([]ChatDestination, error) {
+	data, _, err := s.client.From("chat_destinations").
+		Select("*", "exact", false).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve chat destinations: %w", err)
+	}
+
+	destinations := []ChatDestination{}
+	if err := json.Unmarshal(data, &destinations); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chat destinations: %w", err)
+	}
+	return destinations, nil
+}
+
+// DeleteChatDestination removes a chat destination by ID
+func (s *SupabaseClient) DeleteChatDestination(ctx context.Context, userID, destinationID string) error {
+	log := logging.FromContext(ctx)
+
+	data, _, err := s.client.From("chat_destinations").
+		Delete("representation", "").
+		Eq("id", destinationID).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to delete chat destination: %w", err)
+	}
+
+	var deleted []ChatDestination
+	if err := json.Unmarshal(data, &deleted); err == nil && len(deleted) == 0 {
+		return ErrChatDestinationNotFound
+	}
+
+	log.Infof("Successfully deleted chat destination with ID: %s", destinationID)
+	return nil
+}
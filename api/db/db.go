@@ -3,23 +3,43 @@ package db
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/jgfranco17/aeternum/api/environment"
 	"github.com/jgfranco17/aeternum/api/logging"
 	"github.com/jgfranco17/aeternum/execution"
 	exec "github.com/jgfranco17/aeternum/execution"
+	"github.com/supabase-community/postgrest-go"
 	supabase "github.com/supabase-community/supabase-go"
 )
 
+// trimBatchSize is the most results of a suite deleted per retention sweep
+// on Supabase; anything left over is removed by the following sweeps
+const trimBatchSize = 500
+
+var (
+	ErrTestResultNotFound      = errors.New("test result not found")
+	ErrSuiteNotFound           = errors.New("suite not found")
+	ErrMonitorNotFound         = errors.New("monitor not found")
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrChatDestinationNotFound = errors.New("chat destination not found")
+)
+
 // TestResult represents a stored test execution result
 type TestResult struct {
-	ID        string                 `json:"id"`
+	ID        string                 `json:"id" bson:"_id"`
 	UserID    string                 `json:"user_id"`
 	RequestID string                 `json:"request_id"`
+	SuiteID   string                 `json:"suite_id,omitempty"`
 	BaseURL   string                 `json:"base_url"`
 	Status    execution.Status       `json:"status"`
 	Results   []exec.CheckResult     `json:"results"`
+	Scenarios []exec.ScenarioResult  `json:"scenarios,omitempty"`
+	Tags      []string               `json:"tags,omitempty"`
 	CreatedAt time.Time              `json:"created_at"`
 	Metadata  map[string]interface{} `json:"metadata,omitempty"`
 }
@@ -27,8 +47,39 @@ type TestResult struct {
 // DatabaseClient interface for database operations
 type DatabaseClient interface {
 	StoreTestResult(ctx context.Context, userID string, result *exec.CheckResponse) error
+	UpdateTestResult(ctx context.Context, userID string, result *exec.CheckResponse) error
 	GetTestResult(ctx context.Context, userID, requestID string) (*TestResult, error)
-	GetUserTestResults(ctx context.Context, userID string, limit int) ([]TestResult, error)
+	GetUserTestResults(ctx context.Context, userID string, query ResultQuery) (*ResultPage, error)
+	DeleteTestResult(ctx context.Context, userID, requestID string) error
+	DeleteTestResults(ctx context.Context, userID string, query ResultQuery) (int64, error)
+	DeleteTestResultsBefore(ctx context.Context, before time.Time) (int64, error)
+	TrimSuiteTestResults(ctx context.Context, keep int) (int64, error)
+	GetSuiteTestResults(ctx context.Context, userID, suiteID string, limit int) ([]TestResult, error)
+	GetEndpointHealth(ctx context.Context, userID string, query ResultQuery) ([]EndpointHealth, error)
+	CreateSuite(ctx context.Context, userID string, suite *Suite) (*Suite, error)
+	GetSuite(ctx context.Context, userID, suiteID string) (*Suite, error)
+	ListSuites(ctx context.Context, userID string) ([]Suite, error)
+	UpdateSuite(ctx context.Context, userID string, suite *Suite) (*Suite, error)
+	DeleteSuite(ctx context.Context, userID, suiteID string) error
+	SetSuiteStatus(ctx context.Context, userID, suiteID string, status exec.Status) (exec.Status, error)
+	CreateMonitor(ctx context.Context, userID string, monitor *Monitor) (*Monitor, error)
+	GetMonitor(ctx context.Context, userID, monitorID string) (*Monitor, error)
+	ListMonitors(ctx context.Context, userID string) ([]Monitor, error)
+	UpdateMonitor(ctx context.Context, userID string, monitor *Monitor) (*Monitor, error)
+	DeleteMonitor(ctx context.Context, userID, monitorID string) error
+	ListDueMonitors(ctx context.Context, before time.Time) ([]Monitor, error)
+	ClaimMonitorRun(ctx context.Context, monitor *Monitor, nextRunAt time.Time) (bool, error)
+	CreateWebhook(ctx context.Context, userID string, webhook *Webhook) (*Webhook, error)
+	GetWebhook(ctx context.Context, userID, webhookID string) (*Webhook, error)
+	ListWebhooks(ctx context.Context, userID string) ([]Webhook, error)
+	UpdateWebhook(ctx context.Context, userID string, webhook *Webhook) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, userID, webhookID string) error
+	StoreWebhookDelivery(ctx context.Context, delivery *WebhookDelivery) error
+	ListWebhookDeliveries(ctx context.Context, userID, webhookID string, limit int) ([]WebhookDelivery, error)
+	CreateChatDestination(ctx context.Context, userID string, destination *ChatDestination) (*ChatDestination, error)
+	GetChatDestination(ctx context.Context, userID, destinationID string) (*ChatDestination, error)
+	ListChatDestinations(ctx context.Context, userID string) ([]ChatDestination, error)
+	DeleteChatDestination(ctx context.Context, userID, destinationID string) error
 }
 
 // SupabaseClient implements DatabaseClient for Supabase
@@ -36,11 +87,16 @@ type SupabaseClient struct {
 	client *supabase.Client
 }
 
-// NewClient creates a new Supabase database client
+// NewClient creates a new Supabase database client from the environment
 func NewClient() (*SupabaseClient, error) {
-	client := GetSupabaseClient()
-	if client == nil {
-		return nil, fmt.Errorf("failed to initialize Supabase client")
+	return NewSupabaseClient(os.Getenv(environment.ENV_KEY_DB_URL), os.Getenv(environment.ENV_KEY_DB_KEY))
+}
+
+// NewSupabaseClient creates a Supabase database client for a project
+func NewSupabaseClient(url, key string) (*SupabaseClient, error) {
+	client, err := supabase.NewClient(url, key, &supabase.ClientOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Supabase client: %w", err)
 	}
 	return &SupabaseClient{client: client}, nil
 }
@@ -49,21 +105,7 @@ func NewClient() (*SupabaseClient, error) {
 func (s *SupabaseClient) StoreTestResult(ctx context.Context, userID string, result *exec.CheckResponse) error {
 	log := logging.FromContext(ctx)
 
-	// Create the test result data
-	testResult := TestResult{
-		ID:        result.RequestID,
-		UserID:    userID,
-		RequestID: result.RequestID,
-		BaseURL:   result.BaseURL,
-		Status:    result.Status,
-		Results:   result.Results,
-		CreatedAt: time.Now(),
-		Metadata: map[string]interface{}{
-			"endpoint_count": len(result.Results),
-			"passed_count":   countPassedTests(result.Results),
-			"failed_count":   countFailedTests(result.Results),
-		},
-	}
+	testResult := newTestResult(userID, result)
 
 	// Use Supabase SDK's ORM-like interface to insert the test result
 	// Based on the documentation: client.From("table").Insert(data).Execute()
@@ -76,6 +118,30 @@ func (s *SupabaseClient) StoreTestResult(ctx context.Context, userID string, res
 	return nil
 }
 
+// UpdateTestResult replaces the status and results of a stored test result,
+// used as an asynchronous run progresses
+func (s *SupabaseClient) UpdateTestResult(ctx context.Context, userID string, result *exec.CheckResponse) error {
+	log := logging.FromContext(ctx)
+
+	testResult := newTestResult(userID, result)
+	_, _, err := s.client.From("test_results").
+		Update(map[string]interface{}{
+			"status":    testResult.Status,
+			"results":   testResult.Results,
+			"scenarios": testResult.Scenarios,
+			"metadata":  testResult.Metadata,
+		}, "", "").
+		Eq("id", result.RequestID).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to update test result: %w", err)
+	}
+
+	log.Infof("Updated test result %s to status %s", result.RequestID, result.Status)
+	return nil
+}
+
 // GetTestResult retrieves a specific test result by request ID
 func (s *SupabaseClient) GetTestResult(ctx context.Context, userID, requestID string) (*TestResult, error) {
 	log := logging.FromContext(ctx)
@@ -98,27 +164,58 @@ func (s *SupabaseClient) GetTestResult(ctx context.Context, userID, requestID st
 
 	if len(results) == 0 {
 		log.Infof("No test result found for ID: %s", requestID)
-		return nil, fmt.Errorf("test result not found")
+		return nil, ErrTestResultNotFound
 	}
 
 	log.Infof("Successfully retrieved test result with ID: %s", requestID)
 	return &results[0], nil
 }
 
-// GetUserTestResults retrieves all test results for a user
-func (s *SupabaseClient) GetUserTestResults(ctx context.Context, userID string, limit int) ([]TestResult, error) {
+// GetUserTestResults retrieves a page of the test results of a user
+func (s *SupabaseClient) GetUserTestResults(ctx context.Context, userID string, query ResultQuery) (*ResultPage, error) {
 	log := logging.FromContext(ctx)
 
+	page, err := s.resultPage(userID, "*", query)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Successfully retrieved %d test results for user: %s", len(page.Results), userID)
+	return page, nil
+}
+
+// resultPage reads a page of the test results of a user, selecting only the
+// given columns
+func (s *SupabaseClient) resultPage(userID, columns string, query ResultQuery) (*ResultPage, error) {
+	cursor, err := decodeCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	after := "lt"
+	if query.Ascending {
+		after = "gt"
+	}
+	timeFilters := []string{}
+	if cursor != nil {
+		position := cursor.CreatedAt.Format(time.RFC3339Nano)
+		timeFilters = append(timeFilters, fmt.Sprintf("or(created_at.%s.%s,and(created_at.eq.%s,id.%s.%s))",
+			after, position, position, after, cursor.ID))
+	}
+
 	// Build the query
-	query := s.client.From("test_results").
-		Select("*", "exact", false).
-		Eq("user_id", userID)
+	request := filterResults(s.client.From("test_results").
+		Select(columns, "", false).
+		Eq("user_id", userID), query, timeFilters...)
 
-	if limit > 0 {
-		query = query.Limit(limit, "")
+	request = request.
+		Order("created_at", &postgrest.OrderOpts{Ascending: query.Ascending}).
+		Order("id", &postgrest.OrderOpts{Ascending: query.Ascending})
+	if limit := query.fetchLimit(); limit > 0 {
+		request = request.Limit(limit, "")
 	}
 
-	data, _, err := query.Execute()
+	data, _, err := request.Execute()
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve user test results: %w", err)
 	}
@@ -128,12 +225,163 @@ func (s *SupabaseClient) GetUserTestResults(ctx context.Context, userID string,
 	if err := json.Unmarshal(data, &results); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal user test results: %w", err)
 	}
+	return query.page(results), nil
+}
+
+// DeleteTestResult removes a test result by request ID
+func (s *SupabaseClient) DeleteTestResult(ctx context.Context, userID, requestID string) error {
+	log := logging.FromContext(ctx)
+
+	data, _, err := s.client.From("test_results").
+		Delete("representation", "").
+		Eq("id", requestID).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to delete test result: %w", err)
+	}
+
+	var deleted []TestResult
+	if err := json.Unmarshal(data, &deleted); err == nil && len(deleted) == 0 {
+		return ErrTestResultNotFound
+	}
 
-	log.Infof("Successfully retrieved %d test results for user: %s", len(results), userID)
-	return results, nil
+	log.Infof("Successfully deleted test result with ID: %s", requestID)
+	return nil
+}
+
+// DeleteTestResults removes the test results of a user matching the
+// filters of a query, ignoring its limit, cursor and order
+func (s *SupabaseClient) DeleteTestResults(ctx context.Context, userID string, query ResultQuery) (int64, error) {
+	_, count, err := filterResults(s.client.From("test_results").
+		Delete("minimal", "exact").
+		Eq("user_id", userID), query).
+		Execute()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete test results: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteTestResultsBefore removes the test results of every user created
+// before the given time
+func (s *SupabaseClient) DeleteTestResultsBefore(ctx context.Context, before time.Time) (int64, error) {
+	_, count, err := s.client.From("test_results").
+		Delete("minimal", "exact").
+		Lt("created_at", before.UTC().Format(time.RFC3339Nano)).
+		Execute()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired test results: %w", err)
+	}
+	return count, nil
+}
+
+// TrimSuiteTestResults removes all but the newest results of each suite.
+// The API cannot rank rows per suite, so suites are trimmed one at a time.
+func (s *SupabaseClient) TrimSuiteTestResults(ctx context.Context, keep int) (int64, error) {
+	data, _, err := s.client.From("test_suites").Select("id,user_id", "", false).Execute()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list suites: %w", err)
+	}
+	var suites []Suite
+	if err := json.Unmarshal(data, &suites); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal suites: %w", err)
+	}
+
+	var deleted int64
+	for _, suite := range suites {
+		data, _, err := s.client.From("test_results").
+			Select("id", "", false).
+			Eq("user_id", suite.UserID).
+			Eq("suite_id", suite.ID).
+			Order("created_at", &postgrest.OrderOpts{Ascending: false}).
+			Order("id", &postgrest.OrderOpts{Ascending: false}).
+			Range(keep, keep+trimBatchSize-1, "").
+			Execute()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to list results of suite %s: %w", suite.ID, err)
+		}
+		var expired []TestResult
+		if err := json.Unmarshal(data, &expired); err != nil {
+			return deleted, fmt.Errorf("failed to unmarshal results of suite %s: %w", suite.ID, err)
+		}
+		if len(expired) == 0 {
+			continue
+		}
+		ids := make([]string, len(expired))
+		for i, result := range expired {
+			ids[i] = result.ID
+		}
+		_, count, err := s.client.From("test_results").Delete("minimal", "exact").In("id", ids).Execute()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to trim results of suite %s: %w", suite.ID, err)
+		}
+		deleted += count
+	}
+	return deleted, nil
+}
+
+// filterResults applies the filters of a query to a request. Filters are
+// keyed by column, so every condition on created_at, including any extra
+// ones given, has to go into a single logical filter.
+func filterResults(request *postgrest.FilterBuilder, query ResultQuery, timeFilters ...string) *postgrest.FilterBuilder {
+	if len(query.Statuses) > 0 {
+		statuses := make([]string, len(query.Statuses))
+		for i, status := range query.Statuses {
+			statuses[i] = string(status)
+		}
+		request = request.In("status", statuses)
+	}
+	if query.BaseURL != "" {
+		request = request.Eq("base_url", query.BaseURL)
+	}
+	if query.SuiteID != "" {
+		request = request.Eq("suite_id", query.SuiteID)
+	}
+	if len(query.Tags) > 0 {
+		tags, _ := json.Marshal(query.Tags)
+		request = request.Filter("tags", "cs", string(tags))
+	}
+
+	if query.CreatedFrom != nil {
+		timeFilters = append(timeFilters, "created_at.gte."+query.CreatedFrom.UTC().Format(time.RFC3339Nano))
+	}
+	if query.CreatedTo != nil {
+		timeFilters = append(timeFilters, "created_at.lt."+query.CreatedTo.UTC().Format(time.RFC3339Nano))
+	}
+	if len(timeFilters) > 0 {
+		request = request.And(strings.Join(timeFilters, ","), "")
+	}
+	return request
 }
 
 // Helper functions
+func newTestResult(userID string, result *exec.CheckResponse) TestResult {
+	testResult := TestResult{
+		ID:        result.RequestID,
+		UserID:    userID,
+		RequestID: result.RequestID,
+		SuiteID:   result.SuiteID,
+		BaseURL:   result.BaseURL,
+		Status:    result.Status,
+		Results:   result.Results,
+		Scenarios: result.Scenarios,
+		Tags:      result.Tags,
+		CreatedAt: time.Now(),
+		Metadata: map[string]interface{}{
+			"endpoint_count": len(result.Results),
+			"passed_count":   countPassedTests(result.Results),
+			"failed_count":   countFailedTests(result.Results),
+			"errored_count":  countErroredTests(result.Results),
+			"scenario_count": len(result.Scenarios),
+		},
+	}
+	if result.Latency != nil {
+		testResult.Metadata["latency"] = result.Latency
+	}
+	return testResult
+}
+
 func countPassedTests(results []exec.CheckResult) int {
 	count := 0
 	for _, result := range results {
@@ -153,3 +401,13 @@ func countFailedTests(results []exec.CheckResult) int {
 	}
 	return count
 }
+
+func countErroredTests(results []exec.CheckResult) int {
+	count := 0
+	for _, result := range results {
+		if result.StatusCode == "ERROR" {
+			count++
+		}
+	}
+	return count
+}
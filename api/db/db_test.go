@@ -3,6 +3,10 @@ package db
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -108,6 +112,17 @@ func TestCountResults_FailedTests(t *testing.T) {
 	}
 }
 
+func TestCountResults_ErroredTests(t *testing.T) {
+	results := []exec.CheckResult{
+		{StatusCode: "PASS"},
+		{StatusCode: "ERROR"},
+		{StatusCode: "FAIL"},
+		{StatusCode: "ERROR"},
+	}
+	assert.Equal(t, 2, countErroredTests(results))
+	assert.Equal(t, 0, countErroredTests([]exec.CheckResult{}))
+}
+
 func TestTestResultStruct(t *testing.T) {
 	// Test TestResult struct creation and JSON marshaling
 	testResult := TestResult{
@@ -251,7 +266,7 @@ func TestGetUserTestResults(t *testing.T) {
 		// Expected in test environment
 		assert.Contains(t, err.Error(), "failed to initialize Supabase client")
 	} else {
-		results, err := client.GetUserTestResults(ctx, userID, 10)
+		results, err := client.GetUserTestResults(ctx, userID, ResultQuery{Limit: 10})
 		// This will likely fail due to network connectivity in test environment
 		if err != nil {
 			assert.Contains(t, err.Error(), "failed to retrieve user test results")
@@ -375,3 +390,43 @@ func BenchmarkCountFailedTests(b *testing.B) {
 		countFailedTests(results)
 	}
 }
+
+func TestSupabaseEndpointHealthReadsEveryPage(t *testing.T) {
+	pageSize := resultPageSize
+	t.Cleanup(func() { resultPageSize = pageSize })
+	resultPageSize = 2
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	rows := []TestResult{}
+	for i := range 3 {
+		rows = append(rows, TestResult{
+			ID:        fmt.Sprintf("run-%d", i),
+			BaseURL:   "https://example.com",
+			CreatedAt: start.Add(time.Duration(i) * time.Hour),
+			Results:   []exec.CheckResult{{Path: "/health", ExpectedStatus: 200, ActualStatus: 200, StatusCode: "PASS"}},
+		})
+	}
+	// A PostgREST stand-in serving the first page, with one row more than the
+	// page size to show another follows, and the rest after the cursor
+	var requests []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Query())
+		page := rows
+		if r.URL.Query().Has("and") {
+			page = rows[2:]
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client, err := NewSupabaseClient(server.URL, "test-key")
+	require.NoError(t, err)
+	health, err := client.GetEndpointHealth(context.Background(), "user", ResultQuery{})
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, "3", requests[0].Get("limit"))
+	assert.Contains(t, requests[1].Get("and"), "run-1")
+	require.Len(t, health, 1)
+	assert.Equal(t, 3, health[0].Runs)
+}
@@ -0,0 +1,466 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+// MemoryClient implements DatabaseClient in memory, for local development
+// and tests. Nothing is persisted across restarts.
+type MemoryClient struct {
+	mu           sync.Mutex
+	results      map[string]TestResult
+	suites       map[string]Suite
+	monitors     map[string]Monitor
+	webhooks     map[string]Webhook
+	deliveries   []WebhookDelivery
+	destinations map[string]ChatDestination
+}
+
+func NewMemoryClient() *MemoryClient {
+	return &MemoryClient{
+		results:      map[string]TestResult{},
+		suites:       map[string]Suite{},
+		monitors:     map[string]Monitor{},
+		webhooks:     map[string]Webhook{},
+		destinations: map[string]ChatDestination{},
+	}
+}
+
+// filter returns the values matching keep, ordered by the less function
+func filter[T any](items map[string]T, keep func(T) bool, less func(a, b T) bool) []T {
+	matched := []T{}
+	for _, item := range items {
+		if keep(item) {
+			matched = append(matched, item)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return less(matched[i], matched[j]) })
+	return matched
+}
+
+func limited[T any](items []T, limit int) []T {
+	if limit > 0 && len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
+
+// stored round-trips a value through JSON, so that it reads back the same
+// as from the SQL backends and shares nothing with the caller
+func stored[T any](value T) T {
+	var copied T
+	encoded, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(encoded, &copied)
+	}
+	if err != nil {
+		return value
+	}
+	return copied
+}
+
+func newerResult(a, b TestResult) bool {
+	return a.CreatedAt.After(b.CreatedAt)
+}
+
+func (m *MemoryClient) StoreTestResult(ctx context.Context, userID string, result *exec.CheckResponse) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results[result.RequestID] = stored(newTestResult(userID, result))
+	return nil
+}
+
+func (m *MemoryClient) UpdateTestResult(ctx context.Context, userID string, result *exec.CheckResponse) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.results[result.RequestID]
+	if !ok || existing.UserID != userID {
+		return nil
+	}
+	updated := newTestResult(userID, result)
+	updated.CreatedAt = existing.CreatedAt
+	m.results[result.RequestID] = stored(updated)
+	return nil
+}
+
+func (m *MemoryClient) GetTestResult(ctx context.Context, userID, requestID string) (*TestResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result, ok := m.results[requestID]
+	if !ok || result.UserID != userID {
+		return nil, ErrTestResultNotFound
+	}
+	return &result, nil
+}
+
+func (m *MemoryClient) GetUserTestResults(ctx context.Context, userID string, query ResultQuery) (*ResultPage, error) {
+	cursor, err := decodeCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	results := filter(m.results, func(result TestResult) bool {
+		return result.UserID == userID && query.matches(result, cursor)
+	}, query.precedes)
+	return query.page(limited(results, query.fetchLimit())), nil
+}
+
+func (m *MemoryClient) DeleteTestResult(ctx context.Context, userID, requestID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result, ok := m.results[requestID]
+	if !ok || result.UserID != userID {
+		return ErrTestResultNotFound
+	}
+	delete(m.results, requestID)
+	return nil
+}
+
+func (m *MemoryClient) DeleteTestResults(ctx context.Context, userID string, query ResultQuery) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteResults(func(result TestResult) bool {
+		return result.UserID == userID && query.matches(result, nil)
+	}), nil
+}
+
+func (m *MemoryClient) DeleteTestResultsBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteResults(func(result TestResult) bool { return result.CreatedAt.Before(before) }), nil
+}
+
+func (m *MemoryClient) TrimSuiteTestResults(ctx context.Context, keep int) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	suiteResults := filter(m.results, func(result TestResult) bool { return result.SuiteID != "" }, ResultQuery{}.precedes)
+	kept := map[string]int{}
+	expired := map[string]bool{}
+	for _, result := range suiteResults {
+		suite := result.UserID + "/" + result.SuiteID
+		if kept[suite] < keep {
+			kept[suite]++
+			continue
+		}
+		expired[result.ID] = true
+	}
+	return m.deleteResults(func(result TestResult) bool { return expired[result.ID] }), nil
+}
+
+// deleteResults removes the results matching remove and counts them
+func (m *MemoryClient) deleteResults(remove func(TestResult) bool) int64 {
+	var deleted int64
+	for id, result := range m.results {
+		if remove(result) {
+			delete(m.results, id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+func (m *MemoryClient) GetSuiteTestResults(ctx context.Context, userID, suiteID string, limit int) ([]TestResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	results := filter(m.results, func(result TestResult) bool {
+		return result.UserID == userID && result.SuiteID == suiteID
+	}, newerResult)
+	return limited(results, limit), nil
+}
+
+func (m *MemoryClient) GetEndpointHealth(ctx context.Context, userID string, query ResultQuery) ([]EndpointHealth, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	results := filter(m.results, func(result TestResult) bool {
+		return result.UserID == userID && query.matches(result, nil)
+	}, newerResult)
+	return endpointHealth(results), nil
+}
+
+func (m *MemoryClient) CreateSuite(ctx context.Context, userID string, suite *Suite) (*Suite, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now().UTC()
+	created := *suite
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	m.suites[created.ID] = created
+	return &created, nil
+}
+
+func (m *MemoryClient) GetSuite(ctx context.Context, userID, suiteID string) (*Suite, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	suite, ok := m.suites[suiteID]
+	if !ok || suite.UserID != userID {
+		return nil, ErrSuiteNotFound
+	}
+	return &suite, nil
+}
+
+func (m *MemoryClient) ListSuites(ctx context.Context, userID string) ([]Suite, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return filter(m.suites,
+		func(suite Suite) bool { return suite.UserID == userID },
+		func(a, b Suite) bool { return a.CreatedAt.Before(b.CreatedAt) },
+	), nil
+}
+
+func (m *MemoryClient) UpdateSuite(ctx context.Context, userID string, suite *Suite) (*Suite, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.suites[suite.ID]
+	if !ok || existing.UserID != userID {
+		return nil, ErrSuiteNotFound
+	}
+	existing.Name = suite.Name
+	existing.Description = suite.Description
+	existing.Request = suite.Request
+	existing.UpdatedAt = time.Now().UTC()
+	m.suites[suite.ID] = existing
+	return &existing, nil
+}
+
+func (m *MemoryClient) DeleteSuite(ctx context.Context, userID, suiteID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	suite, ok := m.suites[suiteID]
+	if !ok || suite.UserID != userID {
+		return ErrSuiteNotFound
+	}
+	delete(m.suites, suiteID)
+	return nil
+}
+
+func (m *MemoryClient) SetSuiteStatus(ctx context.Context, userID, suiteID string, status exec.Status) (exec.Status, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	suite, ok := m.suites[suiteID]
+	if !ok || suite.UserID != userID {
+		return "", ErrSuiteNotFound
+	}
+	previous := suite.LastStatus
+	suite.LastStatus = status
+	m.suites[suiteID] = suite
+	return previous, nil
+}
+
+func (m *MemoryClient) CreateMonitor(ctx context.Context, userID string, monitor *Monitor) (*Monitor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now().UTC()
+	created := *monitor
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	m.monitors[created.ID] = created
+	return &created, nil
+}
+
+func (m *MemoryClient) GetMonitor(ctx context.Context, userID, monitorID string) (*Monitor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	monitor, ok := m.monitors[monitorID]
+	if !ok || monitor.UserID != userID {
+		return nil, ErrMonitorNotFound
+	}
+	return &monitor, nil
+}
+
+func (m *MemoryClient) ListMonitors(ctx context.Context, userID string) ([]Monitor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return filter(m.monitors,
+		func(monitor Monitor) bool { return monitor.UserID == userID },
+		func(a, b Monitor) bool { return a.CreatedAt.Before(b.CreatedAt) },
+	), nil
+}
+
+func (m *MemoryClient) UpdateMonitor(ctx context.Context, userID string, monitor *Monitor) (*Monitor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.monitors[monitor.ID]
+	if !ok || existing.UserID != userID {
+		return nil, ErrMonitorNotFound
+	}
+	updated := *monitor
+	updated.UserID = userID
+	updated.CreatedAt = existing.CreatedAt
+	updated.UpdatedAt = time.Now().UTC()
+	m.monitors[monitor.ID] = updated
+	return &updated, nil
+}
+
+func (m *MemoryClient) DeleteMonitor(ctx context.Context, userID, monitorID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	monitor, ok := m.monitors[monitorID]
+	if !ok || monitor.UserID != userID {
+		return ErrMonitorNotFound
+	}
+	delete(m.monitors, monitorID)
+	return nil
+}
+
+func (m *MemoryClient) ListDueMonitors(ctx context.Context, before time.Time) ([]Monitor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return filter(m.monitors,
+		func(monitor Monitor) bool { return !monitor.Paused && !monitor.NextRunAt.After(before) },
+		func(a, b Monitor) bool { return a.NextRunAt.Before(b.NextRunAt) },
+	), nil
+}
+
+func (m *MemoryClient) ClaimMonitorRun(ctx context.Context, monitor *Monitor, nextRunAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.monitors[monitor.ID]
+	if !ok || !stored.NextRunAt.Equal(monitor.NextRunAt) {
+		return false, nil
+	}
+	now := time.Now().UTC()
+	stored.NextRunAt = nextRunAt
+	stored.LastRunAt = &now
+	m.monitors[monitor.ID] = stored
+	return true, nil
+}
+
+func (m *MemoryClient) CreateWebhook(ctx context.Context, userID string, webhook *Webhook) (*Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now().UTC()
+	created := *webhook
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	m.webhooks[created.ID] = created
+	return &created, nil
+}
+
+func (m *MemoryClient) GetWebhook(ctx context.Context, userID, webhookID string) (*Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	webhook, ok := m.webhooks[webhookID]
+	if !ok || webhook.UserID != userID {
+		return nil, ErrWebhookNotFound
+	}
+	return &webhook, nil
+}
+
+func (m *MemoryClient) ListWebhooks(ctx context.Context, userID string) ([]Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return filter(m.webhooks,
+		func(webhook Webhook) bool { return webhook.UserID == userID },
+		func(a, b Webhook) bool { return a.CreatedAt.Before(b.CreatedAt) },
+	), nil
+}
+
+func (m *MemoryClient) UpdateWebhook(ctx context.Context, userID string, webhook *Webhook) (*Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.webhooks[webhook.ID]
+	if !ok || existing.UserID != userID {
+		return nil, ErrWebhookNotFound
+	}
+	existing.URL = webhook.URL
+	existing.Events = webhook.Events
+	existing.Active = webhook.Active
+	if webhook.Secret != "" {
+		existing.Secret = webhook.Secret
+	}
+	existing.UpdatedAt = time.Now().UTC()
+	m.webhooks[webhook.ID] = existing
+	return &existing, nil
+}
+
+func (m *MemoryClient) DeleteWebhook(ctx context.Context, userID, webhookID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	webhook, ok := m.webhooks[webhookID]
+	if !ok || webhook.UserID != userID {
+		return ErrWebhookNotFound
+	}
+	delete(m.webhooks, webhookID)
+	return nil
+}
+
+func (m *MemoryClient) StoreWebhookDelivery(ctx context.Context, delivery *WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *delivery
+	stored.ID = uuid.NewString()
+	m.deliveries = append(m.deliveries, stored)
+	return nil
+}
+
+func (m *MemoryClient) ListWebhookDeliveries(ctx context.Context, userID, webhookID string, limit int) ([]WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	deliveries := []WebhookDelivery{}
+	for i := len(m.deliveries) - 1; i >= 0; i-- {
+		delivery := m.deliveries[i]
+		if delivery.UserID != userID || delivery.WebhookID != webhookID {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+		if limit > 0 && len(deliveries) == limit {
+			break
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *MemoryClient) CreateChatDestination(ctx context.Context, userID string, destination *ChatDestination) (*ChatDestination, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	created := *destination
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = time.Now().UTC()
+	m.destinations[created.ID] = created
+	return &created, nil
+}
+
+func (m *MemoryClient) GetChatDestination(ctx context.Context, userID, destinationID string) (*ChatDestination, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	destination, ok := m.destinations[destinationID]
+	if !ok || destination.UserID != userID {
+		return nil, ErrChatDestinationNotFound
+	}
+	return &destination, nil
+}
+
+func (m *MemoryClient) ListChatDestinations(ctx context.Context, userID string) ([]ChatDestination, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return filter(m.destinations,
+		func(destination ChatDestination) bool { return destination.UserID == userID },
+		func(a, b ChatDestination) bool { return a.CreatedAt.Before(b.CreatedAt) },
+	), nil
+}
+
+func (m *MemoryClient) DeleteChatDestination(ctx context.Context, userID, destinationID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	destination, ok := m.destinations[destinationID]
+	if !ok || destination.UserID != userID {
+		return ErrChatDestinationNotFound
+	}
+	delete(m.destinations, destinationID)
+	return nil
+}
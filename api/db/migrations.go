@@ -0,0 +1,308 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/logging"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+var ErrSchemaBehind = errors.New("database schema is behind")
+
+// Migration is a versioned schema change, read from a pair of
+// NNNN_name.up.sql and NNNN_name.down.sql files
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// versioned is implemented by clients whose schema is managed by migrations
+type versioned interface {
+	appliedMigrations(ctx context.Context) (map[int]time.Time, error)
+	migrationDialect() string
+}
+
+// Migrations returns the migrations of a SQL dialect, oldest first
+func Migrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for %s: %w", dialect, err)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		name, direction, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), ".")
+		version, label, found := strings.Cut(name, "_")
+		number, err := strconv.Atoi(version)
+		if !ok || !found || err != nil || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("invalid migration file name '%s'", entry.Name())
+		}
+		content, err := fs.ReadFile(migrationFiles, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migration, exists := byVersion[number]
+		if !exists {
+			migration = &Migration{Version: number, Name: label}
+			byVersion[number] = migration
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := []Migration{}
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// CheckSchema fails with ErrSchemaBehind if the database is missing any of
+// the migrations shipped with this build. Backends without a schema, like
+// the in-memory one, always pass.
+func CheckSchema(ctx context.Context, client DatabaseClient) error {
+	store, ok := client.(versioned)
+	if !ok {
+		return nil
+	}
+	migrations, err := Migrations(store.migrationDialect())
+	if err != nil {
+		return err
+	}
+	applied, err := store.appliedMigrations(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: failed to read applied migrations: %v", ErrSchemaBehind, err)
+	}
+
+	pending := []string{}
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, fmt.Sprintf("%04d_%s", migration.Version, migration.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %d pending migrations (%s)", ErrSchemaBehind, len(pending), strings.Join(pending, ", "))
+	}
+	if len(applied) > len(migrations) {
+		logging.FromContext(ctx).Warnf("Database has %d migrations this build does not know about", len(applied)-len(migrations))
+	}
+	return nil
+}
+
+func (s *SQLClient) migrationDialect() string {
+	return s.dialect
+}
+
+func (s *SQLClient) ensureMigrationsTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version     INTEGER PRIMARY KEY,
+    name        TEXT NOT NULL,
+    applied_at  TIMESTAMP NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLClient) appliedMigrations(ctx context.Context) (map[int]time.Time, error) {
+	if err := s.ensureMigrationsTable(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := s.query(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// MigrationStatus lists every known migration and whether it was applied
+func (s *SQLClient) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := Migrations(s.dialect)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := s.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, migration := range migrations {
+		statuses[i] = MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// MigrateUp applies every pending migration in order, each in its own
+// transaction, and returns the ones applied
+func (s *SQLClient) MigrateUp(ctx context.Context) ([]Migration, error) {
+	log := logging.FromContext(ctx)
+
+	migrations, err := Migrations(s.dialect)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := s.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	done := []Migration{}
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := s.runMigration(ctx, migration.Up,
+			"INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+			migration.Version, migration.Name, time.Now().UTC())
+		if err != nil {
+			return done, fmt.Errorf("failed to apply migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		log.Infof("Applied migration %04d_%s", migration.Version, migration.Name)
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// MigrateDown reverts the given number of most recently applied migrations
+// and returns the ones reverted
+func (s *SQLClient) MigrateDown(ctx context.Context, steps int) ([]Migration, error) {
+	log := logging.FromContext(ctx)
+
+	migrations, err := Migrations(s.dialect)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := s.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	done := []Migration{}
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return done, fmt.Errorf("migration %04d_%s cannot be reverted", migration.Version, migration.Name)
+		}
+		err := s.runMigration(ctx, migration.Down, "DELETE FROM schema_migrations WHERE version = ?", migration.Version)
+		if err != nil {
+			return done, fmt.Errorf("failed to revert migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		log.Infof("Reverted migration %04d_%s", migration.Version, migration.Name)
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// runMigration executes the statements of a migration and records it in a
+// single transaction
+func (s *SQLClient) runMigration(ctx context.Context, script, record string, args ...interface{}) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range strings.Split(script, ";") {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, s.rebind(record), args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SupabaseClient) migrationDialect() string {
+	return DriverPostgres
+}
+
+// Supabase tables are migrated over a direct PostgreSQL connection, but the
+// applied versions can be read through the API
+func (s *SupabaseClient) appliedMigrations(ctx context.Context) (map[int]time.Time, error) {
+	data, _, err := s.client.From("schema_migrations").Select("version,applied_at", "", false).Execute()
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		Version   int       `json:"version"`
+		AppliedAt time.Time `json:"applied_at"`
+	}
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal applied migrations: %w", err)
+	}
+	applied := map[int]time.Time{}
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+	return applied, nil
+}
+
+// OpenMigrator connects to the database of the configured backend for
+// running migrations. Supabase projects are migrated through their
+// PostgreSQL connection string in the DSN.
+func OpenMigrator(ctx context.Context, config Config) (*SQLClient, error) {
+	switch config.Driver {
+	case DriverSupabase:
+		if config.DSN == "" {
+			return nil, fmt.Errorf("migrating Supabase needs the project's PostgreSQL connection string as the DSN")
+		}
+		return NewPostgresClient(ctx, config.DSN)
+	case DriverPostgres:
+		return NewPostgresClient(ctx, config.DSN)
+	case DriverSQLite:
+		return NewSQLiteClient(ctx, config.DSN)
+	}
+	return nil, fmt.Errorf("the %s backend has no schema to migrate", config.Driver)
+}
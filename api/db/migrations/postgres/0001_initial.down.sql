@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS chat_destinations;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+DROP TABLE IF EXISTS monitors;
+DROP TABLE IF EXISTS test_suites;
+DROP TABLE IF EXISTS test_results;
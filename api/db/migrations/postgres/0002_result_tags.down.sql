@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS test_results_user_created_id;
+DROP INDEX IF EXISTS test_results_tags;
+ALTER TABLE test_results DROP COLUMN IF EXISTS tags;
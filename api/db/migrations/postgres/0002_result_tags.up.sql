@@ -0,0 +1,3 @@
+ALTER TABLE test_results ADD COLUMN IF NOT EXISTS tags JSONB;
+CREATE INDEX IF NOT EXISTS test_results_tags ON test_results USING GIN (tags);
+CREATE INDEX IF NOT EXISTS test_results_user_created_id ON test_results (user_id, created_at, id);
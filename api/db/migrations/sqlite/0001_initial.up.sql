@@ -0,0 +1,83 @@
+-- Tables may already exist in deployments created before migrations were
+-- introduced, so this first migration only creates what is missing.
+
+CREATE TABLE IF NOT EXISTS test_results (
+    id          TEXT PRIMARY KEY,
+    user_id     TEXT NOT NULL,
+    request_id  TEXT NOT NULL,
+    suite_id    TEXT NOT NULL DEFAULT '',
+    base_url    TEXT NOT NULL,
+    status      TEXT NOT NULL,
+    results     TEXT NOT NULL,
+    scenarios   TEXT,
+    metadata    TEXT,
+    created_at  TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS test_results_user_created ON test_results (user_id, created_at);
+CREATE INDEX IF NOT EXISTS test_results_suite_created ON test_results (user_id, suite_id, created_at);
+
+CREATE TABLE IF NOT EXISTS test_suites (
+    id           TEXT PRIMARY KEY,
+    user_id      TEXT NOT NULL,
+    name         TEXT NOT NULL,
+    description  TEXT NOT NULL DEFAULT '',
+    request      TEXT NOT NULL,
+    last_status  TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMP NOT NULL,
+    updated_at   TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS test_suites_user ON test_suites (user_id);
+
+CREATE TABLE IF NOT EXISTS monitors (
+    id                TEXT PRIMARY KEY,
+    user_id           TEXT NOT NULL,
+    suite_id          TEXT NOT NULL,
+    name              TEXT NOT NULL,
+    schedule          TEXT NOT NULL DEFAULT '',
+    interval_seconds  INTEGER NOT NULL DEFAULT 0,
+    paused            BOOLEAN NOT NULL DEFAULT 0,
+    next_run_at       TIMESTAMP NOT NULL,
+    last_run_at       TIMESTAMP,
+    created_at        TIMESTAMP NOT NULL,
+    updated_at        TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS monitors_user ON monitors (user_id);
+CREATE INDEX IF NOT EXISTS monitors_due ON monitors (paused, next_run_at);
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id          TEXT PRIMARY KEY,
+    user_id     TEXT NOT NULL,
+    url         TEXT NOT NULL,
+    secret      TEXT NOT NULL DEFAULT '',
+    events      TEXT,
+    active      BOOLEAN NOT NULL DEFAULT 1,
+    created_at  TIMESTAMP NOT NULL,
+    updated_at  TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS webhooks_user ON webhooks (user_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id           TEXT PRIMARY KEY,
+    webhook_id   TEXT NOT NULL,
+    user_id      TEXT NOT NULL,
+    event        TEXT NOT NULL,
+    request_id   TEXT NOT NULL DEFAULT '',
+    attempt      INTEGER NOT NULL,
+    status_code  INTEGER NOT NULL DEFAULT 0,
+    success      BOOLEAN NOT NULL,
+    error        TEXT NOT NULL DEFAULT '',
+    duration_ms  REAL NOT NULL,
+    created_at   TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_created ON webhook_deliveries (webhook_id, created_at);
+
+CREATE TABLE IF NOT EXISTS chat_destinations (
+    id           TEXT PRIMARY KEY,
+    user_id      TEXT NOT NULL,
+    suite_id     TEXT NOT NULL DEFAULT '',
+    name         TEXT NOT NULL,
+    platform     TEXT NOT NULL,
+    webhook_url  TEXT NOT NULL,
+    created_at   TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS chat_destinations_user ON chat_destinations (user_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS test_results_user_created_id;
+ALTER TABLE test_results DROP COLUMN tags;
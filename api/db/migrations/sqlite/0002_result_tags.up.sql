@@ -0,0 +1,2 @@
+ALTER TABLE test_results ADD COLUMN tags TEXT;
+CREATE INDEX IF NOT EXISTS test_results_user_created_id ON test_results (user_id, created_at, id);
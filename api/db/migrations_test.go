@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	exec "github.com/jgfranco17/aeternum/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationsMatchAcrossDialects(t *testing.T) {
+	postgres, err := Migrations(DriverPostgres)
+	require.NoError(t, err)
+	sqlite, err := Migrations(DriverSQLite)
+	require.NoError(t, err)
+
+	require.Len(t, sqlite, len(postgres))
+	for i := range postgres {
+		assert.Equal(t, i+1, postgres[i].Version, "versions are numbered without gaps")
+		assert.Equal(t, postgres[i].Version, sqlite[i].Version)
+		assert.Equal(t, postgres[i].Name, sqlite[i].Name)
+		assert.NotEmpty(t, postgres[i].Down)
+		assert.NotEmpty(t, sqlite[i].Down)
+	}
+
+	_, err = Migrations("oracle")
+	assert.Error(t, err)
+}
+
+func TestMigrateUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	client, err := NewSQLiteClient(ctx, filepath.Join(t.TempDir(), "aeternum.db"))
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.ErrorIs(t, CheckSchema(ctx, client), ErrSchemaBehind)
+	assert.Error(t, client.StoreTestResult(ctx, "user", &exec.CheckResponse{RequestID: "run"}))
+
+	applied, err := client.MigrateUp(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, applied)
+	require.NoError(t, CheckSchema(ctx, client))
+	require.NoError(t, client.StoreTestResult(ctx, "user", &exec.CheckResponse{RequestID: "run"}))
+
+	applied, err = client.MigrateUp(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied, "applying twice is a no-op")
+
+	statuses, err := client.MigrationStatus(ctx)
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.True(t, status.Applied)
+		assert.NotNil(t, status.AppliedAt)
+	}
+
+	reverted, err := client.MigrateDown(ctx, len(statuses))
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(statuses))
+	assert.Equal(t, statuses[len(statuses)-1].Version, reverted[0].Version, "the newest migration is reverted first")
+	assert.ErrorIs(t, CheckSchema(ctx, client), ErrSchemaBehind)
+	_, err = client.GetTestResult(ctx, "user", "run")
+	assert.Error(t, err)
+}
+
+// baselineTestResults is the test_results table of Supabase projects created
+// before suites and scenarios were stored
+const baselineTestResults = `CREATE TABLE test_results (
+    id          TEXT PRIMARY KEY,
+    user_id     TEXT NOT NULL,
+    request_id  TEXT NOT NULL,
+    base_url    TEXT NOT NULL,
+    status      TEXT NOT NULL,
+    results     JSONB NOT NULL,
+    metadata    JSONB,
+    created_at  TIMESTAMPTZ NOT NULL
+)`
+
+func TestMigrateBaselineTestResults(t *testing.T) {
+	dsn := os.Getenv("AETERNUM_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("AETERNUM_TEST_POSTGRES_DSN is not set")
+	}
+	ctx := context.Background()
+	client, err := NewPostgresClient(ctx, dsn)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.MigrateDown(ctx, 1<<10)
+	require.NoError(t, err)
+	_, err = client.exec(ctx, "DROP TABLE IF EXISTS test_results")
+	require.NoError(t, err)
+	_, err = client.exec(ctx, baselineTestResults)
+	require.NoError(t, err)
+	_, err = client.exec(ctx, "INSERT INTO test_results (id, user_id, request_id, base_url, status, results, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		"old", "user", "old", "https://example.com", exec.StatusPass, "[]", time.Now().UTC())
+	require.NoError(t, err)
+
+	_, err = client.MigrateUp(ctx)
+	require.NoError(t, err)
+	require.NoError(t, CheckSchema(ctx, client))
+
+	old, err := client.GetTestResult(ctx, "user", "old")
+	require.NoError(t, err)
+	assert.Empty(t, old.SuiteID)
+	require.NoError(t, client.StoreTestResult(ctx, "user", &exec.CheckResponse{
+		RequestID: "new",
+		SuiteID:   "suite",
+		Scenarios: []exec.ScenarioResult{{Name: "flow", Status: exec.StatusPass}},
+	}))
+	results, err := client.GetSuiteTestResults(ctx, "user", "suite", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "flow", results[0].Scenarios[0].Name)
+}
+
+func TestCheckSchemaWithoutMigrations(t *testing.T) {
+	assert.NoError(t, CheckSchema(context.Background(), NewMemoryClient()))
+}
+
+func TestOpenMigrator(t *testing.T) {
+	_, err := OpenMigrator(context.Background(), Config{Driver: DriverSupabase})
+	assert.ErrorContains(t, err, "PostgreSQL connection string")
+
+	_, err = OpenMigrator(context.Background(), Config{Driver: DriverMemory})
+	assert.ErrorContains(t, err, "no schema to migrate")
+}
@@ -0,0 +1,363 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/configs"
+	"github.com/jgfranco17/aeternum/api/logging"
+	exec "github.com/jgfranco17/aeternum/execution"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// trimDeleteBatchSize is the most results removed by a single delete when
+// trimming suites
+const trimDeleteBatchSize = 1000
+
+// MongoClient implements DatabaseClient on MongoDB. Documents use the JSON
+// field names of the API, with the ID of each record as its _id, so stored
+// documents read the same as API responses. Times are stored as dates with
+// millisecond precision.
+type MongoClient struct {
+	client   *mongo.Client
+	database *mongo.Database
+}
+
+// NewMongoClient connects to the MongoDB deployment of the configuration
+// and creates any missing indexes
+func NewMongoClient(ctx context.Context, config *configs.EnvironmentConfig) (*MongoClient, error) {
+	clientOptions := options.Client().ApplyURI(config.MongoUri()).
+		SetBSONOptions(&options.BSONOptions{UseJSONStructTags: true, DefaultDocumentMap: true})
+	if config.MongoUser() != "" {
+		// Keep options such as authSource given in the URI
+		credential := options.Credential{}
+		if clientOptions.Auth != nil {
+			credential = *clientOptions.Auth
+		}
+		credential.Username = config.MongoUser()
+		credential.Password = config.MongoPassword()
+		clientOptions.SetAuth(credential)
+	}
+
+	client, err := mongo.Connect(clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize MongoDB client: %w", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(ctx)
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+
+	m := &MongoClient{client: client, database: client.Database(config.MongoDatabase())}
+	if err := m.ensureIndexes(ctx); err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
+	return m, nil
+}
+
+// Close disconnects from the deployment
+func (m *MongoClient) Close() error {
+	return m.client.Disconnect(context.Background())
+}
+
+// ensureIndexes creates the indexes used by the queries of each collection.
+// Creating an index that already exists is a no-op, so this runs on every
+// connect instead of through migrations.
+func (m *MongoClient) ensureIndexes(ctx context.Context) error {
+	indexes := map[string][]bson.D{
+		"test_results": {
+			{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			{{Key: "user_id", Value: 1}, {Key: "suite_id", Value: 1}, {Key: "created_at", Value: -1}},
+			{{Key: "request_id", Value: 1}},
+			{{Key: "created_at", Value: 1}},
+		},
+		"test_suites":        {{{Key: "user_id", Value: 1}}},
+		"monitors":           {{{Key: "user_id", Value: 1}}, {{Key: "paused", Value: 1}, {Key: "next_run_at", Value: 1}}},
+		"webhooks":           {{{Key: "user_id", Value: 1}}},
+		"webhook_deliveries": {{{Key: "user_id", Value: 1}, {Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		"chat_destinations":  {{{Key: "user_id", Value: 1}}},
+	}
+	for collection, keys := range indexes {
+		models := make([]mongo.IndexModel, len(keys))
+		for i, key := range keys {
+			models[i] = mongo.IndexModel{Keys: key}
+		}
+		if _, err := m.database.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			return fmt.Errorf("failed to create indexes on %s: %w", collection, err)
+		}
+	}
+	return nil
+}
+
+func (m *MongoClient) results() *mongo.Collection {
+	return m.database.Collection("test_results")
+}
+
+// findAll decodes every document of a query, normalised to read back the
+// same as from the other backends
+func findAll[T any](ctx context.Context, collection *mongo.Collection, filter interface{}, opts ...options.Lister[options.FindOptions]) ([]T, error) {
+	cursor, err := collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	items := []T{}
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i] = stored(items[i])
+	}
+	return items, nil
+}
+
+// findOne decodes a single document, normalised like findAll
+func findOne[T any](ctx context.Context, collection *mongo.Collection, filter interface{}) (*T, error) {
+	var item T
+	if err := collection.FindOne(ctx, filter).Decode(&item); err != nil {
+		return nil, err
+	}
+	item = stored(item)
+	return &item, nil
+}
+
+// mongoTime rounds a time to the millisecond precision of stored dates, so
+// that records returned by writes equal the ones read back later
+func mongoTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Millisecond)
+}
+
+func byUser(userID, id string) bson.D {
+	return bson.D{{Key: "_id", Value: id}, {Key: "user_id", Value: userID}}
+}
+
+// StoreTestResult stores a test execution result
+func (m *MongoClient) StoreTestResult(ctx context.Context, userID string, result *exec.CheckResponse) error {
+	log := logging.FromContext(ctx)
+
+	testResult := newTestResult(userID, result)
+	testResult.CreatedAt = mongoTime(testResult.CreatedAt)
+	if testResult.Results == nil {
+		testResult.Results = []exec.CheckResult{}
+	}
+	if _, err := m.results().InsertOne(ctx, testResult); err != nil {
+		return fmt.Errorf("failed to store test result: %w", err)
+	}
+
+	log.Infof("Successfully stored test result with ID: %s", result.RequestID)
+	return nil
+}
+
+// UpdateTestResult replaces the status and results of a stored test result
+func (m *MongoClient) UpdateTestResult(ctx context.Context, userID string, result *exec.CheckResponse) error {
+	log := logging.FromContext(ctx)
+
+	testResult := newTestResult(userID, result)
+	if testResult.Results == nil {
+		testResult.Results = []exec.CheckResult{}
+	}
+	_, err := m.results().UpdateOne(ctx, byUser(userID, result.RequestID), bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: testResult.Status},
+		{Key: "results", Value: testResult.Results},
+		{Key: "scenarios", Value: testResult.Scenarios},
+		{Key: "metadata", Value: testResult.Metadata},
+	}}})
+	if err != nil {
+		return fmt.Errorf("failed to update test result: %w", err)
+	}
+
+	log.Infof("Updated test result %s to status %s", result.RequestID, result.Status)
+	return nil
+}
+
+// GetTestResult retrieves a specific test result by request ID
+func (m *MongoClient) GetTestResult(ctx context.Context, userID, requestID string) (*TestResult, error) {
+	result, err := findOne[TestResult](ctx, m.results(), byUser(userID, requestID))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrTestResultNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve test result: %w", err)
+	}
+	return result, nil
+}
+
+// resultFilter builds the filter document of the filters and cursor of a query
+func resultFilter(userID string, query ResultQuery, cursor *resultCursor) bson.D {
+	filter := bson.D{{Key: "user_id", Value: userID}}
+	if len(query.Statuses) > 0 {
+		statuses := make(bson.A, len(query.Statuses))
+		for i, status := range query.Statuses {
+			statuses[i] = string(status)
+		}
+		filter = append(filter, bson.E{Key: "status", Value: bson.D{{Key: "$in", Value: statuses}}})
+	}
+	if query.BaseURL != "" {
+		filter = append(filter, bson.E{Key: "base_url", Value: query.BaseURL})
+	}
+	if query.SuiteID != "" {
+		filter = append(filter, bson.E{Key: "suite_id", Value: query.SuiteID})
+	}
+	if len(query.Tags) > 0 {
+		filter = append(filter, bson.E{Key: "tags", Value: bson.D{{Key: "$all", Value: query.Tags}}})
+	}
+
+	created := bson.D{}
+	if query.CreatedFrom != nil {
+		created = append(created, bson.E{Key: "$gte", Value: query.CreatedFrom.UTC()})
+	}
+	if query.CreatedTo != nil {
+		created = append(created, bson.E{Key: "$lt", Value: query.CreatedTo.UTC()})
+	}
+	if len(created) > 0 {
+		filter = append(filter, bson.E{Key: "created_at", Value: created})
+	}
+
+	if cursor != nil {
+		after := "$lt"
+		if query.Ascending {
+			after = "$gt"
+		}
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "created_at", Value: bson.D{{Key: after, Value: cursor.CreatedAt}}}},
+			bson.D{{Key: "created_at", Value: cursor.CreatedAt}, {Key: "_id", Value: bson.D{{Key: after, Value: cursor.ID}}}},
+		}})
+	}
+	return filter
+}
+
+// GetUserTestResults retrieves a page of the test results of a user
+func (m *MongoClient) GetUserTestResults(ctx context.Context, userID string, query ResultQuery) (*ResultPage, error) {
+	cursor, err := decodeCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	direction := -1
+	if query.Ascending {
+		direction = 1
+	}
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: direction}, {Key: "_id", Value: direction}})
+	if limit := query.fetchLimit(); limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+	results, err := findAll[TestResult](ctx, m.results(), resultFilter(userID, query, cursor), findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user test results: %w", err)
+	}
+	return query.page(results), nil
+}
+
+// DeleteTestResult removes a test result by request ID
+func (m *MongoClient) DeleteTestResult(ctx context.Context, userID, requestID string) error {
+	log := logging.FromContext(ctx)
+
+	deleted, err := m.results().DeleteOne(ctx, byUser(userID, requestID))
+	if err != nil {
+		return fmt.Errorf("failed to delete test result: %w", err)
+	}
+	if deleted.DeletedCount == 0 {
+		return ErrTestResultNotFound
+	}
+
+	log.Infof("Successfully deleted test result with ID: %s", requestID)
+	return nil
+}
+
+// DeleteTestResults removes the test results of a user matching the
+// filters of a query, ignoring its limit, cursor and order
+func (m *MongoClient) DeleteTestResults(ctx context.Context, userID string, query ResultQuery) (int64, error) {
+	deleted, err := m.results().DeleteMany(ctx, resultFilter(userID, query, nil))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete test results: %w", err)
+	}
+	return deleted.DeletedCount, nil
+}
+
+// DeleteTestResultsBefore removes the test results of every user created
+// before the given time
+func (m *MongoClient) DeleteTestResultsBefore(ctx context.Context, before time.Time) (int64, error) {
+	deleted, err := m.results().DeleteMany(ctx, bson.D{{Key: "created_at", Value: bson.D{{Key: "$lt", Value: before.UTC()}}}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired test results: %w", err)
+	}
+	return deleted.DeletedCount, nil
+}
+
+// TrimSuiteTestResults removes all but the newest results of each suite.
+// Empty fields are left out of documents, so the results of suites are
+// matched with $gt instead of by comparing with an empty string.
+func (m *MongoClient) TrimSuiteTestResults(ctx context.Context, keep int) (int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "suite_id", Value: bson.D{{Key: "$gt", Value: ""}}}}}},
+		{{Key: "$setWindowFields", Value: bson.D{
+			{Key: "partitionBy", Value: bson.D{{Key: "user_id", Value: "$user_id"}, {Key: "suite_id", Value: "$suite_id"}}},
+			{Key: "sortBy", Value: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+			{Key: "output", Value: bson.D{{Key: "position", Value: bson.D{{Key: "$documentNumber", Value: bson.D{}}}}}},
+		}}},
+		{{Key: "$match", Value: bson.D{{Key: "position", Value: bson.D{{Key: "$gt", Value: keep}}}}}},
+		{{Key: "$project", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+	cursor, err := m.results().Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find expired suite test results: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var deleted int64
+	expired := bson.A{}
+	flush := func() error {
+		if len(expired) == 0 {
+			return nil
+		}
+		result, err := m.results().DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: expired}}}})
+		if err != nil {
+			return fmt.Errorf("failed to trim suite test results: %w", err)
+		}
+		deleted += result.DeletedCount
+		expired = bson.A{}
+		return nil
+	}
+	for cursor.Next(ctx) {
+		expired = append(expired, cursor.Current.Lookup("_id"))
+		if len(expired) == trimDeleteBatchSize {
+			if err := flush(); err != nil {
+				return deleted, err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return deleted, fmt.Errorf("failed to find expired suite test results: %w", err)
+	}
+	return deleted, flush()
+}
+
+// GetEndpointHealth aggregates the checks of the test results of a user
+// matching the filters of a query by endpoint. The results are aggregated
+// in Go rather than in a pipeline.
+func (m *MongoClient) GetEndpointHealth(ctx context.Context, userID string, query ResultQuery) ([]EndpointHealth, error) {
+	results, err := findAll[TestResult](ctx, m.results(), resultFilter(userID, query, nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve test results for endpoint health: %w", err)
+	}
+	return endpointHealth(results), nil
+}
+
+// GetSuiteTestResults retrieves the results of runs of a suite, newest first
+func (m *MongoClient) GetSuiteTestResults(ctx context.Context, userID, suiteID string, limit int) ([]TestResult, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+	results, err := findAll[TestResult](ctx, m.results(),
+		bson.D{{Key: "user_id", Value: userID}, {Key: "suite_id", Value: suiteID}}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve suite test results: %w", err)
+	}
+	return results, nil
+}
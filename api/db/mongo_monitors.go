@@ -0,0 +1,151 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jgfranco17/aeternum/api/logging"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func (m *MongoClient) monitors() *mongo.Collection {
+	return m.database.Collection("monitors")
+}
+
+// monitorTimes rounds the times of a monitor to the precision they are
+// stored with
+func monitorTimes(monitor *Monitor) {
+	monitor.NextRunAt = mongoTime(monitor.NextRunAt)
+	if monitor.LastRunAt != nil {
+		lastRunAt := mongoTime(*monitor.LastRunAt)
+		monitor.LastRunAt = &lastRunAt
+	}
+}
+
+// CreateMonitor stores a new monitor for the user, assigning its ID
+func (m *MongoClient) CreateMonitor(ctx context.Context, userID string, monitor *Monitor) (*Monitor, error) {
+	log := logging.FromContext(ctx)
+
+	now := mongoTime(time.Now())
+	created := *monitor
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	monitorTimes(&created)
+
+	if _, err := m.monitors().InsertOne(ctx, created); err != nil {
+		return nil, fmt.Errorf("failed to store monitor: %w", err)
+	}
+
+	log.Infof("Successfully created monitor with ID: %s", created.ID)
+	return &created, nil
+}
+
+// GetMonitor retrieves a monitor by ID
+func (m *MongoClient) GetMonitor(ctx context.Context, userID, monitorID string) (*Monitor, error) {
+	monitor, err := findOne[Monitor](ctx, m.monitors(), byUser(userID, monitorID))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrMonitorNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve monitor: %w", err)
+	}
+	return monitor, nil
+}
+
+// ListMonitors retrieves all monitors for a user
+func (m *MongoClient) ListMonitors(ctx context.Context, userID string) ([]Monitor, error) {
+	monitors, err := findAll[Monitor](ctx, m.monitors(), bson.D{{Key: "user_id", Value: userID}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve monitors: %w", err)
+	}
+	return monitors, nil
+}
+
+// UpdateMonitor replaces the stored monitor with the given one
+func (m *MongoClient) UpdateMonitor(ctx context.Context, userID string, monitor *Monitor) (*Monitor, error) {
+	log := logging.FromContext(ctx)
+
+	updated := *monitor
+	updated.UserID = userID
+	updated.UpdatedAt = mongoTime(time.Now())
+	monitorTimes(&updated)
+
+	var lastRunAt interface{}
+	if updated.LastRunAt != nil {
+		lastRunAt = *updated.LastRunAt
+	}
+	matched, err := m.monitors().UpdateOne(ctx, byUser(userID, monitor.ID), bson.D{{Key: "$set", Value: bson.D{
+		{Key: "suite_id", Value: updated.SuiteID},
+		{Key: "name", Value: updated.Name},
+		{Key: "schedule", Value: updated.Schedule},
+		{Key: "interval_seconds", Value: updated.IntervalSeconds},
+		{Key: "paused", Value: updated.Paused},
+		{Key: "next_run_at", Value: updated.NextRunAt},
+		{Key: "last_run_at", Value: lastRunAt},
+		{Key: "updated_at", Value: updated.UpdatedAt},
+	}}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update monitor: %w", err)
+	}
+	if matched.MatchedCount == 0 {
+		return nil, ErrMonitorNotFound
+	}
+
+	log.Infof("Successfully updated monitor with ID: %s", monitor.ID)
+	return &updated, nil
+}
+
+// DeleteMonitor removes a monitor by ID
+func (m *MongoClient) DeleteMonitor(ctx context.Context, userID, monitorID string) error {
+	log := logging.FromContext(ctx)
+
+	deleted, err := m.monitors().DeleteOne(ctx, byUser(userID, monitorID))
+	if err != nil {
+		return fmt.Errorf("failed to delete monitor: %w", err)
+	}
+	if deleted.DeletedCount == 0 {
+		return ErrMonitorNotFound
+	}
+
+	log.Infof("Successfully deleted monitor with ID: %s", monitorID)
+	return nil
+}
+
+// ListDueMonitors retrieves the monitors of every user that are not paused
+// and are due to run at or before the given time
+func (m *MongoClient) ListDueMonitors(ctx context.Context, before time.Time) ([]Monitor, error) {
+	filter := bson.D{
+		{Key: "paused", Value: false},
+		{Key: "next_run_at", Value: bson.D{{Key: "$lte", Value: before.UTC()}}},
+	}
+	monitors, err := findAll[Monitor](ctx, m.monitors(), filter,
+		options.Find().SetSort(bson.D{{Key: "next_run_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve due monitors: %w", err)
+	}
+	return monitors, nil
+}
+
+// ClaimMonitorRun moves the monitor to its next run, but only if it is still
+// scheduled for the run the caller saw
+func (m *MongoClient) ClaimMonitorRun(ctx context.Context, monitor *Monitor, nextRunAt time.Time) (bool, error) {
+	claimed, err := m.monitors().UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: monitor.ID}, {Key: "next_run_at", Value: mongoTime(monitor.NextRunAt)}},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "next_run_at", Value: mongoTime(nextRunAt)},
+			{Key: "last_run_at", Value: mongoTime(time.Now())},
+		}}})
+	if err != nil {
+		return false, fmt.Errorf("failed to claim monitor run: %w", err)
+	}
+	return claimed.MatchedCount > 0, nil
+}
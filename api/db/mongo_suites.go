@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jgfranco17/aeternum/api/logging"
+	exec "github.com/jgfranco17/aeternum/execution"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func (m *MongoClient) suites() *mongo.Collection {
+	return m.database.Collection("test_suites")
+}
+
+// CreateSuite stores a new suite for the user, assigning its ID
+func (m *MongoClient) CreateSuite(ctx context.Context, userID string, suite *Suite) (*Suite, error) {
+	log := logging.FromContext(ctx)
+
+	now := mongoTime(time.Now())
+	created := *suite
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+
+	if _, err := m.suites().InsertOne(ctx, created); err != nil {
+		return nil, fmt.Errorf("failed to store suite: %w", err)
+	}
+
+	log.Infof("Successfully created suite with ID: %s", created.ID)
+	return &created, nil
+}
+
+// GetSuite retrieves a suite by ID
+func (m *MongoClient) GetSuite(ctx context.Context, userID, suiteID string) (*Suite, error) {
+	suite, err := findOne[Suite](ctx, m.suites(), byUser(userID, suiteID))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrSuiteNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve suite: %w", err)
+	}
+	return suite, nil
+}
+
+// ListSuites retrieves all suites for a user
+func (m *MongoClient) ListSuites(ctx context.Context, userID string) ([]Suite, error) {
+	suites, err := findAll[Suite](ctx, m.suites(), bson.D{{Key: "user_id", Value: userID}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve suites: %w", err)
+	}
+	return suites, nil
+}
+
+// UpdateSuite replaces the name, description and request of a suite
+func (m *MongoClient) UpdateSuite(ctx context.Context, userID string, suite *Suite) (*Suite, error) {
+	log := logging.FromContext(ctx)
+
+	existing, err := m.GetSuite(ctx, userID, suite.ID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *existing
+	updated.Name = suite.Name
+	updated.Description = suite.Description
+	updated.Request = suite.Request
+	updated.UpdatedAt = mongoTime(time.Now())
+
+	if _, err := m.suites().ReplaceOne(ctx, byUser(userID, suite.ID), updated); err != nil {
+		return nil, fmt.Errorf("failed to update suite: %w", err)
+	}
+
+	log.Infof("Successfully updated suite with ID: %s", suite.ID)
+	return &updated, nil
+}
+
+// SetSuiteStatus records the status of the latest run of a suite, returning
+// the status it replaced. The read and the write are a single atomic update.
+func (m *MongoClient) SetSuiteStatus(ctx context.Context, userID, suiteID string, status exec.Status) (exec.Status, error) {
+	var previous Suite
+	err := m.suites().FindOneAndUpdate(ctx, byUser(userID, suiteID),
+		bson.D{{Key: "$set", Value: bson.D{{Key: "last_status", Value: string(status)}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before)).Decode(&previous)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", ErrSuiteNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to update suite status: %w", err)
+	}
+	return previous.LastStatus, nil
+}
+
+// DeleteSuite removes a suite by ID
+func (m *MongoClient) DeleteSuite(ctx context.Context, userID, suiteID string) error {
+	log := logging.FromContext(ctx)
+
+	deleted, err := m.suites().DeleteOne(ctx, byUser(userID, suiteID))
+	if err != nil {
+		return fmt.Errorf("failed to delete suite: %w", err)
+	}
+	if deleted.DeletedCount == 0 {
+		return ErrSuiteNotFound
+	}
+
+	log.Infof("Successfully deleted suite with ID: %s", suiteID)
+	return nil
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	exec "github.com/jgfranco17/aeternum/execution"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestMongoResultFilter(t *testing.T) {
+	assert.Equal(t, bson.D{{Key: "user_id", Value: "user"}}, resultFilter("user", ResultQuery{Limit: 10}, nil))
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.FixedZone("CET", 3600))
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	filter := resultFilter("user", ResultQuery{
+		Statuses:    []exec.Status{exec.StatusFail, exec.StatusError},
+		BaseURL:     "https://example.com",
+		SuiteID:     "suite",
+		Tags:        []string{"smoke"},
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+	}, nil)
+	assert.Equal(t, bson.D{
+		{Key: "user_id", Value: "user"},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{"FAIL", "ERROR"}}}},
+		{Key: "base_url", Value: "https://example.com"},
+		{Key: "suite_id", Value: "suite"},
+		{Key: "tags", Value: bson.D{{Key: "$all", Value: []string{"smoke"}}}},
+		{Key: "created_at", Value: bson.D{{Key: "$gte", Value: from.UTC()}, {Key: "$lt", Value: to}}},
+	}, filter)
+}
+
+func TestMongoResultFilterCursor(t *testing.T) {
+	cursor := &resultCursor{CreatedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), ID: "run-5"}
+
+	descending := resultFilter("user", ResultQuery{}, cursor)
+	assert.Equal(t, bson.E{Key: "$or", Value: bson.A{
+		bson.D{{Key: "created_at", Value: bson.D{{Key: "$lt", Value: cursor.CreatedAt}}}},
+		bson.D{{Key: "created_at", Value: cursor.CreatedAt}, {Key: "_id", Value: bson.D{{Key: "$lt", Value: "run-5"}}}},
+	}}, descending[len(descending)-1])
+
+	ascending := resultFilter("user", ResultQuery{Ascending: true}, cursor)
+	assert.Equal(t, bson.E{Key: "$or", Value: bson.A{
+		bson.D{{Key: "created_at", Value: bson.D{{Key: "$gt", Value: cursor.CreatedAt}}}},
+		bson.D{{Key: "created_at", Value: cursor.CreatedAt}, {Key: "_id", Value: bson.D{{Key: "$gt", Value: "run-5"}}}},
+	}}, ascending[len(ascending)-1])
+}
+
+func TestMongoTime(t *testing.T) {
+	local := time.Date(2024, 3, 1, 12, 0, 0, 123456789, time.FixedZone("CET", 3600))
+	assert.Equal(t, time.Date(2024, 3, 1, 11, 0, 0, 123000000, time.UTC), mongoTime(local))
+}
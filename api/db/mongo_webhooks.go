@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jgfranco17/aeternum/api/logging"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func (m *MongoClient) webhooks() *mongo.Collection {
+	return m.database.Collection("webhooks")
+}
+
+func (m *MongoClient) webhookDeliveries() *mongo.Collection {
+	return m.database.Collection("webhook_deliveries")
+}
+
+func (m *MongoClient) chatDestinations() *mongo.Collection {
+	return m.database.Collection("chat_destinations")
+}
+
+// CreateWebhook stores a new webhook for the user, assigning its ID
+func (m *MongoClient) CreateWebhook(ctx context.Context, userID string, webhook *Webhook) (*Webhook, error) {
+	log := logging.FromContext(ctx)
+
+	now := mongoTime(time.Now())
+	created := *webhook
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+
+	if _, err := m.webhooks().InsertOne(ctx, created); err != nil {
+		return nil, fmt.Errorf("failed to store webhook: %w", err)
+	}
+
+	log.Infof("Successfully created webhook with ID: %s", created.ID)
+	return &created, nil
+}
+
+// GetWebhook retrieves a webhook by ID
+func (m *MongoClient) GetWebhook(ctx context.Context, userID, webhookID string) (*Webhook, error) {
+	webhook, err := findOne[Webhook](ctx, m.webhooks(), byUser(userID, webhookID))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// ListWebhooks retrieves all webhooks for a user
+func (m *MongoClient) ListWebhooks(ctx context.Context, userID string) ([]Webhook, error) {
+	webhooks, err := findAll[Webhook](ctx, m.webhooks(), bson.D{{Key: "user_id", Value: userID}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// UpdateWebhook replaces the URL, events and active flag of a webhook. The
+// secret is only replaced when a new one is given.
+func (m *MongoClient) UpdateWebhook(ctx context.Context, userID string, webhook *Webhook) (*Webhook, error) {
+	log := logging.FromContext(ctx)
+
+	existing, err := m.GetWebhook(ctx, userID, webhook.ID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *existing
+	updated.URL = webhook.URL
+	updated.Events = webhook.Events
+	updated.Active = webhook.Active
+	if webhook.Secret != "" {
+		updated.Secret = webhook.Secret
+	}
+	updated.UpdatedAt = mongoTime(time.Now())
+
+	if _, err := m.webhooks().ReplaceOne(ctx, byUser(userID, webhook.ID), updated); err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	log.Infof("Successfully updated webhook with ID: %s", webhook.ID)
+	return &updated, nil
+}
+
+// DeleteWebhook removes a webhook by ID
+func (m *MongoClient) DeleteWebhook(ctx context.Context, userID, webhookID string) error {
+	log := logging.FromContext(ctx)
+
+	deleted, err := m.webhooks().DeleteOne(ctx, byUser(userID, webhookID))
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if deleted.DeletedCount == 0 {
+		return ErrWebhookNotFound
+	}
+
+	log.Infof("Successfully deleted webhook with ID: %s", webhookID)
+	return nil
+}
+
+// StoreWebhookDelivery records a delivery attempt, assigning its ID
+func (m *MongoClient) StoreWebhookDelivery(ctx context.Context, delivery *WebhookDelivery) error {
+	record := *delivery
+	record.ID = uuid.NewString()
+	record.CreatedAt = mongoTime(delivery.CreatedAt)
+	if _, err := m.webhookDeliveries().InsertOne(ctx, record); err != nil {
+		return fmt.Errorf("failed to store webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// ListWebhookDeliveries retrieves the most recent delivery attempts of a webhook
+func (m *MongoClient) ListWebhookDeliveries(ctx context.Context, userID, webhookID string, limit int) ([]WebhookDelivery, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+	deliveries, err := findAll[WebhookDelivery](ctx, m.webhookDeliveries(),
+		bson.D{{Key: "user_id", Value: userID}, {Key: "webhook_id", Value: webhookID}}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// CreateChatDestination stores a new chat destination, assigning its ID
+func (m *MongoClient) CreateChatDestination(ctx context.Context, userID string, destination *ChatDestination) (*ChatDestination, error) {
+	log := logging.FromContext(ctx)
+
+	created := *destination
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = mongoTime(time.Now())
+
+	if _, err := m.chatDestinations().InsertOne(ctx, created); err != nil {
+		return nil, fmt.Errorf("failed to store chat destination: %w", err)
+	}
+
+	log.Infof("Successfully created chat destination with ID: %s", created.ID)
+	return &created, nil
+}
+
+// GetChatDestination retrieves a chat destination by ID
+func (m *MongoClient) GetChatDestination(ctx context.Context, userID, destinationID string) (*ChatDestination, error) {
+	destination, err := findOne[ChatDestination](ctx, m.chatDestinations(), byUser(userID, destinationID))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrChatDestinationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve chat destination: %w", err)
+	}
+	return destination, nil
+}
+
+// ListChatDestinations retrieves all chat destinations for a user
+func (m *MongoClient) ListChatDestinations(ctx context.Context, userID string) ([]ChatDestination, error) {
+	destinations, err := findAll[ChatDestination](ctx, m.chatDestinations(), bson.D{{Key: "user_id", Value: userID}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve chat destinations: %w", err)
+	}
+	return destinations, nil
+}
+
+// DeleteChatDestination removes a chat destination by ID
+func (m *MongoClient) DeleteChatDestination(ctx context.Context, userID, destinationID string) error {
+	log := logging.FromContext(ctx)
+
+	deleted, err := m.chatDestinations().DeleteOne(ctx, byUser(userID, destinationID))
+	if err != nil {
+		return fmt.Errorf("failed to delete chat destination: %w", err)
+	}
+	if deleted.DeletedCount == 0 {
+		return ErrChatDestinationNotFound
+	}
+
+	log.Infof("Successfully deleted chat destination with ID: %s", destinationID)
+	return nil
+}
@@ -0,0 +1,179 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jgfranco17/aeternum/api/logging"
+)
+
+// Monitor runs a suite on a cron schedule or at a fixed interval
+type Monitor struct {
+	ID              string     `json:"id" bson:"_id"`
+	UserID          string     `json:"user_id"`
+	SuiteID         string     `json:"suite_id"`
+	Name            string     `json:"name"`
+	Schedule        string     `json:"schedule,omitempty"`
+	IntervalSeconds int        `json:"interval_seconds,omitempty"`
+	Paused          bool       `json:"paused"`
+	NextRunAt       time.Time  `json:"next_run_at"`
+	LastRunAt       *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// CreateMonitor stores a new monitor for the user, assigning its ID
+func (s *SupabaseClient) CreateMonitor(ctx context.Context, userID string, monitor *Monitor) (*Monitor, error) {
+	log := logging.FromContext(ctx)
+
+	now := time.Now()
+	created := *monitor
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+
+	_, _, err := s.client.From("monitors").Insert(created, false, "", "", "").Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to store monitor: %w", err)
+	}
+
+	log.Infof("Successfully created monitor with ID: %s", created.ID)
+	return &created, nil
+}
+
+// GetMonitor retrieves a monitor by ID
+func (s *SupabaseClient) GetMonitor(ctx context.Context, userID, monitorID string) (*Monitor, error) {
+	data, _, err := s.client.From("monitors").
+		Select("*", "exact", false).
+		Eq("id", monitorID).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve monitor: %w", err)
+	}
+
+	var monitors []Monitor
+	if err := json.Unmarshal(data, &monitors); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal monitor: %w", err)
+	}
+	if len(monitors) == 0 {
+		return nil, ErrMonitorNotFound
+	}
+	return &monitors[0], nil
+}
+
+// ListMonitors retrieves all monitors for a user
+func (s *SupabaseClient) ListMonitors(ctx context.Context, userID string) ([]Monitor, error) {
+	log := logging.FromContext(ctx)
+
+	data, _, err := s.client.From("monitors").
+		Select("*", "exact", false).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve monitors: %w", err)
+	}
+
+	monitors := []Monitor{}
+	if err := json.Unmarshal(data, &monitors); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal monitors: %w", err)
+	}
+
+	log.Infof("Successfully retrieved %d monitors for user: %s", len(monitors), userID)
+	return monitors, nil
+}
+
+// UpdateMonitor replaces the stored monitor with the given one
+func (s *SupabaseClient) UpdateMonitor(ctx context.Context, userID string, monitor *Monitor) (*Monitor, error) {
+	log := logging.FromContext(ctx)
+
+	updated := *monitor
+	updated.UserID = userID
+	updated.UpdatedAt = time.Now()
+
+	data, _, err := s.client.From("monitors").
+		Update(updated, "representation", "").
+		Eq("id", monitor.ID).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update monitor: %w", err)
+	}
+
+	var monitors []Monitor
+	if err := json.Unmarshal(data, &monitors); err == nil && len(monitors) == 0 {
+		return nil, ErrMonitorNotFound
+	}
+
+	log.Infof("Successfully updated monitor with ID: %s", monitor.ID)
+	return &updated, nil
+}
+
+// DeleteMonitor removes a monitor by ID
+func (s *SupabaseClient) DeleteMonitor(ctx context.Context, userID, monitorID string) error {
+	log := logging.FromContext(ctx)
+
+	data, _, err := s.client.From("monitors").
+		Delete("representation", "").
+		Eq("id", monitorID).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to delete monitor: %w", err)
+	}
+
+	var deleted []Monitor
+	if err := json.Unmarshal(data, &deleted); err == nil && len(deleted) == 0 {
+		return ErrMonitorNotFound
+	}
+
+	log.Infof("Successfully deleted monitor with ID: %s", monitorID)
+	return nil
+}
+
+// ListDueMonitors retrieves the monitors of every user that are not paused
+// and are due to run at or before the given time
+func (s *SupabaseClient) ListDueMonitors(ctx context.Context, before time.Time) ([]Monitor, error) {
+	data, _, err := s.client.From("monitors").
+		Select("*", "exact", false).
+		Eq("paused", "false").
+		Lte("next_run_at", before.UTC().Format(time.RFC3339)).
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve due monitors: %w", err)
+	}
+
+	monitors := []Monitor{}
+	if err := json.Unmarshal(data, &monitors); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal monitors: %w", err)
+	}
+	return monitors, nil
+}
+
+// ClaimMonitorRun moves the monitor to its next run, but only if it is still
+// scheduled for the run the caller saw. When several replicas race for the
+// same run, exactly one of them gets true back.
+func (s *SupabaseClient) ClaimMonitorRun(ctx context.Context, monitor *Monitor, nextRunAt time.Time) (bool, error) {
+	now := time.Now()
+	data, _, err := s.client.From("monitors").
+		Update(map[string]interface{}{
+			"next_run_at": nextRunAt.UTC().Format(time.RFC3339),
+			"last_run_at": now.UTC().Format(time.RFC3339),
+		}, "representation", "").
+		Eq("id", monitor.ID).
+		Eq("next_run_at", monitor.NextRunAt.UTC().Format(time.RFC3339)).
+		Execute()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim monitor run: %w", err)
+	}
+
+	var claimed []Monitor
+	if err := json.Unmarshal(data, &claimed); err != nil {
+		return false, fmt.Errorf("failed to unmarshal claimed monitor: %w", err)
+	}
+	return len(claimed) > 0, nil
+}
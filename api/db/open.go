@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jgfranco17/aeternum/api/configs"
+	"github.com/jgfranco17/aeternum/api/environment"
+)
+
+const (
+	DriverSupabase = "supabase"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+	DriverMongo    = "mongo"
+)
+
+// Config selects the storage backend. DSN is the connection string for
+// PostgreSQL or the database file for SQLite; Supabase is configured through
+// its project URL and key, and MongoDB through the AETERNUM_MONGO_* variables
+// read by the configs package.
+type Config struct {
+	Driver string
+	DSN    string
+}
+
+// ConfigFromEnvironment reads the storage backend from the environment,
+// defaulting to Supabase.
+func ConfigFromEnvironment() Config {
+	return Config{
+		Driver: environment.GetEnvWithDefault(environment.ENV_KEY_DB_DRIVER, DriverSupabase),
+		DSN:    environment.GetEnvWithDefault(environment.ENV_KEY_DB_DSN, ""),
+	}
+}
+
+// Open connects to the configured storage backend
+func Open(ctx context.Context, config Config) (DatabaseClient, error) {
+	var client DatabaseClient
+	var err error
+	switch config.Driver {
+	case DriverSupabase:
+		client, err = NewClient()
+	case DriverPostgres:
+		client, err = NewPostgresClient(ctx, config.DSN)
+	case DriverSQLite:
+		client, err = NewSQLiteClient(ctx, config.DSN)
+	case DriverMemory:
+		client = NewMemoryClient()
+	case DriverMongo:
+		var mongoConfig *configs.EnvironmentConfig
+		mongoConfig, err = configs.NewConfigFromSecrets()
+		if err == nil {
+			client, err = NewMongoClient(ctx, mongoConfig)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported database driver '%s'", config.Driver)
+	}
+	// Avoid handing back a typed nil client on failure
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
@@ -0,0 +1,18 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// NewPostgresClient connects to a PostgreSQL database
+func NewPostgresClient(ctx context.Context, dsn string) (*SQLClient, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres database: %w", err)
+	}
+	return newSQLClient(ctx, db, DriverPostgres, true)
+}
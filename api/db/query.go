@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// resultPageSize is the page size results are read in when every matching
+// result is needed, kept below the row limit hosted PostgREST applies to a
+// single response
+var resultPageSize = 500
+
+// ResultQuery selects a page of a user's test results. Filters left at
+// their zero value are not applied.
+type ResultQuery struct {
+	// Limit is the page size, where zero returns every matching result
+	Limit int
+	// Cursor continues from the NextCursor of a previous page
+	Cursor   string
+	Statuses []exec.Status
+	BaseURL  string
+	SuiteID  string
+	// Tags only matches results carrying every one of the tags
+	Tags []string
+	// CreatedFrom is inclusive and CreatedTo exclusive
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// Ascending returns the oldest results first instead of the newest
+	Ascending bool
+}
+
+// ResultPage is one page of test results. NextCursor is empty on the last page.
+type ResultPage struct {
+	Results    []TestResult `json:"results"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
+
+// resultCursor is the position of the last result of a page. Results are
+// ordered by creation time and then ID, so the position is unique even when
+// several results share a timestamp.
+type resultCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"id"`
+}
+
+func encodeCursor(result TestResult) string {
+	encoded, _ := json.Marshal(resultCursor{CreatedAt: result.CreatedAt.UTC(), ID: result.ID})
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// decodeCursor returns nil for an empty cursor, which starts at the first page
+func decodeCursor(cursor string) (*resultCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var position resultCursor
+	if err := json.Unmarshal(decoded, &position); err != nil || position.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	position.CreatedAt = position.CreatedAt.UTC()
+	return &position, nil
+}
+
+// Filtered reports whether the query narrows down the results of a user
+func (q ResultQuery) Filtered() bool {
+	return len(q.Statuses) > 0 || q.BaseURL != "" || q.SuiteID != "" || len(q.Tags) > 0 ||
+		q.CreatedFrom != nil || q.CreatedTo != nil
+}
+
+// fetchLimit is the number of rows a backend reads for a page: one more
+// than the page size, to find out whether another page follows
+func (q ResultQuery) fetchLimit() int {
+	if q.Limit <= 0 {
+		return 0
+	}
+	return q.Limit + 1
+}
+
+// page trims results read with fetchLimit down to the page size
+func (q ResultQuery) page(results []TestResult) *ResultPage {
+	page := &ResultPage{Results: results}
+	if q.Limit > 0 && len(results) > q.Limit {
+		page.Results = results[:q.Limit]
+		page.NextCursor = encodeCursor(page.Results[q.Limit-1])
+	}
+	return page
+}
+
+// precedes reports whether a comes before b in the order of the query
+func (q ResultQuery) precedes(a, b TestResult) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt) == q.Ascending
+	}
+	return a.ID != b.ID && (a.ID < b.ID) == q.Ascending
+}
+
+// matches applies the filters and cursor of the query to a result, for
+// backends that cannot push them down to storage
+func (q ResultQuery) matches(result TestResult, cursor *resultCursor) bool {
+	if len(q.Statuses) > 0 && !slices.Contains(q.Statuses, result.Status) {
+		return false
+	}
+	if q.BaseURL != "" && result.BaseURL != q.BaseURL {
+		return false
+	}
+	if q.SuiteID != "" && result.SuiteID != q.SuiteID {
+		return false
+	}
+	for _, tag := range q.Tags {
+		if !slices.Contains(result.Tags, tag) {
+			return false
+		}
+	}
+	if q.CreatedFrom != nil && result.CreatedAt.Before(*q.CreatedFrom) {
+		return false
+	}
+	if q.CreatedTo != nil && !result.CreatedAt.Before(*q.CreatedTo) {
+		return false
+	}
+	if cursor != nil {
+		return q.precedes(TestResult{CreatedAt: cursor.CreatedAt, ID: cursor.ID}, result)
+	}
+	return true
+}
+
+// allPages reads every result of a query a page at a time, following the
+// cursor to the last page, so no backend cuts the results short
+func allPages(query ResultQuery, fetch func(query ResultQuery) (*ResultPage, error)) ([]TestResult, error) {
+	query.Limit = resultPageSize
+	query.Cursor = ""
+	results := []TestResult{}
+	for {
+		page, err := fetch(query)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, page.Results...)
+		if page.NextCursor == "" {
+			return results, nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+// AllTestResults reads every test result of a user that matches the query,
+// ignoring its limit and cursor
+func AllTestResults(ctx context.Context, client DatabaseClient, userID string, query ResultQuery) ([]TestResult, error) {
+	return allPages(query, func(query ResultQuery) (*ResultPage, error) {
+		return client.GetUserTestResults(ctx, userID, query)
+	})
+}
@@ -0,0 +1,345 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/logging"
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+// SQLClient implements DatabaseClient on top of database/sql, for the
+// PostgreSQL and SQLite backends. Queries are written with ? placeholders
+// and rewritten for drivers that number their parameters. Times are stored
+// in UTC so that SQLite, which keeps them as text, orders them correctly.
+type SQLClient struct {
+	db       *sql.DB
+	dialect  string
+	numbered bool
+}
+
+func newSQLClient(ctx context.Context, db *sql.DB, dialect string, numbered bool) (*SQLClient, error) {
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to %s database: %w", dialect, err)
+	}
+	return &SQLClient{db: db, dialect: dialect, numbered: numbered}, nil
+}
+
+// Close releases the database connections
+func (s *SQLClient) Close() error {
+	return s.db.Close()
+}
+
+// rebind rewrites ? placeholders as $1, $2, ... for PostgreSQL
+func (s *SQLClient) rebind(query string) string {
+	if !s.numbered {
+		return query
+	}
+	var rebound strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			rebound.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		rebound.WriteRune(r)
+	}
+	return rebound.String()
+}
+
+func (s *SQLClient) exec(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	result, err := s.db.ExecContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (s *SQLClient) query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return s.db.QueryContext(ctx, s.rebind(query), args...)
+}
+
+func (s *SQLClient) queryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return s.db.QueryRowContext(ctx, s.rebind(query), args...)
+}
+
+// scanner is satisfied by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAll collects every row of a query, closing the rows when done
+func scanAll[T any](rows *sql.Rows, err error, scan func(scanner) (*T, error)) ([]T, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []T{}
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	return items, rows.Err()
+}
+
+// toJSON encodes a value for a JSON column, storing nil as NULL
+func toJSON(value interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if string(encoded) == "null" {
+		return nil, nil
+	}
+	return string(encoded), nil
+}
+
+func fromJSON(data []byte, target interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, target)
+}
+
+const testResultColumns = "id, user_id, request_id, suite_id, base_url, status, results, scenarios, metadata, tags, created_at"
+
+func scanTestResult(row scanner) (*TestResult, error) {
+	var result TestResult
+	var results, scenarios, metadata, tags []byte
+	err := row.Scan(&result.ID, &result.UserID, &result.RequestID, &result.SuiteID, &result.BaseURL,
+		&result.Status, &results, &scenarios, &metadata, &tags, &result.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := fromJSON(results, &result.Results); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal results: %w", err)
+	}
+	if err := fromJSON(scenarios, &result.Scenarios); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scenarios: %w", err)
+	}
+	if err := fromJSON(metadata, &result.Metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+	if err := fromJSON(tags, &result.Tags); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+	return &result, nil
+}
+
+// testResultValues returns the JSON encoded results, scenarios, metadata and tags
+func testResultValues(result TestResult) ([]interface{}, error) {
+	values := make([]interface{}, 4)
+	for i, value := range []interface{}{result.Results, result.Scenarios, result.Metadata, result.Tags} {
+		encoded, err := toJSON(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode test result: %w", err)
+		}
+		values[i] = encoded
+	}
+	if values[0] == nil {
+		values[0] = "[]"
+	}
+	return values, nil
+}
+
+// StoreTestResult stores a test execution result
+func (s *SQLClient) StoreTestResult(ctx context.Context, userID string, result *exec.CheckResponse) error {
+	log := logging.FromContext(ctx)
+
+	testResult := newTestResult(userID, result)
+	values, err := testResultValues(testResult)
+	if err != nil {
+		return err
+	}
+	_, err = s.exec(ctx, "INSERT INTO test_results ("+testResultColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		testResult.ID, testResult.UserID, testResult.RequestID, testResult.SuiteID, testResult.BaseURL,
+		testResult.Status, values[0], values[1], values[2], values[3], testResult.CreatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to store test result: %w", err)
+	}
+
+	log.Infof("Successfully stored test result with ID: %s", result.RequestID)
+	return nil
+}
+
+// UpdateTestResult replaces the status and results of a stored test result
+func (s *SQLClient) UpdateTestResult(ctx context.Context, userID string, result *exec.CheckResponse) error {
+	log := logging.FromContext(ctx)
+
+	testResult := newTestResult(userID, result)
+	values, err := testResultValues(testResult)
+	if err != nil {
+		return err
+	}
+	_, err = s.exec(ctx, "UPDATE test_results SET status = ?, results = ?, scenarios = ?, metadata = ? WHERE id = ? AND user_id = ?",
+		testResult.Status, values[0], values[1], values[2], result.RequestID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update test result: %w", err)
+	}
+
+	log.Infof("Updated test result %s to status %s", result.RequestID, result.Status)
+	return nil
+}
+
+// GetTestResult retrieves a specific test result by request ID
+func (s *SQLClient) GetTestResult(ctx context.Context, userID, requestID string) (*TestResult, error) {
+	result, err := scanTestResult(s.queryRow(ctx,
+		"SELECT "+testResultColumns+" FROM test_results WHERE id = ? AND user_id = ?", requestID, userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrTestResultNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve test result: %w", err)
+	}
+	return result, nil
+}
+
+// GetUserTestResults retrieves a page of the test results of a user. The
+// results are ordered by creation time and then ID, and pages continue from
+// the last result of the previous one rather than an offset, so results
+// stored while paging do not shift later pages.
+func (s *SQLClient) GetUserTestResults(ctx context.Context, userID string, query ResultQuery) (*ResultPage, error) {
+	cursor, err := decodeCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions, args := s.resultConditions(userID, query)
+	direction, after := "DESC", "<"
+	if query.Ascending {
+		direction, after = "ASC", ">"
+	}
+	if cursor != nil {
+		conditions = append(conditions, "(created_at "+after+" ? OR (created_at = ? AND id "+after+" ?))")
+		args = append(args, cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+
+	statement := "SELECT " + testResultColumns + " FROM test_results WHERE " + strings.Join(conditions, " AND ") +
+		" ORDER BY created_at " + direction + ", id " + direction
+	if limit := query.fetchLimit(); limit > 0 {
+		statement += " LIMIT ?"
+		args = append(args, limit)
+	}
+	rows, err := s.query(ctx, statement, args...)
+	results, err := scanAll(rows, err, scanTestResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user test results: %w", err)
+	}
+	return query.page(results), nil
+}
+
+// resultConditions builds the WHERE conditions of the filters of a query
+func (s *SQLClient) resultConditions(userID string, query ResultQuery) ([]string, []interface{}) {
+	conditions := []string{"user_id = ?"}
+	args := []interface{}{userID}
+	if len(query.Statuses) > 0 {
+		conditions = append(conditions, "status IN (?"+strings.Repeat(", ?", len(query.Statuses)-1)+")")
+		for _, status := range query.Statuses {
+			args = append(args, status)
+		}
+	}
+	if query.BaseURL != "" {
+		conditions = append(conditions, "base_url = ?")
+		args = append(args, query.BaseURL)
+	}
+	if query.SuiteID != "" {
+		conditions = append(conditions, "suite_id = ?")
+		args = append(args, query.SuiteID)
+	}
+	for _, tag := range query.Tags {
+		if s.dialect == DriverPostgres {
+			encoded, _ := json.Marshal([]string{tag})
+			conditions = append(conditions, "tags @> ?")
+			args = append(args, string(encoded))
+		} else {
+			conditions = append(conditions, "EXISTS (SELECT 1 FROM json_each(test_results.tags) WHERE json_each.value = ?)")
+			args = append(args, tag)
+		}
+	}
+	if query.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, query.CreatedFrom.UTC())
+	}
+	if query.CreatedTo != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, query.CreatedTo.UTC())
+	}
+	return conditions, args
+}
+
+// DeleteTestResult removes a test result by request ID
+func (s *SQLClient) DeleteTestResult(ctx context.Context, userID, requestID string) error {
+	log := logging.FromContext(ctx)
+
+	deleted, err := s.exec(ctx, "DELETE FROM test_results WHERE id = ? AND user_id = ?", requestID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete test result: %w", err)
+	}
+	if deleted == 0 {
+		return ErrTestResultNotFound
+	}
+
+	log.Infof("Successfully deleted test result with ID: %s", requestID)
+	return nil
+}
+
+// DeleteTestResults removes the test results of a user matching the
+// filters of a query, ignoring its limit, cursor and order
+func (s *SQLClient) DeleteTestResults(ctx context.Context, userID string, query ResultQuery) (int64, error) {
+	conditions, args := s.resultConditions(userID, query)
+	deleted, err := s.exec(ctx, "DELETE FROM test_results WHERE "+strings.Join(conditions, " AND "), args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete test results: %w", err)
+	}
+	return deleted, nil
+}
+
+// DeleteTestResultsBefore removes the test results of every user created
+// before the given time
+func (s *SQLClient) DeleteTestResultsBefore(ctx context.Context, before time.Time) (int64, error) {
+	deleted, err := s.exec(ctx, "DELETE FROM test_results WHERE created_at < ?", before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired test results: %w", err)
+	}
+	return deleted, nil
+}
+
+// TrimSuiteTestResults removes all but the newest results of each suite
+func (s *SQLClient) TrimSuiteTestResults(ctx context.Context, keep int) (int64, error) {
+	deleted, err := s.exec(ctx, `DELETE FROM test_results WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id, suite_id ORDER BY created_at DESC, id DESC) AS position
+        FROM test_results WHERE suite_id <> ''
+    ) ranked WHERE position > ?
+)`, keep)
+	if err != nil {
+		return 0, fmt.Errorf("failed to trim suite test results: %w", err)
+	}
+	return deleted, nil
+}
+
+// GetSuiteTestResults retrieves the results of runs of a suite, newest first
+func (s *SQLClient) GetSuiteTestResults(ctx context.Context, userID, suiteID string, limit int) ([]TestResult, error) {
+	query := "SELECT " + testResultColumns + " FROM test_results WHERE user_id = ? AND suite_id = ? ORDER BY created_at DESC"
+	args := []interface{}{userID, suiteID}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	rows, err := s.query(ctx, query, args...)
+	results, err := scanAll(rows, err, scanTestResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve suite test results: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+// endpointHealthQuery aggregates the checks of each endpoint in a single
+// statement. The checks of a run are collapsed into one outcome per
+// endpoint, window functions then number the runs of each endpoint in
+// order, and failing streaks are found as runs of failures whose position
+// among all runs and among failing runs differ by the same amount. Each
+// streak is joined to the run after it, which is the recovery if present.
+// The statement is completed with the filter conditions, the JSON array
+// expansion and the duration expression of the dialect.
+const endpointHealthQuery = `WITH runs AS (
+    SELECT id, base_url, results, created_at FROM test_results WHERE %s
+),
+elements AS (
+    SELECT runs.id AS run_id, runs.base_url, runs.created_at,
+        UPPER(COALESCE(NULLIF(c.value ->> 'method', ''), 'GET')) AS check_method,
+        c.value ->> 'path' AS check_path,
+        c.value ->> 'status' AS check_status
+    FROM runs, %s
+),
+checks AS (
+    SELECT run_id, base_url, created_at, check_method AS method, check_path AS path,
+        MIN(CASE WHEN check_status = 'PASS' THEN 1 ELSE 0 END) AS passed
+    FROM elements WHERE check_status IN ('PASS', 'FAIL', 'ERROR')
+    GROUP BY run_id, base_url, created_at, check_method, check_path
+),
+ordered AS (
+    SELECT base_url, method, path, created_at, passed,
+        LAG(passed) OVER (PARTITION BY base_url, method, path ORDER BY created_at, run_id) AS previous,
+        ROW_NUMBER() OVER (PARTITION BY base_url, method, path ORDER BY created_at, run_id) AS position,
+        ROW_NUMBER() OVER (PARTITION BY base_url, method, path, passed ORDER BY created_at, run_id) AS outcome_position,
+        COUNT(*) OVER (PARTITION BY base_url, method, path) AS total
+    FROM checks
+),
+streaks AS (
+    SELECT base_url, method, path, COUNT(*) AS length, MIN(created_at) AS failed_at, MAX(position) AS last_position
+    FROM ordered WHERE passed = 0
+    GROUP BY base_url, method, path, position - outcome_position
+),
+recoveries AS (
+    SELECT streaks.base_url, streaks.method, streaks.path,
+        MAX(streaks.length) AS longest_streak,
+        COUNT(ordered.created_at) AS recoveries,
+        AVG(%s) AS mean_recovery
+    FROM streaks LEFT JOIN ordered ON ordered.base_url = streaks.base_url AND ordered.method = streaks.method
+        AND ordered.path = streaks.path AND ordered.position = streaks.last_position + 1
+    GROUP BY streaks.base_url, streaks.method, streaks.path
+)
+SELECT ordered.base_url, ordered.method, ordered.path, COUNT(*), SUM(ordered.passed),
+    SUM(CASE WHEN ordered.previous <> ordered.passed THEN 1 ELSE 0 END),
+    COALESCE(MAX(recoveries.longest_streak), 0), COALESCE(MAX(recoveries.recoveries), 0), MAX(recoveries.mean_recovery),
+    MAX(CASE WHEN ordered.position = ordered.total THEN ordered.passed END), MAX(ordered.created_at)
+FROM ordered LEFT JOIN recoveries ON recoveries.base_url = ordered.base_url AND recoveries.method = ordered.method
+    AND recoveries.path = ordered.path
+GROUP BY ordered.base_url, ordered.method, ordered.path`
+
+// sqliteSeconds converts a time stored by the SQLite driver, which formats
+// times as "2006-01-02 15:04:05.999999999 +0000 UTC", to something
+// julianday can parse
+func sqliteSeconds(column string) string {
+	return "julianday(substr(" + column + ", 1, instr(" + column + ", ' +') - 1))"
+}
+
+// sqlTime scans a time computed by a query. SQLite only returns table
+// columns as time.Time and the stored text for anything derived from them.
+type sqlTime struct {
+	time.Time
+}
+
+func (t *sqlTime) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case time.Time:
+		t.Time = v
+	case string:
+		return t.parse(v)
+	case []byte:
+		return t.parse(string(v))
+	default:
+		return fmt.Errorf("cannot scan %T into a time", value)
+	}
+	return nil
+}
+
+func (t *sqlTime) parse(value string) error {
+	parsed, err := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", value)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+func scanEndpointHealth(row scanner) (*EndpointHealth, error) {
+	var health EndpointHealth
+	var lastPassed int
+	var lastCheckedAt sqlTime
+	err := row.Scan(&health.BaseURL, &health.Method, &health.Path, &health.Runs, &health.Passed, &health.Flips,
+		&health.LongestFailingStreak, &health.Recoveries, &health.MeanTimeToRecoverySeconds, &lastPassed, &lastCheckedAt)
+	if err != nil {
+		return nil, err
+	}
+	health.LastStatus = exec.StatusFail
+	if lastPassed == 1 {
+		health.LastStatus = exec.StatusPass
+	}
+	health.LastCheckedAt = lastCheckedAt.UTC()
+	return &health, nil
+}
+
+// GetEndpointHealth aggregates the checks of the test results of a user
+// matching the filters of a query by endpoint, ignoring its limit, cursor
+// and order
+func (s *SQLClient) GetEndpointHealth(ctx context.Context, userID string, query ResultQuery) ([]EndpointHealth, error) {
+	conditions, args := s.resultConditions(userID, query)
+	elements := "json_each(runs.results) AS c"
+	seconds := "ROUND((" + sqliteSeconds("ordered.created_at") + " - " + sqliteSeconds("streaks.failed_at") + ") * 86400.0, 3)"
+	if s.dialect == DriverPostgres {
+		elements = "jsonb_array_elements(runs.results) AS c(value)"
+		seconds = "ROUND(CAST(EXTRACT(EPOCH FROM ordered.created_at - streaks.failed_at) AS NUMERIC), 3)::DOUBLE PRECISION"
+	}
+
+	statement := fmt.Sprintf(endpointHealthQuery, strings.Join(conditions, " AND "), elements, seconds)
+	rows, err := s.query(ctx, statement, args...)
+	health, err := scanAll(rows, err, scanEndpointHealth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate endpoint health: %w", err)
+	}
+	return sortedHealth(health), nil
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jgfranco17/aeternum/api/logging"
+)
+
+const monitorColumns = "id, user_id, suite_id, name, schedule, interval_seconds, paused, next_run_at, last_run_at, created_at, updated_at"
+
+func scanMonitor(row scanner) (*Monitor, error) {
+	var monitor Monitor
+	var lastRunAt sql.NullTime
+	err := row.Scan(&monitor.ID, &monitor.UserID, &monitor.SuiteID, &monitor.Name, &monitor.Schedule,
+		&monitor.IntervalSeconds, &monitor.Paused, &monitor.NextRunAt, &lastRunAt, &monitor.CreatedAt, &monitor.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if lastRunAt.Valid {
+		monitor.LastRunAt = &lastRunAt.Time
+	}
+	return &monitor, nil
+}
+
+func nullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+// CreateMonitor stores a new monitor for the user, assigning its ID
+func (s *SQLClient) CreateMonitor(ctx context.Context, userID string, monitor *Monitor) (*Monitor, error) {
+	log := logging.FromContext(ctx)
+
+	now := time.Now().UTC()
+	created := *monitor
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+
+	_, err := s.exec(ctx, "INSERT INTO monitors ("+monitorColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		created.ID, created.UserID, created.SuiteID, created.Name, created.Schedule, created.IntervalSeconds,
+		created.Paused, created.NextRunAt.UTC(), nullableTime(created.LastRunAt), created.CreatedAt, created.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store monitor: %w", err)
+	}
+
+	log.Infof("Successfully created monitor with ID: %s", created.ID)
+	return &created, nil
+}
+
+// GetMonitor retrieves a monitor by ID
+func (s *SQLClient) GetMonitor(ctx context.Context, userID, monitorID string) (*Monitor, error) {
+	monitor, err := scanMonitor(s.queryRow(ctx,
+		"SELECT "+monitorColumns+" FROM monitors WHERE id = ? AND user_id = ?", monitorID, userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrMonitorNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve monitor: %w", err)
+	}
+	return monitor, nil
+}
+
+// ListMonitors retrieves all monitors for a user
+func (s *SQLClient) ListMonitors(ctx context.Context, userID string) ([]Monitor, error) {
+	rows, err := s.query(ctx, "SELECT "+monitorColumns+" FROM monitors WHERE user_id = ? ORDER BY created_at", userID)
+	monitors, err := scanAll(rows, err, scanMonitor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve monitors: %w", err)
+	}
+	return monitors, nil
+}
+
+// UpdateMonitor replaces the stored monitor with the given one
+func (s *SQLClient) UpdateMonitor(ctx context.Context, userID string, monitor *Monitor) (*Monitor, error) {
+	log := logging.FromContext(ctx)
+
+	updated := *monitor
+	updated.UserID = userID
+	updated.UpdatedAt = time.Now().UTC()
+
+	count, err := s.exec(ctx,
+		"UPDATE monitors SET suite_id = ?, name = ?, schedule = ?, interval_seconds = ?, paused = ?, next_run_at = ?, last_run_at = ?, updated_at = ? WHERE id = ? AND user_id = ?",
+		updated.SuiteID, updated.Name, updated.Schedule, updated.IntervalSeconds, updated.Paused,
+		updated.NextRunAt.UTC(), nullableTime(updated.LastRunAt), updated.UpdatedAt, monitor.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update monitor: %w", err)
+	}
+	if count == 0 {
+		return nil, ErrMonitorNotFound
+	}
+
+	log.Infof("Successfully updated monitor with ID: %s", monitor.ID)
+	return &updated, nil
+}
+
+// DeleteMonitor removes a monitor by ID
+func (s *SQLClient) DeleteMonitor(ctx context.Context, userID, monitorID string) error {
+	log := logging.FromContext(ctx)
+
+	deleted, err := s.exec(ctx, "DELETE FROM monitors WHERE id = ? AND user_id = ?", monitorID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete monitor: %w", err)
+	}
+	if deleted == 0 {
+		return ErrMonitorNotFound
+	}
+
+	log.Infof("Successfully deleted monitor with ID: %s", monitorID)
+	return nil
+}
+
+// ListDueMonitors retrieves the monitors of every user that are not paused
+// and are due to run at or before the given time
+func (s *SQLClient) ListDueMonitors(ctx context.Context, before time.Time) ([]Monitor, error) {
+	rows, err := s.query(ctx, "SELECT "+monitorColumns+" FROM monitors WHERE paused = ? AND next_run_at <= ? ORDER BY next_run_at",
+		false, before.UTC())
+	monitors, err := scanAll(rows, err, scanMonitor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve due monitors: %w", err)
+	}
+	return monitors, nil
+}
+
+// ClaimMonitorRun moves the monitor to its next run, but only if it is still
+// scheduled for the run the caller saw
+func (s *SQLClient) ClaimMonitorRun(ctx context.Context, monitor *Monitor, nextRunAt time.Time) (bool, error) {
+	claimed, err := s.exec(ctx, "UPDATE monitors SET next_run_at = ?, last_run_at = ? WHERE id = ? AND next_run_at = ?",
+		nextRunAt.UTC(), time.Now().UTC(), monitor.ID, monitor.NextRunAt.UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to claim monitor run: %w", err)
+	}
+	return claimed > 0, nil
+}
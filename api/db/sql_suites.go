@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jgfranco17/aeternum/api/logging"
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+const suiteColumns = "id, user_id, name, description, request, last_status, created_at, updated_at"
+
+func scanSuite(row scanner) (*Suite, error) {
+	var suite Suite
+	var request []byte
+	err := row.Scan(&suite.ID, &suite.UserID, &suite.Name, &suite.Description, &request,
+		&suite.LastStatus, &suite.CreatedAt, &suite.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := fromJSON(request, &suite.Request); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal suite request: %w", err)
+	}
+	return &suite, nil
+}
+
+// CreateSuite stores a new suite for the user, assigning its ID
+func (s *SQLClient) CreateSuite(ctx context.Context, userID string, suite *Suite) (*Suite, error) {
+	log := logging.FromContext(ctx)
+
+	now := time.Now().UTC()
+	created := *suite
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+
+	request, err := json.Marshal(created.Request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode suite request: %w", err)
+	}
+	_, err = s.exec(ctx, "INSERT INTO test_suites ("+suiteColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		created.ID, created.UserID, created.Name, created.Description, string(request),
+		created.LastStatus, created.CreatedAt, created.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store suite: %w", err)
+	}
+
+	log.Infof("Successfully created suite with ID: %s", created.ID)
+	return &created, nil
+}
+
+// GetSuite retrieves a suite by ID
+func (s *SQLClient) GetSuite(ctx context.Context, userID, suiteID string) (*Suite, error) {
+	suite, err := scanSuite(s.queryRow(ctx,
+		"SELECT "+suiteColumns+" FROM test_suites WHERE id = ? AND user_id = ?", suiteID, userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrSuiteNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve suite: %w", err)
+	}
+	return suite, nil
+}
+
+// ListSuites retrieves all suites for a user
+func (s *SQLClient) ListSuites(ctx context.Context, userID string) ([]Suite, error) {
+	rows, err := s.query(ctx, "SELECT "+suiteColumns+" FROM test_suites WHERE user_id = ? ORDER BY created_at", userID)
+	suites, err := scanAll(rows, err, scanSuite)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve suites: %w", err)
+	}
+	return suites, nil
+}
+
+// UpdateSuite replaces the name, description and request of a suite
+func (s *SQLClient) UpdateSuite(ctx context.Context, userID string, suite *Suite) (*Suite, error) {
+	log := logging.FromContext(ctx)
+
+	existing, err := s.GetSuite(ctx, userID, suite.ID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *existing
+	updated.Name = suite.Name
+	updated.Description = suite.Description
+	updated.Request = suite.Request
+	updated.UpdatedAt = time.Now().UTC()
+
+	request, err := json.Marshal(updated.Request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode suite request: %w", err)
+	}
+	_, err = s.exec(ctx, "UPDATE test_suites SET name = ?, description = ?, request = ?, updated_at = ? WHERE id = ? AND user_id = ?",
+		updated.Name, updated.Description, string(request), updated.UpdatedAt, suite.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update suite: %w", err)
+	}
+
+	log.Infof("Successfully updated suite with ID: %s", suite.ID)
+	return &updated, nil
+}
+
+// SetSuiteStatus records the status of the latest run of a suite, returning
+// the status it replaced. The status is only replaced if it is still the one
+// read, so each change of status is returned to exactly one run.
+func (s *SQLClient) SetSuiteStatus(ctx context.Context, userID, suiteID string, status exec.Status) (exec.Status, error) {
+	for range suiteStatusAttempts {
+		existing, err := s.GetSuite(ctx, userID, suiteID)
+		if err != nil {
+			return "", err
+		}
+		updated, err := s.exec(ctx, "UPDATE test_suites SET last_status = ? WHERE id = ? AND user_id = ? AND last_status = ?",
+			status, suiteID, userID, existing.LastStatus)
+		if err != nil {
+			return "", fmt.Errorf("failed to update suite status: %w", err)
+		}
+		if updated > 0 {
+			return existing.LastStatus, nil
+		}
+	}
+	return "", fmt.Errorf("failed to update suite status: it changed %d times while being updated", suiteStatusAttempts)
+}
+
+// DeleteSuite removes a suite by ID
+func (s *SQLClient) DeleteSuite(ctx context.Context, userID, suiteID string) error {
+	log := logging.FromContext(ctx)
+
+	deleted, err := s.exec(ctx, "DELETE FROM test_suites WHERE id = ? AND user_id = ?", suiteID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete suite: %w", err)
+	}
+	if deleted == 0 {
+		return ErrSuiteNotFound
+	}
+
+	log.Infof("Successfully deleted suite with ID: %s", suiteID)
+	return nil
+}
@@ -0,0 +1,232 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jgfranco17/aeternum/api/logging"
+)
+
+const (
+	webhookColumns         = "id, user_id, url, secret, events, active, created_at, updated_at"
+	webhookDeliveryColumns = "id, webhook_id, user_id, event, request_id, attempt, status_code, success, error, duration_ms, created_at"
+	chatDestinationColumns = "id, user_id, suite_id, name, platform, webhook_url, created_at"
+)
+
+func scanWebhook(row scanner) (*Webhook, error) {
+	var webhook Webhook
+	var events []byte
+	err := row.Scan(&webhook.ID, &webhook.UserID, &webhook.URL, &webhook.Secret, &events,
+		&webhook.Active, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := fromJSON(events, &webhook.Events); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook events: %w", err)
+	}
+	return &webhook, nil
+}
+
+func scanWebhookDelivery(row scanner) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	err := row.Scan(&delivery.ID, &delivery.WebhookID, &delivery.UserID, &delivery.Event, &delivery.RequestID,
+		&delivery.Attempt, &delivery.StatusCode, &delivery.Success, &delivery.Error, &delivery.DurationMs, &delivery.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func scanChatDestination(row scanner) (*ChatDestination, error) {
+	var destination ChatDestination
+	err := row.Scan(&destination.ID, &destination.UserID, &destination.SuiteID, &destination.Name,
+		&destination.Platform, &destination.WebhookURL, &destination.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &destination, nil
+}
+
+// CreateWebhook stores a new webhook for the user, assigning its ID
+func (s *SQLClient) CreateWebhook(ctx context.Context, userID string, webhook *Webhook) (*Webhook, error) {
+	log := logging.FromContext(ctx)
+
+	now := time.Now().UTC()
+	created := *webhook
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+
+	events, err := toJSON(created.Events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook events: %w", err)
+	}
+	_, err = s.exec(ctx, "INSERT INTO webhooks ("+webhookColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		created.ID, created.UserID, created.URL, created.Secret, events, created.Active, created.CreatedAt, created.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store webhook: %w", err)
+	}
+
+	log.Infof("Successfully created webhook with ID: %s", created.ID)
+	return &created, nil
+}
+
+// GetWebhook retrieves a webhook by ID
+func (s *SQLClient) GetWebhook(ctx context.Context, userID, webhookID string) (*Webhook, error) {
+	webhook, err := scanWebhook(s.queryRow(ctx,
+		"SELECT "+webhookColumns+" FROM webhooks WHERE id = ? AND user_id = ?", webhookID, userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// ListWebhooks retrieves all webhooks for a user
+func (s *SQLClient) ListWebhooks(ctx context.Context, userID string) ([]Webhook, error) {
+	rows, err := s.query(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE user_id = ? ORDER BY created_at", userID)
+	webhooks, err := scanAll(rows, err, scanWebhook)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// UpdateWebhook replaces the URL, events and active flag of a webhook. The
+// secret is only replaced when a new one is given.
+func (s *SQLClient) UpdateWebhook(ctx context.Context, userID string, webhook *Webhook) (*Webhook, error) {
+	log := logging.FromContext(ctx)
+
+	existing, err := s.GetWebhook(ctx, userID, webhook.ID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *existing
+	updated.URL = webhook.URL
+	updated.Events = webhook.Events
+	updated.Active = webhook.Active
+	if webhook.Secret != "" {
+		updated.Secret = webhook.Secret
+	}
+	updated.UpdatedAt = time.Now().UTC()
+
+	events, err := toJSON(updated.Events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook events: %w", err)
+	}
+	_, err = s.exec(ctx, "UPDATE webhooks SET url = ?, secret = ?, events = ?, active = ?, updated_at = ? WHERE id = ? AND user_id = ?",
+		updated.URL, updated.Secret, events, updated.Active, updated.UpdatedAt, webhook.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	log.Infof("Successfully updated webhook with ID: %s", webhook.ID)
+	return &updated, nil
+}
+
+// DeleteWebhook removes a webhook by ID
+func (s *SQLClient) DeleteWebhook(ctx context.Context, userID, webhookID string) error {
+	log := logging.FromContext(ctx)
+
+	deleted, err := s.exec(ctx, "DELETE FROM webhooks WHERE id = ? AND user_id = ?", webhookID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if deleted == 0 {
+		return ErrWebhookNotFound
+	}
+
+	log.Infof("Successfully deleted webhook with ID: %s", webhookID)
+	return nil
+}
+
+// StoreWebhookDelivery records a delivery attempt, assigning its ID
+func (s *SQLClient) StoreWebhookDelivery(ctx context.Context, delivery *WebhookDelivery) error {
+	_, err := s.exec(ctx, "INSERT INTO webhook_deliveries ("+webhookDeliveryColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		uuid.NewString(), delivery.WebhookID, delivery.UserID, delivery.Event, delivery.RequestID, delivery.Attempt,
+		delivery.StatusCode, delivery.Success, delivery.Error, delivery.DurationMs, delivery.CreatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to store webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// ListWebhookDeliveries retrieves the most recent delivery attempts of a webhook
+func (s *SQLClient) ListWebhookDeliveries(ctx context.Context, userID, webhookID string, limit int) ([]WebhookDelivery, error) {
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries WHERE user_id = ? AND webhook_id = ? ORDER BY created_at DESC"
+	args := []interface{}{userID, webhookID}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	rows, err := s.query(ctx, query, args...)
+	deliveries, err := scanAll(rows, err, scanWebhookDelivery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// CreateChatDestination stores a new chat destination, assigning its ID
+func (s *SQLClient) CreateChatDestination(ctx context.Context, userID string, destination *ChatDestination) (*ChatDestination, error) {
+	log := logging.FromContext(ctx)
+
+	created := *destination
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = time.Now().UTC()
+
+	_, err := s.exec(ctx, "INSERT INTO chat_destinations ("+chatDestinationColumns+") VALUES (?, ?, ?, ?, ?, ?, ?)",
+		created.ID, created.UserID, created.SuiteID, created.Name, created.Platform, created.WebhookURL, created.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store chat destination: %w", err)
+	}
+
+	log.Infof("Successfully created chat destination with ID: %s", created.ID)
+	return &created, nil
+}
+
+// GetChatDestination retrieves a chat destination by ID
+func (s *SQLClient) GetChatDestination(ctx context.Context, userID, destinationID string) (*ChatDestination, error) {
+	destination, err := scanChatDestination(s.queryRow(ctx,
+		"SELECT "+chatDestinationColumns+" FROM chat_destinations WHERE id = ? AND user_id = ?", destinationID, userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrChatDestinationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve chat destination: %w", err)
+	}
+	return destination, nil
+}
+
+// ListChatDestinations retrieves all chat destinations for a user
+func (s *SQLClient) ListChatDestinations(ctx context.Context, userID string) ([]ChatDestination, error) {
+	rows, err := s.query(ctx, "SELECT "+chatDestinationColumns+" FROM chat_destinations WHERE user_id = ? ORDER BY created_at", userID)
+	destinations, err := scanAll(rows, err, scanChatDestination)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve chat destinations: %w", err)
+	}
+	return destinations, nil
+}
+
+// DeleteChatDestination removes a chat destination by ID
+func (s *SQLClient) DeleteChatDestination(ctx context.Context, userID, destinationID string) error {
+	log := logging.FromContext(ctx)
+
+	deleted, err := s.exec(ctx, "DELETE FROM chat_destinations WHERE id = ? AND user_id = ?", destinationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete chat destination: %w", err)
+	}
+	if deleted == 0 {
+		return ErrChatDestinationNotFound
+	}
+
+	log.Infof("Successfully deleted chat destination with ID: %s", destinationID)
+	return nil
+}
@@ -0,0 +1,27 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite"
+)
+
+const defaultSQLitePath = "aeternum.db"
+
+// NewSQLiteClient opens an embedded SQLite database file, creating the file
+// if it does not exist yet
+func NewSQLiteClient(ctx context.Context, path string) (*SQLClient, error) {
+	if path == "" {
+		path = defaultSQLitePath
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	// SQLite allows a single writer; sharing one connection also keeps
+	// in-memory databases from being opened once per connection
+	db.SetMaxOpenConns(1)
+	return newSQLClient(ctx, db, DriverSQLite, false)
+}
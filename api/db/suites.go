@@ -0,0 +1,200 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jgfranco17/aeternum/api/logging"
+	exec "github.com/jgfranco17/aeternum/execution"
+	"github.com/supabase-community/postgrest-go"
+)
+
+// Suite represents a saved test execution request that can be run by ID
+type Suite struct {
+	ID          string                    `json:"id" bson:"_id"`
+	UserID      string                    `json:"user_id"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description,omitempty"`
+	Request     exec.TestExecutionRequest `json:"request"`
+	LastStatus  exec.Status               `json:"last_status,omitempty"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+}
+
+// suiteStatusAttempts bounds how often SetSuiteStatus reads the status again
+// after another run of the suite replaced it first
+const suiteStatusAttempts = 5
+
+// CreateSuite stores a new suite for the user, assigning its ID
+func (s *SupabaseClient) CreateSuite(ctx context.Context, userID string, suite *Suite) (*Suite, error) {
+	log := logging.FromContext(ctx)
+
+	now := time.Now()
+	created := *suite
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+
+	_, _, err := s.client.From("test_suites").Insert(created, false, "", "", "").Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to store suite: %w", err)
+	}
+
+	log.Infof("Successfully created suite with ID: %s", created.ID)
+	return &created, nil
+}
+
+// GetSuite retrieves a suite by ID
+func (s *SupabaseClient) GetSuite(ctx context.Context, userID, suiteID string) (*Suite, error) {
+	data, _, err := s.client.From("test_suites").
+		Select("*", "exact", false).
+		Eq("id", suiteID).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve suite: %w", err)
+	}
+
+	var suites []Suite
+	if err := json.Unmarshal(data, &suites); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal suite: %w", err)
+	}
+	if len(suites) == 0 {
+		return nil, ErrSuiteNotFound
+	}
+	return &suites[0], nil
+}
+
+// ListSuites retrieves all suites for a user
+func (s *SupabaseClient) ListSuites(ctx context.Context, userID string) ([]Suite, error) {
+	log := logging.FromContext(ctx)
+
+	data, _, err := s.client.From("test_suites").
+		Select("*", "exact", false).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve suites: %w", err)
+	}
+
+	suites := []Suite{}
+	if err := json.Unmarshal(data, &suites); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal suites: %w", err)
+	}
+
+	log.Infof("Successfully retrieved %d suites for user: %s", len(suites), userID)
+	return suites, nil
+}
+
+// UpdateSuite replaces the name, description and request of a suite
+func (s *SupabaseClient) UpdateSuite(ctx context.Context, userID string, suite *Suite) (*Suite, error) {
+	log := logging.FromContext(ctx)
+
+	existing, err := s.GetSuite(ctx, userID, suite.ID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *existing
+	updated.Name = suite.Name
+	updated.Description = suite.Description
+	updated.Request = suite.Request
+	updated.UpdatedAt = time.Now()
+
+	_, _, err = s.client.From("test_suites").
+		Update(updated, "", "").
+		Eq("id", suite.ID).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update suite: %w", err)
+	}
+
+	log.Infof("Successfully updated suite with ID: %s", suite.ID)
+	return &updated, nil
+}
+
+// SetSuiteStatus records the status of the latest run of a suite, returning
+// the status it replaced. The status is only replaced if it is still the one
+// read, so when runs of a suite finish together each change of status is
+// returned to exactly one of them.
+func (s *SupabaseClient) SetSuiteStatus(ctx context.Context, userID, suiteID string, status exec.Status) (exec.Status, error) {
+	for range suiteStatusAttempts {
+		existing, err := s.GetSuite(ctx, userID, suiteID)
+		if err != nil {
+			return "", err
+		}
+
+		data, _, err := s.client.From("test_suites").
+			Update(map[string]interface{}{"last_status": status}, "representation", "").
+			Eq("id", suiteID).
+			Eq("user_id", userID).
+			Eq("last_status", string(existing.LastStatus)).
+			Execute()
+		if err != nil {
+			return "", fmt.Errorf("failed to update suite status: %w", err)
+		}
+
+		var updated []Suite
+		if err := json.Unmarshal(data, &updated); err != nil {
+			return "", fmt.Errorf("failed to unmarshal updated suite: %w", err)
+		}
+		if len(updated) > 0 {
+			return existing.LastStatus, nil
+		}
+	}
+	return "", fmt.Errorf("failed to update suite status: it changed %d times while being updated", suiteStatusAttempts)
+}
+
+// DeleteSuite removes a suite by ID
+func (s *SupabaseClient) DeleteSuite(ctx context.Context, userID, suiteID string) error {
+	log := logging.FromContext(ctx)
+
+	data, _, err := s.client.From("test_suites").
+		Delete("representation", "").
+		Eq("id", suiteID).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to delete suite: %w", err)
+	}
+
+	var deleted []Suite
+	if err := json.Unmarshal(data, &deleted); err == nil && len(deleted) == 0 {
+		return ErrSuiteNotFound
+	}
+
+	log.Infof("Successfully deleted suite with ID: %s", suiteID)
+	return nil
+}
+
+// GetSuiteTestResults retrieves the results of runs of a suite, newest first
+func (s *SupabaseClient) GetSuiteTestResults(ctx context.Context, userID, suiteID string, limit int) ([]TestResult, error) {
+	log := logging.FromContext(ctx)
+
+	query := s.client.From("test_results").
+		Select("*", "exact", false).
+		Eq("user_id", userID).
+		Eq("suite_id", suiteID).
+		Order("created_at", &postgrest.OrderOpts{Ascending: false})
+
+	if limit > 0 {
+		query = query.Limit(limit, "")
+	}
+
+	data, _, err := query.Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve suite test results: %w", err)
+	}
+
+	var results []TestResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal suite test results: %w", err)
+	}
+
+	log.Infof("Successfully retrieved %d test results for suite: %s", len(results), suiteID)
+	return results, nil
+}
@@ -1,11 +1,11 @@
 package db
 
 import (
-	"log"
 	"os"
 	"sync"
 
 	"github.com/jgfranco17/aeternum/api/environment"
+	"github.com/sirupsen/logrus"
 	supabase "github.com/supabase-community/supabase-go"
 )
 
@@ -14,7 +14,9 @@ var (
 	once       sync.Once
 )
 
-// GetSupabaseClient returns a singleton Supabase client
+// GetSupabaseClient returns a singleton Supabase client, used for Supabase
+// Auth whichever storage backend is configured. It is nil if the client
+// could not be created.
 func GetSupabaseClient() *supabase.Client {
 	once.Do(func() {
 		client, err := supabase.NewClient(
@@ -23,7 +25,8 @@ func GetSupabaseClient() *supabase.Client {
 			&supabase.ClientOptions{},
 		)
 		if err != nil {
-			log.Fatalf("Failed to initialize Supabase client: %v", err)
+			logrus.Errorf("Failed to initialize Supabase client: %v", err)
+			return
 		}
 		supaClient = client
 	})
@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jgfranco17/aeternum/api/logging"
+)
+
+// Webhook is a user's subscription to notifications about their runs
+type Webhook struct {
+	ID        string    `json:"id" bson:"_id"`
+	UserID    string    `json:"user_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events,omitempty"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery records a single attempt to deliver a notification
+type WebhookDelivery struct {
+	ID         string    `json:"id" bson:"_id"`
+	WebhookID  string    `json:"webhook_id"`
+	UserID     string    `json:"user_id"`
+	Event      string    `json:"event"`
+	RequestID  string    `json:"request_id,omitempty"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs float64   `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateWebhook stores a new webhook for the user, assigning its ID
+func (s *SupabaseClient) CreateWebhook(ctx context.Context, userID string, webhook *Webhook) (*Webhook, error) {
+	log := logging.FromContext(ctx)
+
+	now := time.Now()
+	created := *webhook
+	created.ID = uuid.NewString()
+	created.UserID = userID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+
+	_, _, err := s.client.From("webhooks").Insert(created, false, "", "", "").Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to store webhook: %w", err)
+	}
+
+	log.Infof("Successfully created webhook with ID: %s", created.ID)
+	return &created, nil
+}
+
+// GetWebhook retrieves a webhook by ID
+func (s *SupabaseClient) GetWebhook(ctx context.Context, userID, webhookID string) (*Webhook, error) {
+	data, _, err := s.client.From("webhooks").
+		Select("*", "exact", false).
+		Eq("id", webhookID).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve webhook: %w", err)
+	}
+
+	var webhooks []Webhook
+	if err := json.Unmarshal(data, &webhooks); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil, ErrWebhookNotFound
+	}
+	return &webhooks[0], nil
+}
+
+// ListWebhooks retrieves all webhooks for a user
+func (s *SupabaseClient) ListWebhooks(ctx context.Context, userID string) ([]Webhook, error) {
+	data, _, err := s.client.From("webhooks").
+		Select("*", "exact", false).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve webhooks: %w", err)
+	}
+
+	webhooks := []Webhook{}
+	if err := json.Unmarshal(data, &webhooks); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// UpdateWebhook replaces the URL, events and active flag of a webhook. The
+// secret is only replaced when a new one is given.
+func (s *SupabaseClient) UpdateWebhook(ctx context.Context, userID string, webhook *Webhook) (*Webhook, error) {
+	log := logging.FromContext(ctx)
+
+	existing, err := s.GetWebhook(ctx, userID, webhook.ID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *existing
+	updated.URL = webhook.URL
+	updated.Events = webhook.Events
+	updated.Active = webhook.Active
+	if webhook.Secret != "" {
+		updated.Secret = webhook.Secret
+	}
+	updated.UpdatedAt = time.Now()
+
+	_, _, err = s.client.From("webhooks").
+		Update(updated, "", "").
+		Eq("id", webhook.ID).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	log.Infof("Successfully updated webhook with ID: %s", webhook.ID)
+	return &updated, nil
+}
+
+// DeleteWebhook removes a webhook by ID
+func (s *SupabaseClient) DeleteWebhook(ctx context.Context, userID, webhookID string) error {
+	log := logging.FromContext(ctx)
+
+	data, _, err := s.client.From("webhooks").
+		Delete("representation", "").
+		Eq("id", webhookID).
+		Eq("user_id", userID).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	var deleted []Webhook
+	if err := json.Unmarshal(data, &deleted); err == nil && len(deleted) == 0 {
+		return ErrWebhookNotFound
+	}
+
+	log.Infof("Successfully deleted webhook with ID: %s", webhookID)
+	return nil
+}
+
+// StoreWebhookDelivery records a delivery attempt, assigning its ID
+func (s *SupabaseClient) StoreWebhookDelivery(ctx context.Context, delivery *WebhookDelivery) error {
+	stored := *delivery
+	stored.ID = uuid.NewString()
+	_, _, err := s.client.From("webhook_deliveries").Insert(stored, false, "", "", "").Execute()
+	if err != nil {
+		return fmt.Errorf("failed to store webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// ListWebhookDeliveries retrieves the most recent delivery attempts of a webhook
+func (s *SupabaseClient) ListWebhookDeliveries(ctx context.Context, userID, webhookID string, limit int) ([]WebhookDelivery, error) {
+	query := s.client.From("webhook_deliveries").
+		Select("*", "exact", false).
+		Eq("user_id", userID).
+		Eq("webhook_id", webhookID).
+		Order("created_at", nil)
+
+	if limit > 0 {
+		query = query.Limit(limit, "")
+	}
+
+	data, _, err := query.Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve webhook deliveries: %w", err)
+	}
+
+	deliveries := []WebhookDelivery{}
+	if err := json.Unmarshal(data, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
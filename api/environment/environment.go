@@ -2,6 +2,7 @@ package environment
 
 import (
 	"os"
+	"strconv"
 )
 
 const (
@@ -12,11 +13,31 @@ const (
 )
 
 const (
-	ENV_KEY_ENVIRONMENT = "ENVIRONMENT"
-	ENV_KEY_VERSION     = "APP_VERSION"
-	ENV_KEY_JWT_SECRET  = "AETERNUM_JWT_SECRET"
-	ENV_KEY_DB_URL      = "AETERNUM_DB_URL"
-	ENV_KEY_DB_KEY      = "AETERNUM_DB_KEY"
+	ENV_KEY_ENVIRONMENT             = "ENVIRONMENT"
+	ENV_KEY_VERSION                 = "APP_VERSION"
+	ENV_KEY_JWT_SECRET              = "AETERNUM_JWT_SECRET"
+	ENV_KEY_DB_URL                  = "AETERNUM_DB_URL"
+	ENV_KEY_DB_KEY                  = "AETERNUM_DB_KEY"
+	ENV_KEY_DB_DRIVER               = "AETERNUM_DB_DRIVER"
+	ENV_KEY_DB_DSN                  = "AETERNUM_DB_DSN"
+	ENV_KEY_MAX_CONCURRENCY         = "AETERNUM_MAX_CONCURRENCY"
+	ENV_KEY_MAX_REQUESTS_PER_SECOND = "AETERNUM_MAX_REQUESTS_PER_SECOND"
+	ENV_KEY_SCHEDULER_POLL_SECONDS  = "AETERNUM_SCHEDULER_POLL_SECONDS"
+	ENV_KEY_RUN_WORKERS             = "AETERNUM_RUN_WORKERS"
+	ENV_KEY_RUN_QUEUE_DEPTH         = "AETERNUM_RUN_QUEUE_DEPTH"
+	ENV_KEY_PUBLIC_URL              = "AETERNUM_PUBLIC_URL"
+	ENV_KEY_SMTP_HOST               = "AETERNUM_SMTP_HOST"
+	ENV_KEY_SMTP_PORT               = "AETERNUM_SMTP_PORT"
+	ENV_KEY_SMTP_USERNAME           = "AETERNUM_SMTP_USERNAME"
+	ENV_KEY_SMTP_PASSWORD           = "AETERNUM_SMTP_PASSWORD"
+	ENV_KEY_SMTP_FROM               = "AETERNUM_SMTP_FROM"
+	ENV_KEY_SMTP_RECIPIENTS         = "AETERNUM_SMTP_RECIPIENTS"
+	ENV_KEY_SMTP_STARTTLS           = "AETERNUM_SMTP_STARTTLS"
+	ENV_KEY_SMTP_DEDUP_MINUTES      = "AETERNUM_SMTP_DEDUP_MINUTES"
+	ENV_KEY_RETENTION_DAYS          = "AETERNUM_RETENTION_DAYS"
+	ENV_KEY_RETENTION_SUITE_RUNS    = "AETERNUM_RETENTION_SUITE_RUNS"
+	ENV_KEY_RETENTION_SWEEP_MINUTES = "AETERNUM_RETENTION_SWEEP_MINUTES"
+	ENV_KEY_FLAKY_THRESHOLD         = "AETERNUM_FLAKY_THRESHOLD"
 )
 
 func IsLocalEnvironment() bool {
@@ -34,3 +55,31 @@ func GetEnvWithDefault(key string, defaultValue string) string {
 func GetApplicationEnv() string {
 	return GetEnvWithDefault(ENV_KEY_ENVIRONMENT, APPLICATION_ENV_LOCAL)
 }
+
+// GetIntEnvWithDefault reads an integer from the environment, falling back
+// to the default if the variable is unset or not a valid integer.
+func GetIntEnvWithDefault(key string, defaultValue int) int {
+	value, present := os.LookupEnv(key)
+	if !present {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return defaultValue
+	}
+	return parsed
+}
+
+// GetFloatEnvWithDefault reads a number from the environment, falling back
+// to the default if the variable is unset or not a valid number.
+func GetFloatEnvWithDefault(key string, defaultValue float64) float64 {
+	value, present := os.LookupEnv(key)
+	if !present {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return parsed
+}
@@ -28,3 +28,23 @@ func TestCheckLocalEnvironment(t *testing.T) {
 	t.Setenv(ENV_KEY_ENVIRONMENT, APPLICATION_ENV_LOCAL)
 	assert.True(t, IsLocalEnvironment())
 }
+
+func TestGetIntEnvWithDefault(t *testing.T) {
+	t.Setenv(ENV_KEY_MAX_CONCURRENCY, "25")
+	assert.Equal(t, 25, GetIntEnvWithDefault(ENV_KEY_MAX_CONCURRENCY, 10))
+
+	t.Setenv(ENV_KEY_MAX_CONCURRENCY, "not-a-number")
+	assert.Equal(t, 10, GetIntEnvWithDefault(ENV_KEY_MAX_CONCURRENCY, 10))
+
+	assert.Equal(t, 3, GetIntEnvWithDefault("AETERNUM_UNSET_VARIABLE", 3))
+}
+
+func TestGetFloatEnvWithDefault(t *testing.T) {
+	t.Setenv(ENV_KEY_FLAKY_THRESHOLD, "0.35")
+	assert.Equal(t, 0.35, GetFloatEnvWithDefault(ENV_KEY_FLAKY_THRESHOLD, 0.2))
+
+	t.Setenv(ENV_KEY_FLAKY_THRESHOLD, "often")
+	assert.Equal(t, 0.2, GetFloatEnvWithDefault(ENV_KEY_FLAKY_THRESHOLD, 0.2))
+
+	assert.Equal(t, 0.5, GetFloatEnvWithDefault("AETERNUM_UNSET_VARIABLE", 0.5))
+}
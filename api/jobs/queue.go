@@ -0,0 +1,106 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jgfranco17/aeternum/api/environment"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultWorkers    = 4
+	defaultQueueDepth = 100
+)
+
+var ErrQueueFull = errors.New("run queue is full")
+
+var (
+	QueueDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "aeternum_run_queue_depth",
+			Help: "Number of asynchronous runs waiting for a worker",
+		},
+	)
+	QueueWorkers = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "aeternum_run_queue_workers",
+			Help: "Number of workers executing asynchronous runs",
+		},
+	)
+	QueueBusyWorkers = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "aeternum_run_queue_busy_workers",
+			Help: "Number of workers currently executing a run",
+		},
+	)
+)
+
+// Job is a unit of work executed by a queue worker.
+type Job func(ctx context.Context)
+
+// Queue runs jobs on a fixed number of workers. Jobs are buffered up to
+// the queue depth; submitting to a full queue fails instead of blocking.
+type Queue struct {
+	jobs    chan Job
+	workers int
+}
+
+// NewQueue creates a queue with the given number of workers and queue
+// depth. Values that are not positive use the defaults, as a queue that
+// cannot hold any job would reject every run not picked up immediately.
+func NewQueue(workers int, depth int) *Queue {
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	if depth <= 0 {
+		depth = defaultQueueDepth
+	}
+	return &Queue{
+		jobs:    make(chan Job, depth),
+		workers: workers,
+	}
+}
+
+// NewQueueFromEnvironment creates a queue sized by the server configuration.
+func NewQueueFromEnvironment() *Queue {
+	return NewQueue(
+		environment.GetIntEnvWithDefault(environment.ENV_KEY_RUN_WORKERS, defaultWorkers),
+		environment.GetIntEnvWithDefault(environment.ENV_KEY_RUN_QUEUE_DEPTH, defaultQueueDepth),
+	)
+}
+
+// Start launches the workers, which run until the context is done. Jobs
+// are given the same context.
+func (q *Queue) Start(ctx context.Context) {
+	QueueWorkers.Add(float64(q.workers))
+	for i := 0; i < q.workers; i++ {
+		go func() {
+			defer QueueWorkers.Dec()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-q.jobs:
+					QueueDepth.Dec()
+					QueueBusyWorkers.Inc()
+					job(ctx)
+					QueueBusyWorkers.Dec()
+				}
+			}
+		}()
+	}
+}
+
+// Submit adds a job to the queue, returning ErrQueueFull if there is no room.
+func (q *Queue) Submit(job Job) error {
+	QueueDepth.Inc()
+	select {
+	case q.jobs <- job:
+		return nil
+	default:
+		QueueDepth.Dec()
+		return ErrQueueFull
+	}
+}
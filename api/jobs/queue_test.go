@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueRunsSubmittedJobs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue := NewQueue(2, 10)
+	queue.Start(ctx)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	ran := 0
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		require.NoError(t, queue.Submit(func(ctx context.Context) {
+			defer wg.Done()
+			mu.Lock()
+			ran++
+			mu.Unlock()
+		}))
+	}
+	wg.Wait()
+	assert.Equal(t, 5, ran)
+}
+
+func TestQueueRejectsJobsWhenFull(t *testing.T) {
+	queue := NewQueue(1, 1)
+
+	// Workers are not started, so the first job fills the queue.
+	require.NoError(t, queue.Submit(func(ctx context.Context) {}))
+	assert.ErrorIs(t, queue.Submit(func(ctx context.Context) {}), ErrQueueFull)
+}
+
+func TestNewQueueDefaults(t *testing.T) {
+	queue := NewQueue(0, 0)
+	assert.Equal(t, defaultWorkers, queue.workers)
+	assert.Equal(t, defaultQueueDepth, cap(queue.jobs))
+
+	queue = NewQueue(-1, -1)
+	assert.Equal(t, defaultWorkers, queue.workers)
+	assert.Equal(t, defaultQueueDepth, cap(queue.jobs))
+}
@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+)
+
+type activeRun struct {
+	userID string
+	cancel context.CancelFunc
+}
+
+// Runs tracks the queued and executing runs of this server so that they
+// can be cancelled.
+type Runs struct {
+	mu   sync.Mutex
+	runs map[string]activeRun
+}
+
+func NewRuns() *Runs {
+	return &Runs{runs: map[string]activeRun{}}
+}
+
+// Register returns the context for a new run owned by the given user,
+// which is cancelled by Cancel. The run must be released once finished.
+func (r *Runs) Register(runID string, userID string) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs[runID] = activeRun{userID: userID, cancel: cancel}
+	return ctx
+}
+
+// Release stops tracking a run.
+func (r *Runs) Release(runID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if run, ok := r.runs[runID]; ok {
+		run.cancel()
+		delete(r.runs, runID)
+	}
+}
+
+// Cancel cancels a run owned by the user, returning false if no such run
+// is queued or executing on this server.
+func (r *Runs) Cancel(runID string, userID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run, ok := r.runs[runID]
+	if !ok || run.userID != userID {
+		return false
+	}
+	run.cancel()
+	return true
+}
@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsCancel(t *testing.T) {
+	runs := NewRuns()
+	ctx := runs.Register("run-1", "user")
+
+	assert.False(t, runs.Cancel("run-1", "someone-else"))
+	assert.NoError(t, ctx.Err())
+
+	assert.True(t, runs.Cancel("run-1", "user"))
+	assert.Error(t, ctx.Err())
+
+	runs.Release("run-1")
+	assert.False(t, runs.Cancel("run-1", "user"))
+}
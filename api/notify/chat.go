@@ -0,0 +1,293 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/environment"
+	"github.com/jgfranco17/aeternum/api/logging"
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+const (
+	PlatformSlack   = "slack"
+	PlatformTeams   = "teams"
+	PlatformGeneric = "generic"
+)
+
+const (
+	maxListedFailures = 10
+	chatTimeout       = 10 * time.Second
+)
+
+// Alert is the platform independent content of a chat message.
+type Alert struct {
+	Title     string
+	Status    exec.Status
+	BaseURL   string
+	RequestID string
+	Failures  []string
+	ResultURL string
+}
+
+// NewAlert summarises an event for a chat message. The result link is only
+// included when the server's public URL is known.
+func NewAlert(event Event, publicURL string) Alert {
+	alert := Alert{Status: event.Status}
+	switch {
+	case event.Type == EventSuiteStatusChanged && event.Status == exec.StatusPass:
+		alert.Title = fmt.Sprintf("Suite recovered: %s → %s", event.PreviousStatus, event.Status)
+	case event.Type == EventSuiteStatusChanged:
+		alert.Title = fmt.Sprintf("Suite is failing: %s → %s", event.PreviousStatus, event.Status)
+	default:
+		alert.Title = fmt.Sprintf("Test run finished with status %s", event.Status)
+	}
+	if event.Run == nil {
+		return alert
+	}
+
+	alert.BaseURL = event.Run.BaseURL
+	alert.RequestID = event.Run.RequestID
+	for _, result := range event.Run.Results {
+		if result.StatusCode != string(exec.StatusPass) {
+			alert.Failures = append(alert.Failures, describeFailure(result))
+		}
+	}
+	for _, scenario := range event.Run.Scenarios {
+		if scenario.Status != exec.StatusPass {
+			alert.Failures = append(alert.Failures, scenario.Message)
+		}
+	}
+	if publicURL != "" && alert.RequestID != "" {
+		alert.ResultURL = fmt.Sprintf("%s/v0/tests/results?id=%s", strings.TrimSuffix(publicURL, "/"), url.QueryEscape(alert.RequestID))
+	}
+	return alert
+}
+
+func describeFailure(result exec.CheckResult) string {
+	line := fmt.Sprintf("%s %s: expected %d, got %d", result.Method, result.Path, result.ExpectedStatus, result.ActualStatus)
+	if result.ActualStatus == 0 {
+		line = fmt.Sprintf("%s %s: expected %d, no response", result.Method, result.Path, result.ExpectedStatus)
+	}
+	statusMismatch := fmt.Sprintf("expected status %d, got %d", result.ExpectedStatus, result.ActualStatus)
+	if result.Message != "" && result.Message != statusMismatch {
+		line = fmt.Sprintf("%s (%s)", line, result.Message)
+	}
+	return line
+}
+
+// listedFailures returns the failures to show, noting how many were left out.
+func (a Alert) listedFailures() []string {
+	if len(a.Failures) <= maxListedFailures {
+		return a.Failures
+	}
+	listed := append([]string{}, a.Failures[:maxListedFailures]...)
+	return append(listed, fmt.Sprintf("…and %d more", len(a.Failures)-maxListedFailures))
+}
+
+func (a Alert) text() string {
+	lines := []string{fmt.Sprintf("%s (%s)", a.Title, a.BaseURL)}
+	for _, failure := range a.listedFailures() {
+		lines = append(lines, "• "+failure)
+	}
+	if a.ResultURL != "" {
+		lines = append(lines, a.ResultURL)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RenderSlack builds a Slack Block Kit message for an incoming webhook.
+func RenderSlack(alert Alert) map[string]interface{} {
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]interface{}{"type": "plain_text", "text": alert.Title},
+		},
+		{
+			"type": "section",
+			"fields": []map[string]interface{}{
+				{"type": "mrkdwn", "text": fmt.Sprintf("*Status*\n%s", alert.Status)},
+				{"type": "mrkdwn", "text": fmt.Sprintf("*Target*\n%s", alert.BaseURL)},
+			},
+		},
+	}
+	if failures := alert.listedFailures(); len(failures) > 0 {
+		lines := make([]string, len(failures))
+		for i, failure := range failures {
+			lines[i] = "• " + failure
+		}
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": "*Failing checks*\n" + strings.Join(lines, "\n")},
+		})
+	}
+	if alert.ResultURL != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "actions",
+			"elements": []map[string]interface{}{
+				{
+					"type": "button",
+					"text": map[string]interface{}{"type": "plain_text", "text": "View result"},
+					"url":  alert.ResultURL,
+				},
+			},
+		})
+	}
+	return map[string]interface{}{
+		"text":   alert.text(),
+		"blocks": blocks,
+	}
+}
+
+// RenderTeams builds a Microsoft Teams message holding an Adaptive Card.
+func RenderTeams(alert Alert) map[string]interface{} {
+	body := []map[string]interface{}{
+		{"type": "TextBlock", "text": alert.Title, "weight": "Bolder", "size": "Medium", "wrap": true},
+		{
+			"type": "FactSet",
+			"facts": []map[string]interface{}{
+				{"title": "Status", "value": string(alert.Status)},
+				{"title": "Target", "value": alert.BaseURL},
+				{"title": "Run", "value": alert.RequestID},
+			},
+		},
+	}
+	for _, failure := range alert.listedFailures() {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": "- " + failure, "wrap": true, "spacing": "None"})
+	}
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if alert.ResultURL != "" {
+		card["actions"] = []map[string]interface{}{
+			{"type": "Action.OpenUrl", "title": "View result", "url": alert.ResultURL},
+		}
+	}
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{"contentType": "application/vnd.microsoft.card.adaptive", "content": card},
+		},
+	}
+}
+
+// RenderGeneric builds a plain text message accepted by most chat webhooks.
+func RenderGeneric(alert Alert) map[string]interface{} {
+	return map[string]interface{}{"text": alert.text()}
+}
+
+// Render builds the message for the given platform.
+func Render(platform string, alert Alert) (map[string]interface{}, error) {
+	switch platform {
+	case PlatformSlack:
+		return RenderSlack(alert), nil
+	case PlatformTeams:
+		return RenderTeams(alert), nil
+	case PlatformGeneric:
+		return RenderGeneric(alert), nil
+	}
+	return nil, fmt.Errorf("unsupported chat platform '%s'", platform)
+}
+
+// ChatNotifier posts alerts to the chat destinations of a user. To avoid
+// noise, only failing runs and suite recoveries are posted.
+type ChatNotifier struct {
+	dbClient  db.DatabaseClient
+	client    *http.Client
+	publicURL string
+}
+
+func NewChatNotifier(dbClient db.DatabaseClient) *ChatNotifier {
+	return &ChatNotifier{
+		dbClient:  dbClient,
+		client:    &http.Client{Timeout: chatTimeout},
+		publicURL: environment.GetEnvWithDefault(environment.ENV_KEY_PUBLIC_URL, ""),
+	}
+}
+
+func (n *ChatNotifier) Notify(ctx context.Context, event Event) {
+	if !alerting(event) {
+		return
+	}
+	log := logging.FromContext(ctx)
+	destinations, err := n.dbClient.ListChatDestinations(ctx, event.UserID)
+	if err != nil {
+		log.Errorf("Failed to list chat destinations for user %s: %v", event.UserID, err)
+		return
+	}
+	for _, destination := range destinations {
+		if destination.SuiteID != "" && destination.SuiteID != event.SuiteID {
+			continue
+		}
+		if _, err := n.Send(ctx, destination, event); err != nil {
+			log.Warnf("Failed to post alert to chat destination %s: %v", destination.ID, err)
+		}
+	}
+}
+
+func alerting(event Event) bool {
+	switch event.Type {
+	case EventRunCompleted:
+		return event.Status == exec.StatusFail || event.Status == exec.StatusError
+	case EventSuiteStatusChanged:
+		return event.Status == exec.StatusPass
+	}
+	return false
+}
+
+// Send posts the event to a destination, returning the status code of the
+// chat service's response.
+func (n *ChatNotifier) Send(ctx context.Context, destination db.ChatDestination, event Event) (int, error) {
+	message, err := Render(destination.Platform, NewAlert(event, n.publicURL))
+	if err != nil {
+		return 0, err
+	}
+	body, err := json.Marshal(message)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode chat message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, destination.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("chat service responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SampleEvent is a failing run used to try out a chat destination.
+func SampleEvent(userID string) Event {
+	return Event{
+		ID:         "test-message",
+		Type:       EventRunCompleted,
+		OccurredAt: time.Now().UTC(),
+		UserID:     userID,
+		Status:     exec.StatusFail,
+		Run: &exec.CheckResponse{
+			RequestID: "aeternum-test-message",
+			BaseURL:   "https://api.example.com",
+			Status:    exec.StatusFail,
+			Results: []exec.CheckResult{
+				{Path: "/health", Method: http.MethodGet, ExpectedStatus: 200, ActualStatus: 200, StatusCode: string(exec.StatusPass)},
+				{Path: "/users", Method: http.MethodGet, ExpectedStatus: 200, ActualStatus: 503, StatusCode: string(exec.StatusFail), Message: "expected status 200, got 503"},
+			},
+		},
+	}
+}
@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	exec "github.com/jgfranco17/aeternum/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAlertListsFailures(t *testing.T) {
+	alert := NewAlert(SampleEvent("user"), "https://aeternum.example.com/")
+
+	assert.Equal(t, "Test run finished with status FAIL", alert.Title)
+	assert.Equal(t, []string{"GET /users: expected 200, got 503"}, alert.Failures)
+	assert.Equal(t, "https://aeternum.example.com/v0/tests/results?id=aeternum-test-message", alert.ResultURL)
+
+	assert.Empty(t, NewAlert(SampleEvent("user"), "").ResultURL)
+}
+
+func TestListedFailuresAreCapped(t *testing.T) {
+	alert := Alert{}
+	for i := 0; i < maxListedFailures+3; i++ {
+		alert.Failures = append(alert.Failures, "failure")
+	}
+
+	listed := alert.listedFailures()
+	require.Len(t, listed, maxListedFailures+1)
+	assert.Equal(t, "…and 3 more", listed[maxListedFailures])
+}
+
+func TestRenderSlackAndTeams(t *testing.T) {
+	alert := NewAlert(SampleEvent("user"), "https://aeternum.example.com")
+
+	slack, err := json.Marshal(RenderSlack(alert))
+	require.NoError(t, err)
+	assert.Contains(t, string(slack), `"type":"header"`)
+	assert.Contains(t, string(slack), "GET /users: expected 200, got 503")
+	assert.Contains(t, string(slack), alert.ResultURL)
+
+	teams, err := json.Marshal(RenderTeams(alert))
+	require.NoError(t, err)
+	assert.Contains(t, string(teams), `"contentType":"application/vnd.microsoft.card.adaptive"`)
+	assert.Contains(t, string(teams), `"type":"Action.OpenUrl"`)
+	assert.Contains(t, string(teams), "GET /users: expected 200, got 503")
+
+	_, err = Render("irc", alert)
+	assert.ErrorContains(t, err, "unsupported chat platform")
+}
+
+func TestAlerting(t *testing.T) {
+	assert.True(t, alerting(Event{Type: EventRunCompleted, Status: exec.StatusFail}))
+	assert.True(t, alerting(Event{Type: EventRunCompleted, Status: exec.StatusError}))
+	assert.False(t, alerting(Event{Type: EventRunCompleted, Status: exec.StatusPass}))
+	assert.False(t, alerting(Event{Type: EventRunCompleted, Status: exec.StatusCancelled}))
+	assert.True(t, alerting(Event{Type: EventSuiteStatusChanged, Status: exec.StatusPass, PreviousStatus: exec.StatusFail}))
+	assert.False(t, alerting(Event{Type: EventSuiteStatusChanged, Status: exec.StatusFail, PreviousStatus: exec.StatusPass}))
+}
+
+func TestChatNotifierPostsToMatchingDestinations(t *testing.T) {
+	var calls atomic.Int32
+	var message map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&message))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &fakeDB{chats: []db.ChatDestination{
+		{ID: "all", Platform: PlatformGeneric, WebhookURL: server.URL},
+		{ID: "other-suite", SuiteID: "other", Platform: PlatformGeneric, WebhookURL: server.URL},
+	}}
+	notifier := NewChatNotifier(client)
+
+	event := SampleEvent("user")
+	event.SuiteID = "suite"
+	notifier.Notify(context.Background(), event)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Contains(t, message["text"], "GET /users: expected 200, got 503")
+
+	event.Status = exec.StatusPass
+	notifier.Notify(context.Background(), event)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestChatNotifierSendReportsRejection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	statusCode, err := NewChatNotifier(&fakeDB{}).Send(context.Background(), db.ChatDestination{
+		Platform:   PlatformSlack,
+		WebhookURL: server.URL,
+	}, SampleEvent("user"))
+	assert.Equal(t, http.StatusForbidden, statusCode)
+	assert.ErrorContains(t, err, "status 403")
+}
@@ -0,0 +1,279 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jgfranco17/aeternum/api/environment"
+	"github.com/jgfranco17/aeternum/api/logging"
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+const (
+	defaultSMTPPort     = 587
+	defaultDedupMinutes = 60
+	smtpTimeout         = 10 * time.Second
+)
+
+// SMTPConfig holds the mail server and recipients for email alerts.
+type SMTPConfig struct {
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	From       string
+	Recipients []string
+	StartTLS   bool
+}
+
+// SMTPConfigFromEnvironment reads the SMTP settings, reporting false when
+// email alerts are not configured.
+func SMTPConfigFromEnvironment() (SMTPConfig, bool) {
+	config := SMTPConfig{
+		Host:     environment.GetEnvWithDefault(environment.ENV_KEY_SMTP_HOST, ""),
+		Port:     environment.GetIntEnvWithDefault(environment.ENV_KEY_SMTP_PORT, defaultSMTPPort),
+		Username: environment.GetEnvWithDefault(environment.ENV_KEY_SMTP_USERNAME, ""),
+		Password: environment.GetEnvWithDefault(environment.ENV_KEY_SMTP_PASSWORD, ""),
+		From:     environment.GetEnvWithDefault(environment.ENV_KEY_SMTP_FROM, ""),
+		StartTLS: true,
+	}
+	for _, recipient := range strings.Split(environment.GetEnvWithDefault(environment.ENV_KEY_SMTP_RECIPIENTS, ""), ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			config.Recipients = append(config.Recipients, recipient)
+		}
+	}
+	if startTLS, err := strconv.ParseBool(environment.GetEnvWithDefault(environment.ENV_KEY_SMTP_STARTTLS, "true")); err == nil {
+		config.StartTLS = startTLS
+	}
+	if config.From == "" {
+		config.From = config.Username
+	}
+	return config, config.Host != "" && config.From != "" && len(config.Recipients) > 0
+}
+
+// EmailNotifier mails a summary of failing runs. Repeated failures of the
+// same suite (or target, for ad-hoc runs) are only mailed once per window,
+// and a passing run resets the window so the next failure is sent at once.
+// The window is tracked in memory, so it holds per process: replicas of the
+// server, or a restart, may each send the same alert once.
+type EmailNotifier struct {
+	config    SMTPConfig
+	publicURL string
+	window    time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+}
+
+func NewEmailNotifier(config SMTPConfig) *EmailNotifier {
+	return &EmailNotifier{
+		config:    config,
+		publicURL: environment.GetEnvWithDefault(environment.ENV_KEY_PUBLIC_URL, ""),
+		window:    time.Duration(environment.GetIntEnvWithDefault(environment.ENV_KEY_SMTP_DEDUP_MINUTES, defaultDedupMinutes)) * time.Minute,
+		now:       time.Now,
+		lastSent:  map[string]time.Time{},
+	}
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, event Event) {
+	if event.Type != EventRunCompleted || event.Run == nil {
+		return
+	}
+	key := dedupKey(event)
+	switch event.Status {
+	case exec.StatusPass:
+		n.forget(key)
+		return
+	case exec.StatusFail, exec.StatusError:
+	default:
+		return
+	}
+
+	log := logging.FromContext(ctx)
+	if !n.claim(key) {
+		log.Debugf("Skipping email alert for %s, already sent within %s", key, n.window)
+		return
+	}
+	message, err := n.message(event)
+	if err == nil {
+		err = n.send(message)
+	}
+	if err != nil {
+		// Let the next failure try again instead of staying silent for a window
+		n.forget(key)
+		log.Errorf("Failed to send email alert for run %s: %v", event.Run.RequestID, err)
+	}
+}
+
+func dedupKey(event Event) string {
+	if event.SuiteID != "" {
+		return event.UserID + "/suite/" + event.SuiteID
+	}
+	return event.UserID + "/target/" + event.Run.BaseURL
+}
+
+// claim reserves the right to send for a key, unless an email went out for
+// it within the window.
+func (n *EmailNotifier) claim(key string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	now := n.now()
+	if sent, ok := n.lastSent[key]; ok && now.Sub(sent) < n.window {
+		return false
+	}
+	n.lastSent[key] = now
+	return true
+}
+
+func (n *EmailNotifier) forget(key string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.lastSent, key)
+}
+
+var emailText = template.Must(template.New("text").Parse(`{{.Title}}
+
+Target: {{.BaseURL}}
+Run:    {{.RequestID}}
+{{if .Failures}}
+Failing checks:
+{{range .Failures}}  - {{.}}
+{{end}}{{end}}{{if .ResultURL}}
+View the result: {{.ResultURL}}
+{{end}}`))
+
+var emailHTML = htmltemplate.Must(htmltemplate.New("html").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<h2>{{.Title}}</h2>
+<table>
+<tr><td><strong>Target</strong></td><td>{{.BaseURL}}</td></tr>
+<tr><td><strong>Run</strong></td><td>{{.RequestID}}</td></tr>
+</table>
+{{if .Failures}}<h3>Failing checks</h3>
+<ul>
+{{range .Failures}}<li><code>{{.}}</code></li>
+{{end}}</ul>{{end}}
+{{if .ResultURL}}<p><a href="{{.ResultURL}}">View the result</a></p>{{end}}
+</body>
+</html>
+`))
+
+// message builds a multipart email with plain text and HTML versions of the
+// alert. Unlike chat alerts, every failing check is listed.
+func (n *EmailNotifier) message(event Event) ([]byte, error) {
+	alert := NewAlert(event, n.publicURL)
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	if err := writePart(parts, "text/plain; charset=utf-8", func(w *quotedprintable.Writer) error {
+		return emailText.Execute(w, alert)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to render plain text email: %w", err)
+	}
+	if err := writePart(parts, "text/html; charset=utf-8", func(w *quotedprintable.Writer) error {
+		return emailHTML.Execute(w, alert)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to render HTML email: %w", err)
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	subject := fmt.Sprintf("[Aeternum] %s: %s", event.Status, alert.BaseURL)
+	var message bytes.Buffer
+	headers := []struct{ key, value string }{
+		{"From", n.config.From},
+		{"To", strings.Join(n.config.Recipients, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", n.now().Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@aeternum>", uuid.NewString())},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + parts.Boundary()},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&message, "%s: %s\r\n", header.key, header.value)
+	}
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+	return message.Bytes(), nil
+}
+
+func writePart(parts *multipart.Writer, contentType string, render func(w *quotedprintable.Writer) error) error {
+	part, err := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	encoder := quotedprintable.NewWriter(part)
+	if err := render(encoder); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func (n *EmailNotifier) send(message []byte) error {
+	addr := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+	conn, err := net.DialTimeout("tcp", addr, smtpTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+	client, err := smtp.NewClient(conn, n.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if n.config.StartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP server %s does not support STARTTLS", addr)
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: n.config.Host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if n.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+	if err := client.Mail(n.config.From); err != nil {
+		return fmt.Errorf("sender rejected: %w", err)
+	}
+	for _, recipient := range n.config.Recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("recipient %s rejected: %w", recipient, err)
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("message rejected: %w", err)
+	}
+	return client.Quit()
+}
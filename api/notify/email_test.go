@@ -0,0 +1,203 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	exec "github.com/jgfranco17/aeternum/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpSink is a minimal SMTP server that keeps every message it receives.
+type smtpSink struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []string
+	rcpts    []string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	sink := &smtpSink{listener: listener}
+	go sink.serve()
+	t.Cleanup(func() { listener.Close() })
+	return sink
+}
+
+func (s *smtpSink) config() SMTPConfig {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return SMTPConfig{
+		Host:       addr.IP.String(),
+		Port:       addr.Port,
+		From:       "aeternum@example.com",
+		Recipients: []string{"oncall@example.com", "lead@example.com"},
+	}
+}
+
+func (s *smtpSink) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.session(conn)
+	}
+}
+
+func (s *smtpSink) session(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 sink ready")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			reply("250-sink")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, strings.TrimSpace(line[len("RCPT TO:"):]))
+			s.mu.Unlock()
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, data.String())
+			s.mu.Unlock()
+			reply("250 queued")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (s *smtpSink) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.messages...)
+}
+
+func failingEvent(suiteID string, status exec.Status) Event {
+	event := SampleEvent("user")
+	event.SuiteID = suiteID
+	event.Status = status
+	event.Run.Status = status
+	return event
+}
+
+func TestEmailNotifierSendsMultipartSummary(t *testing.T) {
+	sink := newSMTPSink(t)
+	notifier := NewEmailNotifier(sink.config())
+	notifier.publicURL = "https://aeternum.example.com"
+
+	notifier.Notify(context.Background(), failingEvent("suite", exec.StatusFail))
+
+	messages := sink.received()
+	require.Len(t, messages, 1)
+	assert.Equal(t, []string{"<oncall@example.com>", "<lead@example.com>"}, sink.rcpts)
+
+	message, err := mail.ReadMessage(strings.NewReader(messages[0]))
+	require.NoError(t, err)
+	assert.Equal(t, "[Aeternum] FAIL: https://api.example.com", message.Header.Get("Subject"))
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	bodies := map[string]string{}
+	for {
+		part, err := parts.NextPart()
+		if err != nil {
+			break
+		}
+		var body strings.Builder
+		_, err = bufio.NewReader(part).WriteTo(&body)
+		require.NoError(t, err)
+		bodies[strings.Split(part.Header.Get("Content-Type"), ";")[0]] = body.String()
+	}
+	require.Len(t, bodies, 2)
+	assert.Contains(t, bodies["text/plain"], "GET /users: expected 200, got 503")
+	assert.Contains(t, bodies["text/plain"], "https://aeternum.example.com/v0/tests/results?id=aeternum-test-message")
+	assert.Contains(t, bodies["text/html"], "<code>GET /users: expected 200, got 503</code>")
+}
+
+func TestEmailNotifierDeduplicatesRepeatedFailures(t *testing.T) {
+	sink := newSMTPSink(t)
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	notifier := NewEmailNotifier(sink.config())
+	notifier.now = func() time.Time { return now }
+
+	// A monitor failing every minute only mails once an hour
+	for i := 0; i < 60; i++ {
+		notifier.Notify(context.Background(), failingEvent("suite", exec.StatusFail))
+		now = now.Add(time.Minute)
+	}
+	assert.Len(t, sink.received(), 1)
+
+	notifier.Notify(context.Background(), failingEvent("suite", exec.StatusError))
+	assert.Len(t, sink.received(), 2)
+
+	// Other suites are tracked separately, and recovering resets the window
+	notifier.Notify(context.Background(), failingEvent("other-suite", exec.StatusFail))
+	assert.Len(t, sink.received(), 3)
+	notifier.Notify(context.Background(), failingEvent("suite", exec.StatusPass))
+	notifier.Notify(context.Background(), failingEvent("suite", exec.StatusFail))
+	assert.Len(t, sink.received(), 4)
+}
+
+func TestEmailNotifierRetriesAfterFailedSend(t *testing.T) {
+	config := newSMTPSink(t).config()
+	config.StartTLS = true
+	notifier := NewEmailNotifier(config)
+
+	// The sink does not offer STARTTLS, so the send fails and is not recorded
+	notifier.Notify(context.Background(), failingEvent("suite", exec.StatusFail))
+	assert.True(t, notifier.claim(dedupKey(failingEvent("suite", exec.StatusFail))))
+}
+
+func TestSMTPConfigFromEnvironment(t *testing.T) {
+	t.Setenv("AETERNUM_SMTP_HOST", "smtp.example.com")
+	t.Setenv("AETERNUM_SMTP_USERNAME", "alerts@example.com")
+	t.Setenv("AETERNUM_SMTP_RECIPIENTS", "a@example.com, b@example.com,")
+	t.Setenv("AETERNUM_SMTP_STARTTLS", "false")
+
+	config, ok := SMTPConfigFromEnvironment()
+	assert.True(t, ok)
+	assert.Equal(t, 587, config.Port)
+	assert.Equal(t, "alerts@example.com", config.From)
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, config.Recipients)
+	assert.False(t, config.StartTLS)
+
+	t.Setenv("AETERNUM_SMTP_RECIPIENTS", "")
+	_, ok = SMTPConfigFromEnvironment()
+	assert.False(t, ok)
+}
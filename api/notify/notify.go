@@ -0,0 +1,119 @@
+package notify
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/logging"
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+type EventType string
+
+const (
+	EventRunCompleted       EventType = "run.completed"
+	EventSuiteStatusChanged EventType = "suite.status_changed"
+)
+
+// Event describes something a user may want to be told about.
+type Event struct {
+	ID             string              `json:"id"`
+	Type           EventType           `json:"event"`
+	OccurredAt     time.Time           `json:"occurred_at"`
+	UserID         string              `json:"-"`
+	SuiteID        string              `json:"suite_id,omitempty"`
+	Status         exec.Status         `json:"status"`
+	PreviousStatus exec.Status         `json:"previous_status,omitempty"`
+	Run            *exec.CheckResponse `json:"run"`
+}
+
+// Notifier delivers events over a single channel.
+type Notifier interface {
+	Notify(ctx context.Context, event Event)
+}
+
+// Dispatcher turns finished runs into events and hands them to every
+// notifier in the background.
+type Dispatcher struct {
+	dbClient  db.DatabaseClient
+	notifiers []Notifier
+	wg        sync.WaitGroup
+}
+
+func NewDispatcher(dbClient db.DatabaseClient) *Dispatcher {
+	notifiers := []Notifier{NewWebhookNotifier(dbClient), NewChatNotifier(dbClient)}
+	if config, ok := SMTPConfigFromEnvironment(); ok {
+		notifiers = append(notifiers, NewEmailNotifier(config))
+	}
+	return &Dispatcher{
+		dbClient:  dbClient,
+		notifiers: notifiers,
+	}
+}
+
+// RunCompleted notifies about a finished run without blocking the caller.
+// The context is used after the call returns, so handlers must pass a copy
+// of a pooled request context such as gin's.
+func (d *Dispatcher) RunCompleted(ctx context.Context, userID string, run *exec.CheckResponse) {
+	if d == nil {
+		return
+	}
+	// Notifications outlive the request that finished the run
+	ctx = context.WithoutCancel(ctx)
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		for _, event := range d.events(ctx, userID, run) {
+			for _, notifier := range d.notifiers {
+				d.wg.Add(1)
+				go func(notifier Notifier, event Event) {
+					defer d.wg.Done()
+					notifier.Notify(ctx, event)
+				}(notifier, event)
+			}
+		}
+	}()
+}
+
+// Wait blocks until every notification in progress has been delivered.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) events(ctx context.Context, userID string, run *exec.CheckResponse) []Event {
+	newEvent := func(eventType EventType) Event {
+		return Event{
+			ID:         uuid.NewString(),
+			Type:       eventType,
+			OccurredAt: time.Now().UTC(),
+			UserID:     userID,
+			SuiteID:    run.SuiteID,
+			Status:     run.Status,
+			Run:        run,
+		}
+	}
+	events := []Event{newEvent(EventRunCompleted)}
+	if run.SuiteID == "" || run.Status == exec.StatusCancelled {
+		return events
+	}
+
+	previous, err := d.dbClient.SetSuiteStatus(ctx, userID, run.SuiteID, run.Status)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("Failed to record status of suite %s: %v", run.SuiteID, err)
+		return events
+	}
+	if previous != "" && isHealthy(previous) != isHealthy(run.Status) {
+		changed := newEvent(EventSuiteStatusChanged)
+		changed.PreviousStatus = previous
+		events = append(events, changed)
+	}
+	return events
+}
+
+// A suite flips between healthy and failing; an errored run counts as failing.
+func isHealthy(status exec.Status) bool {
+	return status == exec.StatusPass
+}
@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	exec "github.com/jgfranco17/aeternum/execution"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDB keeps webhooks, deliveries and suite statuses in memory; only the
+// methods used by notifiers are implemented.
+type fakeDB struct {
+	db.DatabaseClient
+	mu         sync.Mutex
+	webhooks   []db.Webhook
+	deliveries []db.WebhookDelivery
+	chats      []db.ChatDestination
+	statuses   map[string]exec.Status
+}
+
+func (f *fakeDB) ListWebhooks(ctx context.Context, userID string) ([]db.Webhook, error) {
+	return f.webhooks, nil
+}
+
+func (f *fakeDB) ListChatDestinations(ctx context.Context, userID string) ([]db.ChatDestination, error) {
+	return f.chats, nil
+}
+
+func (f *fakeDB) StoreWebhookDelivery(ctx context.Context, delivery *db.WebhookDelivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deliveries = append(f.deliveries, *delivery)
+	return nil
+}
+
+func (f *fakeDB) SetSuiteStatus(ctx context.Context, userID, suiteID string, status exec.Status) (exec.Status, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	previous := f.statuses[suiteID]
+	f.statuses[suiteID] = status
+	return previous, nil
+}
+
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recordingNotifier) types() []EventType {
+	types := []EventType{}
+	for _, event := range r.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestDispatcherReportsSuiteStatusChanges(t *testing.T) {
+	client := &fakeDB{statuses: map[string]exec.Status{}}
+	recorder := &recordingNotifier{}
+	dispatcher := &Dispatcher{dbClient: client, notifiers: []Notifier{recorder}}
+
+	run := func(status exec.Status) []EventType {
+		recorder.events = nil
+		dispatcher.RunCompleted(context.Background(), "user", &exec.CheckResponse{SuiteID: "suite", Status: status})
+		dispatcher.Wait()
+		return recorder.types()
+	}
+
+	assert.Equal(t, []EventType{EventRunCompleted}, run(exec.StatusPass))
+	assert.Equal(t, []EventType{EventRunCompleted}, run(exec.StatusPass))
+	assert.ElementsMatch(t, []EventType{EventRunCompleted, EventSuiteStatusChanged}, run(exec.StatusFail))
+	assert.Equal(t, []EventType{EventRunCompleted}, run(exec.StatusError))
+	assert.Equal(t, []EventType{EventRunCompleted}, run(exec.StatusCancelled))
+	assert.ElementsMatch(t, []EventType{EventRunCompleted, EventSuiteStatusChanged}, run(exec.StatusPass))
+
+	for _, event := range recorder.events {
+		if event.Type == EventSuiteStatusChanged {
+			assert.Equal(t, exec.StatusError, event.PreviousStatus)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/logging"
+)
+
+const (
+	SignatureHeader = "X-Aeternum-Signature"
+	EventHeader     = "X-Aeternum-Event"
+	DeliveryHeader  = "X-Aeternum-Delivery"
+)
+
+const (
+	defaultWebhookAttempts = 5
+	defaultWebhookBackoff  = time.Second
+	webhookTimeout         = 10 * time.Second
+)
+
+// Sign returns the signature sent with a webhook body, the hex encoded
+// HMAC-SHA256 of the body keyed with the webhook's secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookNotifier posts events to the webhooks of their user, retrying
+// failed deliveries with exponential backoff and logging every attempt.
+type WebhookNotifier struct {
+	dbClient       db.DatabaseClient
+	client         *http.Client
+	maxAttempts    int
+	initialBackoff time.Duration
+}
+
+func NewWebhookNotifier(dbClient db.DatabaseClient) *WebhookNotifier {
+	return &WebhookNotifier{
+		dbClient:       dbClient,
+		client:         &http.Client{Timeout: webhookTimeout},
+		maxAttempts:    defaultWebhookAttempts,
+		initialBackoff: defaultWebhookBackoff,
+	}
+}
+
+func (w *WebhookNotifier) Notify(ctx context.Context, event Event) {
+	log := logging.FromContext(ctx)
+	webhooks, err := w.dbClient.ListWebhooks(ctx, event.UserID)
+	if err != nil {
+		log.Errorf("Failed to list webhooks for user %s: %v", event.UserID, err)
+		return
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Errorf("Failed to encode %s event: %v", event.Type, err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, webhook := range webhooks {
+		if !subscribed(webhook, event.Type) {
+			continue
+		}
+		wg.Add(1)
+		go func(webhook db.Webhook) {
+			defer wg.Done()
+			w.deliver(ctx, webhook, event, body)
+		}(webhook)
+	}
+	wg.Wait()
+}
+
+func subscribed(webhook db.Webhook, eventType EventType) bool {
+	return webhook.Active && (len(webhook.Events) == 0 || slices.Contains(webhook.Events, string(eventType)))
+}
+
+func (w *WebhookNotifier) deliver(ctx context.Context, webhook db.Webhook, event Event, body []byte) {
+	log := logging.FromContext(ctx)
+	backoff := w.initialBackoff
+	for attempt := 1; attempt <= w.maxAttempts; attempt++ {
+		delivery := w.post(ctx, webhook, event, body)
+		delivery.Attempt = attempt
+		if err := w.dbClient.StoreWebhookDelivery(ctx, &delivery); err != nil {
+			log.Errorf("Failed to log delivery to webhook %s: %v", webhook.ID, err)
+		}
+		if delivery.Success || !retryable(delivery.StatusCode) || attempt == w.maxAttempts {
+			if !delivery.Success {
+				log.Warnf("Giving up on delivering %s to webhook %s after %d attempts", event.Type, webhook.ID, attempt)
+			}
+			return
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (w *WebhookNotifier) post(ctx context.Context, webhook db.Webhook, event Event, body []byte) db.WebhookDelivery {
+	delivery := db.WebhookDelivery{
+		WebhookID: webhook.ID,
+		UserID:    webhook.UserID,
+		Event:     string(event.Type),
+		CreatedAt: time.Now().UTC(),
+	}
+	if event.Run != nil {
+		delivery.RequestID = event.Run.RequestID
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = fmt.Sprintf("failed to create request: %v", err)
+		return delivery
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(event.Type))
+	req.Header.Set(DeliveryHeader, event.ID)
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, body))
+
+	start := time.Now()
+	resp, err := w.client.Do(req)
+	delivery.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	resp.Body.Close()
+	delivery.StatusCode = resp.StatusCode
+	delivery.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !delivery.Success {
+		delivery.Error = fmt.Sprintf("webhook responded with status %d", resp.StatusCode)
+	}
+	return delivery
+}
+
+// Transport errors and server errors are retried; a client error other
+// than a timeout or rate limit will not succeed on a second try.
+func retryable(statusCode int) bool {
+	if statusCode == 0 || statusCode >= 500 {
+		return true
+	}
+	return statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests
+}
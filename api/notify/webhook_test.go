@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	exec "github.com/jgfranco17/aeternum/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	// Computed with: printf 'hello' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=88aab3ede8d3adf94d26ab90d3bafd4a2083070c3bcce9c014ee04a443847c0b", Sign("secret", []byte("hello")))
+}
+
+func TestWebhookDeliveryIsSignedAndRetried(t *testing.T) {
+	var calls atomic.Int32
+	var signature, eventType string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		signature = r.Header.Get(SignatureHeader)
+		eventType = r.Header.Get(EventHeader)
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &fakeDB{webhooks: []db.Webhook{
+		{ID: "hook", UserID: "user", URL: server.URL, Secret: "top-secret", Active: true},
+		{ID: "inactive", UserID: "user", URL: server.URL, Active: false},
+		{ID: "other-event", UserID: "user", URL: server.URL, Active: true, Events: []string{string(EventSuiteStatusChanged)}},
+	}}
+	notifier := NewWebhookNotifier(client)
+	notifier.initialBackoff = 0
+
+	notifier.Notify(context.Background(), Event{
+		ID:     "event",
+		Type:   EventRunCompleted,
+		UserID: "user",
+		Status: exec.StatusFail,
+		Run:    &exec.CheckResponse{RequestID: "run", Status: exec.StatusFail},
+	})
+
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, string(EventRunCompleted), eventType)
+	assert.Equal(t, Sign("top-secret", body), signature)
+	require.Len(t, client.deliveries, 3)
+	for i, delivery := range client.deliveries {
+		assert.Equal(t, i+1, delivery.Attempt)
+		assert.Equal(t, "hook", delivery.WebhookID)
+		assert.Equal(t, "run", delivery.RequestID)
+	}
+	assert.False(t, client.deliveries[0].Success)
+	assert.Equal(t, http.StatusBadGateway, client.deliveries[0].StatusCode)
+	assert.True(t, client.deliveries[2].Success)
+}
+
+func TestWebhookDeliveryStopsOnClientError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	client := &fakeDB{webhooks: []db.Webhook{{ID: "hook", URL: server.URL, Active: true}}}
+	notifier := NewWebhookNotifier(client)
+	notifier.initialBackoff = 0
+	notifier.Notify(context.Background(), Event{Type: EventRunCompleted})
+
+	assert.Equal(t, int32(1), calls.Load())
+	require.Len(t, client.deliveries, 1)
+	assert.Equal(t, "webhook responded with status 410", client.deliveries[0].Error)
+}
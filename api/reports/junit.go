@@ -0,0 +1,160 @@
+package reports
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+// junitTimestamp is the ISO 8601 form without a zone that the JUnit schema
+// expects, always in UTC
+const junitTimestamp = "2006-01-02T15:04:05"
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+func junitSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', 3, 64)
+}
+
+func junitCase(classname string, rc resultCase) junitTestCase {
+	testCase := junitTestCase{Name: rc.Name, Classname: classname, Time: junitSeconds(rc.seconds())}
+	switch rc.status() {
+	case exec.StatusPass:
+	case exec.StatusFail, exec.StatusError:
+		problem := &junitProblem{
+			Message: rc.Check.Message,
+			Type:    rc.Check.StatusCode,
+			Text:    strings.Join(rc.details(), "\n"),
+		}
+		if rc.status() == exec.StatusFail {
+			testCase.Failure = problem
+			break
+		}
+		// Errors are typed by the stage of the request that failed
+		if rc.Check.Error != nil {
+			problem.Type = string(rc.Check.Error.Category)
+		}
+		testCase.Error = problem
+	default:
+		testCase.Skipped = &junitSkipped{Message: rc.skipReason()}
+	}
+	return testCase
+}
+
+func junitSuite(name string, cases []resultCase, properties []junitProperty, timestamp string) junitTestSuite {
+	counts := tally(cases)
+	suite := junitTestSuite{
+		Name:       name,
+		Tests:      counts.Tests,
+		Failures:   counts.Failures,
+		Errors:     counts.Errors,
+		Skipped:    counts.Skipped,
+		Time:       junitSeconds(counts.Seconds),
+		Timestamp:  timestamp,
+		Properties: properties,
+		Cases:      make([]junitTestCase, 0, len(cases)),
+	}
+	for _, rc := range cases {
+		suite.Cases = append(suite.Cases, junitCase(name, rc))
+	}
+	return suite
+}
+
+func junitProperties(result db.TestResult) []junitProperty {
+	properties := []junitProperty{
+		{Name: "request_id", Value: result.RequestID},
+		{Name: "base_url", Value: result.BaseURL},
+		{Name: "status", Value: string(result.Status)},
+	}
+	if result.SuiteID != "" {
+		properties = append(properties, junitProperty{Name: "suite_id", Value: result.SuiteID})
+	}
+	if len(result.Tags) > 0 {
+		properties = append(properties, junitProperty{Name: "tags", Value: strings.Join(result.Tags, ",")})
+	}
+	return properties
+}
+
+// WriteJUnit writes a result as a JUnit XML report for CI systems. The
+// endpoints of the run form one test suite named after the base URL and each
+// scenario another, with one test case per check. Failed checks carry their
+// expected and actual status, message and failed assertions.
+func WriteJUnit(w io.Writer, result db.TestResult) error {
+	properties := junitProperties(result)
+	timestamp := result.CreatedAt.UTC().Format(junitTimestamp)
+
+	counts := tally(resultCases(result))
+	report := junitTestSuites{
+		Name:     result.RequestID,
+		Tests:    counts.Tests,
+		Failures: counts.Failures,
+		Errors:   counts.Errors,
+		Skipped:  counts.Skipped,
+		Time:     junitSeconds(counts.Seconds),
+		Suites:   []junitTestSuite{junitSuite(result.BaseURL, endpointCases(result), properties, timestamp)},
+	}
+	for _, scenario := range result.Scenarios {
+		name := result.BaseURL + " " + scenario.Name
+		report.Suites = append(report.Suites, junitSuite(name, scenarioCases(scenario), properties, timestamp))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
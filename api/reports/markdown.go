@@ -0,0 +1,84 @@
+package reports
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ")
+
+// markdownBadge marks the status of a check so it stands out in a table
+func markdownBadge(status exec.Status) string {
+	switch status {
+	case exec.StatusPass:
+		return "✅ PASS"
+	case exec.StatusFail:
+		return "❌ FAIL"
+	case exec.StatusError:
+		return "⚠️ ERROR"
+	}
+	return "⏭️ " + string(status)
+}
+
+// markdownCode wraps text in a code span, unless it contains a backtick
+func markdownCode(text string) string {
+	if text == "" || strings.Contains(text, "`") {
+		return markdownEscaper.Replace(text)
+	}
+	return "`" + markdownEscaper.Replace(text) + "`"
+}
+
+func markdownSummary(counts caseTally) string {
+	parts := []string{fmt.Sprintf("%d passed", counts.Passed)}
+	if counts.Failures > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", counts.Failures))
+	}
+	if counts.Errors > 0 {
+		parts = append(parts, fmt.Sprintf("%d errored", counts.Errors))
+	}
+	if counts.Skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", counts.Skipped))
+	}
+	return fmt.Sprintf("%d checks: %s", counts.Tests, strings.Join(parts, ", "))
+}
+
+// WriteMarkdown writes a result as a heading and a table with one row per
+// check, suitable for pasting into a pull request comment
+func WriteMarkdown(w io.Writer, result db.TestResult) error {
+	cases := resultCases(result)
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "### %s %s\n\n", markdownBadge(result.Status), markdownEscaper.Replace(result.BaseURL))
+	fmt.Fprintf(out, "Run %s at %s · %s\n\n", markdownCode(result.RequestID),
+		result.CreatedAt.UTC().Format(time.DateTime+" UTC"), markdownSummary(tally(cases)))
+	if len(cases) == 0 {
+		return out.Flush()
+	}
+
+	fmt.Fprintln(out, "| Status | Check | Expected | Actual | Time | Message |")
+	fmt.Fprintln(out, "| --- | --- | ---: | ---: | ---: | --- |")
+	for _, rc := range cases {
+		check := rc.Check
+		actual := "–"
+		if check.ActualStatus != 0 {
+			actual = strconv.Itoa(check.ActualStatus)
+		}
+		duration := ""
+		if check.Timings != nil {
+			duration = fmt.Sprintf("%.0f ms", check.Timings.TotalMs)
+		}
+		name := markdownCode(rc.Name)
+		if rc.Scenario != "" {
+			name = markdownEscaper.Replace(rc.Scenario) + ": " + name
+		}
+		fmt.Fprintf(out, "| %s | %s | %d | %s | %s | %s |\n", markdownBadge(rc.status()), name,
+			check.ExpectedStatus, actual, duration, markdownEscaper.Replace(check.Message))
+	}
+	return out.Flush()
+}
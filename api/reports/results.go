@@ -0,0 +1,180 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatJUnit    Format = "junit"
+	FormatTAP      Format = "tap"
+	FormatMarkdown Format = "markdown"
+)
+
+// Formats are the renderings a single stored result can be exported in
+var Formats = []Format{FormatJSON, FormatJUnit, FormatTAP, FormatMarkdown}
+
+// resultCase is one check of a result, either an endpoint or a scenario step
+type resultCase struct {
+	// Scenario is empty for the endpoints of the run
+	Scenario string
+	Name     string
+	Check    exec.CheckResult
+}
+
+// title names the case on its own, prefixed by its scenario
+func (rc resultCase) title() string {
+	if rc.Scenario == "" {
+		return rc.Name
+	}
+	return fmt.Sprintf("%s: %s", rc.Scenario, rc.Name)
+}
+
+func (rc resultCase) status() exec.Status {
+	return exec.Status(rc.Check.StatusCode)
+}
+
+// seconds is the total time of the check, zero when it never got a response
+func (rc resultCase) seconds() float64 {
+	if rc.Check.Timings == nil {
+		return 0
+	}
+	return rc.Check.Timings.TotalMs / 1000
+}
+
+// skipReason tells why a case did not run, or is empty when it did
+func (rc resultCase) skipReason() string {
+	switch rc.status() {
+	case exec.StatusPass, exec.StatusFail, exec.StatusError:
+		return ""
+	case exec.StatusSkipped:
+		return "an earlier step of the scenario did not pass"
+	case exec.StatusCancelled:
+		return "run was cancelled"
+	}
+	return fmt.Sprintf("check finished with status %s", rc.Check.StatusCode)
+}
+
+// details lists what went wrong in a failed or errored check, one per line
+func (rc resultCase) details() []string {
+	check := rc.Check
+	lines := []string{fmt.Sprintf("expected status: %d", check.ExpectedStatus)}
+	if check.ActualStatus != 0 {
+		lines = append(lines, fmt.Sprintf("actual status: %d", check.ActualStatus))
+	} else {
+		lines = append(lines, "actual status: no response")
+	}
+	// Failed checks join every reason into one message, which reads better
+	// as a list
+	if check.Message != "" {
+		lines = append(lines, strings.Split(check.Message, "; ")...)
+	}
+	return lines
+}
+
+// failedAssertions describes each assertion of a check that did not hold
+func failedAssertions(check exec.CheckResult) []string {
+	var failed []string
+	for _, assertion := range check.Assertions {
+		if assertion.Passed {
+			continue
+		}
+		label := string(assertion.Type)
+		if assertion.Target != "" {
+			label = fmt.Sprintf("%s %s", assertion.Type, assertion.Target)
+		}
+		failed = append(failed, fmt.Sprintf("%s: %s", label, assertion.Message))
+	}
+	return failed
+}
+
+func checkName(check exec.CheckResult) string {
+	method := strings.ToUpper(check.Method)
+	if method == "" {
+		method = "GET"
+	}
+	return fmt.Sprintf("%s %s", method, check.Path)
+}
+
+func endpointCases(result db.TestResult) []resultCase {
+	cases := make([]resultCase, 0, len(result.Results))
+	for _, check := range result.Results {
+		cases = append(cases, resultCase{Name: checkName(check), Check: check})
+	}
+	return cases
+}
+
+func scenarioCases(scenario exec.ScenarioResult) []resultCase {
+	cases := make([]resultCase, 0, len(scenario.Steps))
+	for _, step := range scenario.Steps {
+		cases = append(cases, resultCase{Scenario: scenario.Name, Name: stepName(step), Check: step.Result})
+	}
+	return cases
+}
+
+// resultCases flattens the endpoints of a result and then the steps of its
+// scenarios, in the order they were defined
+func resultCases(result db.TestResult) []resultCase {
+	cases := endpointCases(result)
+	for _, scenario := range result.Scenarios {
+		cases = append(cases, scenarioCases(scenario)...)
+	}
+	return cases
+}
+
+func stepName(step exec.StepResult) string {
+	if step.Name == "" {
+		return fmt.Sprintf("step %d (%s)", step.Index+1, checkName(step.Result))
+	}
+	return fmt.Sprintf("step %d %s (%s)", step.Index+1, step.Name, checkName(step.Result))
+}
+
+// caseTally counts the outcomes of a set of cases
+type caseTally struct {
+	Tests    int
+	Passed   int
+	Failures int
+	Errors   int
+	Skipped  int
+	Seconds  float64
+}
+
+func tally(cases []resultCase) caseTally {
+	var counts caseTally
+	for _, rc := range cases {
+		counts.Tests++
+		counts.Seconds += rc.seconds()
+		switch rc.status() {
+		case exec.StatusPass:
+			counts.Passed++
+		case exec.StatusFail:
+			counts.Failures++
+		case exec.StatusError:
+			counts.Errors++
+		default:
+			counts.Skipped++
+		}
+	}
+	return counts
+}
+
+// WriteResult renders a stored result in one of the text formats. JSON is
+// left to the caller, which encodes results like every other response.
+func WriteResult(w io.Writer, result db.TestResult, format Format) error {
+	switch format {
+	case FormatJUnit:
+		return WriteJUnit(w, result)
+	case FormatTAP:
+		return WriteTAP(w, result)
+	case FormatMarkdown:
+		return WriteMarkdown(w, result)
+	}
+	return fmt.Errorf("unsupported result format %q", format)
+}
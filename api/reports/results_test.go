@@ -0,0 +1,156 @@
+package reports
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	exec "github.com/jgfranco17/aeternum/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportedResult() db.TestResult {
+	return db.TestResult{
+		ID:        "run-1",
+		RequestID: "aeternum-v0-1",
+		BaseURL:   "https://example.com",
+		Status:    exec.StatusFail,
+		Tags:      []string{"production", "smoke"},
+		CreatedAt: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+		Results: []exec.CheckResult{
+			{Method: "GET", Path: "/health", ExpectedStatus: 200, ActualStatus: 200, StatusCode: "PASS",
+				Timings: &exec.Timings{TotalMs: 41.5}},
+			{Method: "post", Path: "/users", ExpectedStatus: 201, ActualStatus: 500, StatusCode: "FAIL",
+				Message: "expected status 201, got 500; expected $.id to exist",
+				Assertions: []exec.AssertionResult{
+					{Type: exec.AssertionJSONPath, Target: "$.id", Passed: false, Message: "expected $.id to exist"},
+					{Type: exec.AssertionHeader, Target: "Content-Type", Passed: true},
+				},
+				Timings: &exec.Timings{TotalMs: 120}},
+			{Path: "/search?q=a|b#top", ExpectedStatus: 200, StatusCode: "ERROR",
+				Message: "timeout error: context deadline exceeded",
+				Error:   &exec.CheckError{Category: exec.ErrorCategoryTimeout, Message: "context deadline exceeded"}},
+			{Method: "GET", Path: "/slow", ExpectedStatus: 200, StatusCode: "CANCELLED", Message: "run was cancelled"},
+		},
+		Scenarios: []exec.ScenarioResult{{
+			Name:   "checkout",
+			Status: exec.StatusFail,
+			Steps: []exec.StepResult{
+				{Index: 0, Name: "login", Result: exec.CheckResult{Method: "POST", Path: "/login", ExpectedStatus: 200,
+					ActualStatus: 401, StatusCode: "FAIL", Message: "expected status 200, got 401"}},
+				{Index: 1, Skipped: true, Result: exec.CheckResult{Method: "GET", Path: "/cart", ExpectedStatus: 200,
+					StatusCode: "SKIPPED"}},
+			},
+		}},
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var out strings.Builder
+	require.NoError(t, WriteJUnit(&out, exportedResult()))
+	require.True(t, strings.HasPrefix(out.String(), xml.Header))
+
+	var report junitTestSuites
+	require.NoError(t, xml.Unmarshal([]byte(out.String()), &report))
+	assert.Equal(t, "aeternum-v0-1", report.Name)
+	assert.Equal(t, []int{6, 2, 1, 2}, []int{report.Tests, report.Failures, report.Errors, report.Skipped})
+	assert.Equal(t, "0.162", report.Time)
+	require.Len(t, report.Suites, 2)
+
+	endpoints := report.Suites[0]
+	assert.Equal(t, "https://example.com", endpoints.Name)
+	assert.Equal(t, "2024-03-01T12:30:00", endpoints.Timestamp)
+	assert.Contains(t, endpoints.Properties, junitProperty{Name: "tags", Value: "production,smoke"})
+	require.Len(t, endpoints.Cases, 4)
+
+	passed := endpoints.Cases[0]
+	assert.Equal(t, junitTestCase{Name: "GET /health", Classname: "https://example.com", Time: "0.042"}, passed)
+
+	failed := endpoints.Cases[1]
+	assert.Equal(t, "POST /users", failed.Name)
+	require.NotNil(t, failed.Failure)
+	assert.Equal(t, "FAIL", failed.Failure.Type)
+	assert.Equal(t, "expected status: 201\nactual status: 500\nexpected status 201, got 500\nexpected $.id to exist",
+		failed.Failure.Text)
+
+	errored := endpoints.Cases[2]
+	assert.Equal(t, "GET /search?q=a|b#top", errored.Name)
+	require.NotNil(t, errored.Error)
+	assert.Equal(t, "timeout", errored.Error.Type)
+	assert.Contains(t, errored.Error.Text, "actual status: no response")
+
+	require.NotNil(t, endpoints.Cases[3].Skipped)
+	assert.Equal(t, "run was cancelled", endpoints.Cases[3].Skipped.Message)
+
+	scenario := report.Suites[1]
+	assert.Equal(t, "https://example.com checkout", scenario.Name)
+	assert.Equal(t, []int{2, 1, 0, 1}, []int{scenario.Tests, scenario.Failures, scenario.Errors, scenario.Skipped})
+	assert.Equal(t, "step 1 login (POST /login)", scenario.Cases[0].Name)
+	assert.Equal(t, "step 2 (GET /cart)", scenario.Cases[1].Name)
+	require.NotNil(t, scenario.Cases[1].Skipped)
+}
+
+func TestWriteTAP(t *testing.T) {
+	var out strings.Builder
+	require.NoError(t, WriteTAP(&out, exportedResult()))
+	assert.Equal(t, `TAP version 13
+1..6
+# aeternum-v0-1 https://example.com: FAIL
+ok 1 - GET /health
+not ok 2 - POST /users
+  ---
+  message: "expected status 201, got 500; expected $.id to exist"
+  severity: fail
+  expected_status: 201
+  actual_status: 500
+  duration_ms: 120
+  failed_assertions:
+    - "jsonpath $.id: expected $.id to exist"
+  ...
+not ok 3 - GET /search?q=a|b\#top
+  ---
+  message: "timeout error: context deadline exceeded"
+  severity: error
+  expected_status: 200
+  actual_status: null
+  error_category: timeout
+  ...
+ok 4 - GET /slow # SKIP run was cancelled
+not ok 5 - checkout: step 1 login (POST /login)
+  ---
+  message: "expected status 200, got 401"
+  severity: fail
+  expected_status: 200
+  actual_status: 401
+  ...
+ok 6 - checkout: step 2 (GET /cart) # SKIP an earlier step of the scenario did not pass
+`, out.String())
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var out strings.Builder
+	require.NoError(t, WriteMarkdown(&out, exportedResult()))
+	lines := strings.Split(out.String(), "\n")
+	assert.Equal(t, "### ❌ FAIL https://example.com", lines[0])
+	assert.Equal(t, "Run `aeternum-v0-1` at 2024-03-01 12:30:00 UTC · 6 checks: 1 passed, 2 failed, 1 errored, 2 skipped", lines[2])
+	assert.Equal(t, "| Status | Check | Expected | Actual | Time | Message |", lines[4])
+	assert.Equal(t, "| ✅ PASS | `GET /health` | 200 | 200 | 42 ms |  |", lines[6])
+	assert.Equal(t, "| ⚠️ ERROR | `GET /search?q=a\\|b#top` | 200 | – |  | timeout error: context deadline exceeded |", lines[8])
+	assert.Equal(t, "| ⏭️ SKIPPED | checkout: `step 2 (GET /cart)` | 200 | – |  |  |", lines[11])
+
+	var empty strings.Builder
+	require.NoError(t, WriteMarkdown(&empty, db.TestResult{RequestID: "aeternum-v0-2", BaseURL: "https://example.com",
+		Status: exec.StatusPending, CreatedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}))
+	assert.Equal(t, "### ⏭️ PENDING https://example.com\n\nRun `aeternum-v0-2` at 2024-03-01 00:00:00 UTC · 0 checks: 0 passed\n\n",
+		empty.String())
+}
+
+func TestWriteResultFormat(t *testing.T) {
+	var out strings.Builder
+	assert.Error(t, WriteResult(&out, exportedResult(), FormatJSON))
+	assert.Error(t, WriteResult(&out, exportedResult(), "html"))
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,68 @@
+package reports
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+var tapEscaper = strings.NewReplacer(`\`, `\\`, "#", `\#`, "\n", " ")
+
+// WriteTAP writes a result as a TAP version 13 stream, one test point per
+// check. Checks that did not pass carry a YAML block with their expected and
+// actual status, message and failed assertions; checks that did not run are
+// reported as skipped.
+func WriteTAP(w io.Writer, result db.TestResult) error {
+	cases := resultCases(result)
+	out := bufio.NewWriter(w)
+	fmt.Fprintln(out, "TAP version 13")
+	fmt.Fprintf(out, "1..%d\n", len(cases))
+	fmt.Fprintf(out, "# %s %s: %s\n", result.RequestID, tapEscaper.Replace(result.BaseURL), result.Status)
+
+	for i, rc := range cases {
+		description := tapEscaper.Replace(rc.title())
+		switch rc.status() {
+		case exec.StatusPass:
+			fmt.Fprintf(out, "ok %d - %s\n", i+1, description)
+		case exec.StatusFail, exec.StatusError:
+			fmt.Fprintf(out, "not ok %d - %s\n", i+1, description)
+			writeTAPDiagnostics(out, rc)
+		default:
+			fmt.Fprintf(out, "ok %d - %s # SKIP %s\n", i+1, description, tapEscaper.Replace(rc.skipReason()))
+		}
+	}
+	return out.Flush()
+}
+
+// writeTAPDiagnostics writes the YAML block of a check that did not pass.
+// Strings are double quoted, which YAML reads the same way as Go escapes them.
+func writeTAPDiagnostics(out io.Writer, rc resultCase) {
+	check := rc.Check
+	fmt.Fprintln(out, "  ---")
+	fmt.Fprintf(out, "  message: %s\n", strconv.Quote(check.Message))
+	fmt.Fprintf(out, "  severity: %s\n", strings.ToLower(check.StatusCode))
+	fmt.Fprintf(out, "  expected_status: %d\n", check.ExpectedStatus)
+	if check.ActualStatus != 0 {
+		fmt.Fprintf(out, "  actual_status: %d\n", check.ActualStatus)
+	} else {
+		fmt.Fprintln(out, "  actual_status: null")
+	}
+	if check.Error != nil {
+		fmt.Fprintf(out, "  error_category: %s\n", check.Error.Category)
+	}
+	if check.Timings != nil {
+		fmt.Fprintf(out, "  duration_ms: %s\n", strconv.FormatFloat(check.Timings.TotalMs, 'f', -1, 64))
+	}
+	if failed := failedAssertions(check); len(failed) > 0 {
+		fmt.Fprintln(out, "  failed_assertions:")
+		for _, message := range failed {
+			fmt.Fprintf(out, "    - %s\n", strconv.Quote(message))
+		}
+	}
+	fmt.Fprintln(out, "  ...")
+}
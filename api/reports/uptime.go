@@ -0,0 +1,350 @@
+package reports
+
+import (
+	"cmp"
+	"encoding/csv"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+type Bucket string
+
+const (
+	BucketDay   Bucket = "day"
+	BucketWeek  Bucket = "week"
+	BucketMonth Bucket = "month"
+)
+
+// Buckets are the period lengths uptime can be reported over
+var Buckets = []Bucket{BucketDay, BucketWeek, BucketMonth}
+
+// UptimeWindow is the time range of a report, split into calendar buckets
+// in UTC. Weeks start on Monday.
+type UptimeWindow struct {
+	From   time.Time
+	To     time.Time
+	Bucket Bucket
+}
+
+// Period is the availability within one bucket, clipped to the window
+type Period struct {
+	Start               time.Time `json:"start"`
+	End                 time.Time `json:"end"`
+	Runs                int       `json:"runs"`
+	Passed              int       `json:"passed"`
+	AvailabilityPercent *float64  `json:"availability_percent"`
+}
+
+// Incident is a contiguous failing period, from the first failing run to
+// the next passing one. End is nil while it is ongoing, in which case its
+// duration runs to the end of the window.
+type Incident struct {
+	Start           time.Time  `json:"start"`
+	End             *time.Time `json:"end"`
+	DurationSeconds float64    `json:"duration_seconds"`
+	FailedRuns      int        `json:"failed_runs"`
+}
+
+// Availability is the share of passing runs of a base URL or endpoint.
+// AvailabilityPercent is nil when nothing ran.
+type Availability struct {
+	Runs                int        `json:"runs"`
+	Passed              int        `json:"passed"`
+	AvailabilityPercent *float64   `json:"availability_percent"`
+	DowntimeSeconds     float64    `json:"downtime_seconds"`
+	Periods             []Period   `json:"periods"`
+	Incidents           []Incident `json:"incidents"`
+}
+
+// EndpointUptime is the availability of one endpoint of a base URL
+type EndpointUptime struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Availability
+}
+
+// BaseURLUptime is the availability of a base URL and its endpoints
+type BaseURLUptime struct {
+	BaseURL string `json:"base_url"`
+	Availability
+	Endpoints []EndpointUptime `json:"endpoints"`
+}
+
+// UptimeReport is the availability of every base URL checked in a window
+type UptimeReport struct {
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Bucket   Bucket          `json:"bucket"`
+	BaseURLs []BaseURLUptime `json:"base_urls"`
+}
+
+// bucketStart is the start of the bucket containing t
+func bucketStart(t time.Time, bucket Bucket) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch bucket {
+	case BucketWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case BucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+func nextBucket(start time.Time, bucket Bucket) time.Time {
+	switch bucket {
+	case BucketWeek:
+		return start.AddDate(0, 0, 7)
+	case BucketMonth:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// Periods counts the buckets the window is split into
+func (w UptimeWindow) Periods() int {
+	count := 0
+	for start := bucketStart(w.From, w.Bucket); start.Before(w.To); start = nextBucket(start, w.Bucket) {
+		count++
+	}
+	return count
+}
+
+// periods splits the window into its buckets
+func (w UptimeWindow) periods() []Period {
+	periods := []Period{}
+	for start := bucketStart(w.From, w.Bucket); start.Before(w.To); start = nextBucket(start, w.Bucket) {
+		period := Period{Start: start, End: nextBucket(start, w.Bucket)}
+		if period.Start.Before(w.From) {
+			period.Start = w.From
+		}
+		if period.End.After(w.To) {
+			period.End = w.To
+		}
+		periods = append(periods, period)
+	}
+	return periods
+}
+
+func percent(passed, runs int) *float64 {
+	if runs == 0 {
+		return nil
+	}
+	value := math.Round(float64(passed)/float64(runs)*100000) / 1000
+	return &value
+}
+
+// series follows the availability of one base URL or endpoint run by run
+type series struct {
+	availability Availability
+	ongoing      *Incident
+}
+
+func newSeries(window UptimeWindow) *series {
+	return &series{availability: Availability{Periods: window.periods(), Incidents: []Incident{}}}
+}
+
+func (s *series) observe(at time.Time, up bool) {
+	s.availability.Runs++
+	index := sort.Search(len(s.availability.Periods), func(i int) bool {
+		return s.availability.Periods[i].End.After(at)
+	})
+	if index < len(s.availability.Periods) {
+		s.availability.Periods[index].Runs++
+	}
+	if up {
+		s.availability.Passed++
+		if index < len(s.availability.Periods) {
+			s.availability.Periods[index].Passed++
+		}
+		if s.ongoing != nil {
+			end := at
+			s.ongoing.End = &end
+			s.close(at)
+		}
+		return
+	}
+	if s.ongoing == nil {
+		s.ongoing = &Incident{Start: at}
+	}
+	s.ongoing.FailedRuns++
+}
+
+func (s *series) close(until time.Time) {
+	s.ongoing.DurationSeconds = math.Round(until.Sub(s.ongoing.Start).Seconds()*1000) / 1000
+	s.availability.DowntimeSeconds += s.ongoing.DurationSeconds
+	s.availability.Incidents = append(s.availability.Incidents, *s.ongoing)
+	s.ongoing = nil
+}
+
+// finish closes an incident still ongoing at the end of the window
+func (s *series) finish(until time.Time) Availability {
+	if s.ongoing != nil {
+		s.close(until)
+	}
+	s.availability.AvailabilityPercent = percent(s.availability.Passed, s.availability.Runs)
+	for i := range s.availability.Periods {
+		period := &s.availability.Periods[i]
+		period.AvailabilityPercent = percent(period.Passed, period.Runs)
+	}
+	return s.availability
+}
+
+type endpointKey struct {
+	method string
+	path   string
+}
+
+func statusUp(status exec.Status) (up bool, counted bool) {
+	switch status {
+	case exec.StatusPass:
+		return true, true
+	case exec.StatusFail, exec.StatusError:
+		return false, true
+	}
+	return false, false
+}
+
+// NewUptimeReport computes the availability of the base URLs and endpoints
+// of the results within the window. A run is up when it passed and down when
+// it failed or errored; runs with any other status are left out. An
+// endpoint is down in a run if any of its checks failed. Ongoing incidents
+// last until the end of the window or now, whichever is earlier.
+func NewUptimeReport(results []db.TestResult, window UptimeWindow, now time.Time) UptimeReport {
+	results = slices.Clone(results)
+	slices.SortFunc(results, func(a, b db.TestResult) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID, b.ID))
+	})
+
+	baseURLs := map[string]*series{}
+	endpoints := map[string]map[endpointKey]*series{}
+	for _, result := range results {
+		if result.CreatedAt.Before(window.From) || !result.CreatedAt.Before(window.To) {
+			continue
+		}
+		up, counted := statusUp(result.Status)
+		if !counted {
+			continue
+		}
+		if baseURLs[result.BaseURL] == nil {
+			baseURLs[result.BaseURL] = newSeries(window)
+			endpoints[result.BaseURL] = map[endpointKey]*series{}
+		}
+		baseURLs[result.BaseURL].observe(result.CreatedAt, up)
+
+		checked := map[endpointKey]bool{}
+		order := []endpointKey{}
+		for _, check := range result.Results {
+			checkUp, counted := statusUp(exec.Status(check.StatusCode))
+			if !counted {
+				continue
+			}
+			key := endpointKey{method: strings.ToUpper(check.Method), path: check.Path}
+			if key.method == "" {
+				key.method = "GET"
+			}
+			previous, seen := checked[key]
+			if !seen {
+				order = append(order, key)
+				previous = true
+			}
+			checked[key] = previous && checkUp
+		}
+		for _, key := range order {
+			if endpoints[result.BaseURL][key] == nil {
+				endpoints[result.BaseURL][key] = newSeries(window)
+			}
+			endpoints[result.BaseURL][key].observe(result.CreatedAt, checked[key])
+		}
+	}
+
+	until := window.To
+	if now.Before(until) {
+		until = now
+	}
+	report := UptimeReport{From: window.From, To: window.To, Bucket: window.Bucket, BaseURLs: []BaseURLUptime{}}
+	for baseURL, baseSeries := range baseURLs {
+		uptime := BaseURLUptime{BaseURL: baseURL, Availability: baseSeries.finish(until), Endpoints: []EndpointUptime{}}
+		for key, endpointSeries := range endpoints[baseURL] {
+			uptime.Endpoints = append(uptime.Endpoints, EndpointUptime{
+				Method:       key.method,
+				Path:         key.path,
+				Availability: endpointSeries.finish(until),
+			})
+		}
+		slices.SortFunc(uptime.Endpoints, func(a, b EndpointUptime) int {
+			return cmp.Or(strings.Compare(a.Path, b.Path), strings.Compare(a.Method, b.Method))
+		})
+		report.BaseURLs = append(report.BaseURLs, uptime)
+	}
+	slices.SortFunc(report.BaseURLs, func(a, b BaseURLUptime) int {
+		return strings.Compare(a.BaseURL, b.BaseURL)
+	})
+	return report
+}
+
+// uptimeColumns are the columns of the CSV export. Each row is the total of
+// the window, one period or one incident of a base URL, or of one of its
+// endpoints when method and path are set.
+var uptimeColumns = []string{
+	"record", "base_url", "method", "path", "start", "end",
+	"runs", "passed", "availability_percent", "duration_seconds",
+}
+
+// WriteCSV writes the report as one CSV table, for spreadsheets. Write
+// errors are kept by the writer and reported once it is flushed.
+func (r UptimeReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(uptimeColumns); err != nil {
+		return err
+	}
+	for _, baseURL := range r.BaseURLs {
+		r.writeAvailability(writer, baseURL.BaseURL, "", "", baseURL.Availability)
+		for _, endpoint := range baseURL.Endpoints {
+			r.writeAvailability(writer, baseURL.BaseURL, endpoint.Method, endpoint.Path, endpoint.Availability)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (r UptimeReport) writeAvailability(writer *csv.Writer, baseURL, method, path string, availability Availability) {
+	writer.Write([]string{"total", baseURL, method, path, formatTime(r.From), formatTime(r.To),
+		strconv.Itoa(availability.Runs), strconv.Itoa(availability.Passed),
+		formatPercent(availability.AvailabilityPercent), formatSeconds(availability.DowntimeSeconds)})
+	for _, period := range availability.Periods {
+		writer.Write([]string{"period", baseURL, method, path, formatTime(period.Start), formatTime(period.End),
+			strconv.Itoa(period.Runs), strconv.Itoa(period.Passed), formatPercent(period.AvailabilityPercent), ""})
+	}
+	for _, incident := range availability.Incidents {
+		end := ""
+		if incident.End != nil {
+			end = formatTime(*incident.End)
+		}
+		writer.Write([]string{"incident", baseURL, method, path, formatTime(incident.Start), end,
+			strconv.Itoa(incident.FailedRuns), "0", "", formatSeconds(incident.DurationSeconds)})
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatPercent(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', 3, 64)
+}
+
+func formatSeconds(value float64) string {
+	return strconv.FormatFloat(value, 'f', 3, 64)
+}
@@ -0,0 +1,116 @@
+package reports
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	exec "github.com/jgfranco17/aeternum/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func at(day, hour int) time.Time {
+	return time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC)
+}
+
+func run(id, baseURL string, status exec.Status, created time.Time, checks ...exec.Status) db.TestResult {
+	result := db.TestResult{ID: id, BaseURL: baseURL, Status: status, CreatedAt: created, Results: []exec.CheckResult{}}
+	for _, check := range checks {
+		result.Results = append(result.Results, exec.CheckResult{Method: "GET", Path: "/health", StatusCode: string(check)})
+	}
+	return result
+}
+
+func percentOf(value float64) *float64 {
+	return &value
+}
+
+func TestUptimeReport(t *testing.T) {
+	results := []db.TestResult{
+		run("r5", "https://a.example.com", exec.StatusFail, at(5, 20), exec.StatusFail),
+		run("r1", "https://a.example.com", exec.StatusPass, at(4, 10), exec.StatusPass),
+		run("r2", "https://a.example.com", exec.StatusFail, at(4, 12), exec.StatusPass, exec.StatusFail),
+		run("r3", "https://a.example.com", exec.StatusError, at(4, 13), exec.StatusError),
+		run("r4", "https://a.example.com", exec.StatusPass, at(5, 9), exec.StatusPass),
+		run("r6", "https://a.example.com", exec.StatusRunning, at(5, 10)),
+		run("r7", "https://a.example.com", exec.StatusFail, at(3, 12), exec.StatusFail),
+		run("r8", "https://b.example.com", exec.StatusPass, at(5, 8), exec.StatusCancelled),
+	}
+	window := UptimeWindow{From: at(4, 0), To: at(6, 0), Bucket: BucketDay}
+
+	report := NewUptimeReport(results, window, at(5, 22))
+	require.Len(t, report.BaseURLs, 2)
+
+	resolved := at(5, 9)
+	expected := Availability{
+		Runs:                5,
+		Passed:              2,
+		AvailabilityPercent: percentOf(40),
+		DowntimeSeconds:     82800,
+		Periods: []Period{
+			{Start: at(4, 0), End: at(5, 0), Runs: 3, Passed: 1, AvailabilityPercent: percentOf(33.333)},
+			{Start: at(5, 0), End: at(6, 0), Runs: 2, Passed: 1, AvailabilityPercent: percentOf(50)},
+		},
+		Incidents: []Incident{
+			{Start: at(4, 12), End: &resolved, DurationSeconds: 75600, FailedRuns: 2},
+			{Start: at(5, 20), DurationSeconds: 7200, FailedRuns: 1},
+		},
+	}
+	a := report.BaseURLs[0]
+	assert.Equal(t, "https://a.example.com", a.BaseURL)
+	assert.Equal(t, expected, a.Availability)
+	require.Len(t, a.Endpoints, 1)
+	assert.Equal(t, "GET", a.Endpoints[0].Method)
+	assert.Equal(t, "/health", a.Endpoints[0].Path)
+	assert.Equal(t, expected, a.Endpoints[0].Availability)
+
+	b := report.BaseURLs[1]
+	assert.Equal(t, 1, b.Runs)
+	assert.Equal(t, percentOf(100), b.AvailabilityPercent)
+	assert.Nil(t, b.Periods[0].AvailabilityPercent)
+	assert.Empty(t, b.Endpoints)
+}
+
+func TestUptimeOngoingIncidentEndsWithWindow(t *testing.T) {
+	results := []db.TestResult{run("r1", "https://a.example.com", exec.StatusFail, at(5, 20))}
+	report := NewUptimeReport(results, UptimeWindow{From: at(5, 0), To: at(6, 0), Bucket: BucketDay}, at(9, 0))
+	assert.Equal(t, float64(4*3600), report.BaseURLs[0].Incidents[0].DurationSeconds)
+}
+
+func TestUptimeWindowPeriods(t *testing.T) {
+	weeks := UptimeWindow{From: at(1, 6), To: at(12, 0), Bucket: BucketWeek}.periods()
+	assert.Equal(t, []Period{
+		{Start: at(1, 6), End: at(4, 0)},
+		{Start: at(4, 0), End: at(11, 0)},
+		{Start: at(11, 0), End: at(12, 0)},
+	}, weeks)
+
+	months := UptimeWindow{From: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), To: at(2, 0), Bucket: BucketMonth}
+	assert.Equal(t, 3, months.Periods())
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), months.periods()[1].Start)
+
+	assert.Equal(t, 0, UptimeWindow{From: at(2, 0), To: at(2, 0), Bucket: BucketDay}.Periods())
+}
+
+func TestUptimeReportCSV(t *testing.T) {
+	results := []db.TestResult{
+		run("r1", "https://a.example.com", exec.StatusFail, at(4, 12), exec.StatusFail),
+		run("r2", "https://a.example.com", exec.StatusPass, at(4, 13), exec.StatusPass),
+	}
+	report := NewUptimeReport(results, UptimeWindow{From: at(4, 0), To: at(5, 0), Bucket: BucketDay}, at(5, 0))
+
+	var out strings.Builder
+	require.NoError(t, report.WriteCSV(&out))
+	assert.Equal(t, strings.Join([]string{
+		"record,base_url,method,path,start,end,runs,passed,availability_percent,duration_seconds",
+		"total,https://a.example.com,,,2024-03-04T00:00:00Z,2024-03-05T00:00:00Z,2,1,50.000,3600.000",
+		"period,https://a.example.com,,,2024-03-04T00:00:00Z,2024-03-05T00:00:00Z,2,1,50.000,",
+		"incident,https://a.example.com,,,2024-03-04T12:00:00Z,2024-03-04T13:00:00Z,1,0,,3600.000",
+		"total,https://a.example.com,GET,/health,2024-03-04T00:00:00Z,2024-03-05T00:00:00Z,2,1,50.000,3600.000",
+		"period,https://a.example.com,GET,/health,2024-03-04T00:00:00Z,2024-03-05T00:00:00Z,2,1,50.000,",
+		"incident,https://a.example.com,GET,/health,2024-03-04T12:00:00Z,2024-03-04T13:00:00Z,1,0,,3600.000",
+		"",
+	}, "\n"), out.String())
+}
@@ -0,0 +1,121 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/environment"
+	"github.com/jgfranco17/aeternum/api/logging"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const defaultSweepMinutes = 60
+
+var PurgedResults = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "aeternum_retention_purged_results_total",
+		Help: "Number of stored test results removed by the retention policy",
+	},
+	[]string{"policy"},
+)
+
+// Policy decides how long stored test results are kept. Results older than
+// MaxAge are removed, and only the newest SuiteRuns results of each suite
+// are kept; either rule is disabled when zero.
+type Policy struct {
+	MaxAge    time.Duration
+	SuiteRuns int
+}
+
+// PolicyFromEnvironment reads the retention policy, which keeps every
+// result unless configured
+func PolicyFromEnvironment() Policy {
+	return Policy{
+		MaxAge:    time.Duration(environment.GetIntEnvWithDefault(environment.ENV_KEY_RETENTION_DAYS, 0)) * 24 * time.Hour,
+		SuiteRuns: environment.GetIntEnvWithDefault(environment.ENV_KEY_RETENTION_SUITE_RUNS, 0),
+	}
+}
+
+// Enabled reports whether the policy removes anything
+func (p Policy) Enabled() bool {
+	return p.MaxAge > 0 || p.SuiteRuns > 0
+}
+
+// Sweeper enforces a retention policy in the background. Sweeps only
+// delete what has expired, so replicas can sweep the same database
+// without coordinating.
+type Sweeper struct {
+	dbClient db.DatabaseClient
+	policy   Policy
+	interval time.Duration
+	now      func() time.Time
+}
+
+func New(dbClient db.DatabaseClient) *Sweeper {
+	sweepMinutes := environment.GetIntEnvWithDefault(environment.ENV_KEY_RETENTION_SWEEP_MINUTES, defaultSweepMinutes)
+	if sweepMinutes <= 0 {
+		sweepMinutes = defaultSweepMinutes
+	}
+	return &Sweeper{
+		dbClient: dbClient,
+		policy:   PolicyFromEnvironment(),
+		interval: time.Duration(sweepMinutes) * time.Minute,
+		now:      time.Now,
+	}
+}
+
+// Start sweeps in the background until the context is done. Nothing is
+// started if the policy keeps every result.
+func (s *Sweeper) Start(ctx context.Context) {
+	log := logging.FromContext(ctx)
+	if !s.policy.Enabled() {
+		log.Info("No retention policy configured, keeping all test results")
+		return
+	}
+	log.Infof("Starting retention sweeper, sweeping every %s", s.interval)
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			s.Sweep(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Sweep removes the results expired under the policy and returns how many
+// were removed. A failing rule is logged and does not stop the other.
+func (s *Sweeper) Sweep(ctx context.Context) int64 {
+	log := logging.FromContext(ctx)
+	var purged int64
+	if s.policy.MaxAge > 0 {
+		cutoff := s.now().Add(-s.policy.MaxAge)
+		deleted, err := s.dbClient.DeleteTestResultsBefore(ctx, cutoff)
+		if err != nil {
+			log.Errorf("Failed to purge test results older than %s: %v", cutoff.Format(time.RFC3339), err)
+		}
+		if deleted > 0 {
+			log.Infof("Purged %d test results older than %s", deleted, cutoff.Format(time.RFC3339))
+		}
+		PurgedResults.WithLabelValues("max_age").Add(float64(deleted))
+		purged += deleted
+	}
+	if s.policy.SuiteRuns > 0 {
+		deleted, err := s.dbClient.TrimSuiteTestResults(ctx, s.policy.SuiteRuns)
+		if err != nil {
+			log.Errorf("Failed to trim suite test results: %v", err)
+		}
+		if deleted > 0 {
+			log.Infof("Purged %d test results beyond the newest %d of each suite", deleted, s.policy.SuiteRuns)
+		}
+		PurgedResults.WithLabelValues("suite_runs").Add(float64(deleted))
+		purged += deleted
+	}
+	return purged
+}
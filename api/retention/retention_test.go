@@ -0,0 +1,74 @@
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	exec "github.com/jgfranco17/aeternum/execution"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func store(t *testing.T, client db.DatabaseClient, requestID, suiteID string) {
+	require.NoError(t, client.StoreTestResult(context.Background(), "user", &exec.CheckResponse{
+		RequestID: requestID,
+		SuiteID:   suiteID,
+		BaseURL:   "https://example.com",
+		Status:    exec.StatusPass,
+		Results:   []exec.CheckResult{},
+	}))
+	time.Sleep(2 * time.Millisecond)
+}
+
+func remaining(t *testing.T, client db.DatabaseClient) []string {
+	page, err := client.GetUserTestResults(context.Background(), "user", db.ResultQuery{Ascending: true})
+	require.NoError(t, err)
+	ids := []string{}
+	for _, result := range page.Results {
+		ids = append(ids, result.RequestID)
+	}
+	return ids
+}
+
+func TestPolicyFromEnvironment(t *testing.T) {
+	assert.False(t, PolicyFromEnvironment().Enabled())
+
+	t.Setenv("AETERNUM_RETENTION_DAYS", "30")
+	t.Setenv("AETERNUM_RETENTION_SUITE_RUNS", "100")
+	policy := PolicyFromEnvironment()
+	assert.Equal(t, 30*24*time.Hour, policy.MaxAge)
+	assert.Equal(t, 100, policy.SuiteRuns)
+	assert.True(t, policy.Enabled())
+}
+
+func TestSweepMaxAge(t *testing.T) {
+	client := db.NewMemoryClient()
+	store(t, client, "old", "")
+	store(t, client, "older-suite-run", "suite")
+	cutoff := time.Now()
+	time.Sleep(2 * time.Millisecond)
+	store(t, client, "new", "")
+
+	sweeper := &Sweeper{dbClient: client, policy: Policy{MaxAge: time.Hour}, now: func() time.Time { return cutoff.Add(time.Hour) }}
+	before := testutil.ToFloat64(PurgedResults.WithLabelValues("max_age"))
+	assert.Equal(t, int64(2), sweeper.Sweep(context.Background()))
+	assert.Equal(t, []string{"new"}, remaining(t, client))
+	assert.Equal(t, before+2, testutil.ToFloat64(PurgedResults.WithLabelValues("max_age")))
+
+	assert.Equal(t, int64(0), sweeper.Sweep(context.Background()), "sweeping again removes nothing")
+}
+
+func TestSweepSuiteRuns(t *testing.T) {
+	client := db.NewMemoryClient()
+	for _, id := range []string{"a-1", "b-1", "a-2", "a-3", "b-2", "c-1"} {
+		store(t, client, id, id[:1])
+	}
+	store(t, client, "manual", "")
+
+	sweeper := &Sweeper{dbClient: client, policy: Policy{SuiteRuns: 2}, now: time.Now}
+	assert.Equal(t, int64(1), sweeper.Sweep(context.Background()))
+	assert.Equal(t, []string{"b-1", "a-2", "a-3", "b-2", "c-1", "manual"}, remaining(t, client))
+}
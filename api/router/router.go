@@ -7,6 +7,7 @@ import (
 	"github.com/jgfranco17/aeternum/api/db"
 	env "github.com/jgfranco17/aeternum/api/environment"
 	"github.com/jgfranco17/aeternum/api/logging"
+	"github.com/jgfranco17/aeternum/api/notify"
 	"github.com/jgfranco17/aeternum/api/router/headers"
 	system "github.com/jgfranco17/aeternum/api/router/system"
 	v0 "github.com/jgfranco17/aeternum/api/router/v0"
@@ -65,14 +66,14 @@ func logRequest() gin.HandlerFunc {
 }
 
 // Configure the router adding routes and middlewares
-func getRouter(dbClient db.DatabaseClient, withSystemInfo bool) (*gin.Engine, error) {
+func getRouter(dbClient db.DatabaseClient, notifier *notify.Dispatcher, withSystemInfo bool) (*gin.Engine, error) {
 	router := gin.Default()
 	router.Use(addLoggerFields())
 	router.Use(logRequest())
 	router.Use(GetCors())
 	router.Use(system.PrometheusMiddleware())
 	system.SetSystemRoutes(router, withSystemInfo)
-	err := v0.SetRoutes(router, dbClient)
+	err := v0.SetRoutes(router, dbClient, notifier)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to set v0 routes: %w", err)
 	}
@@ -85,10 +86,12 @@ Create a backend service instance.
 
 [IN] port: server port to listen on
 
+[IN] notifier: dispatcher of run notifications, shared with the scheduler
+
 [OUT] *Service: new backend service instance
 */
-func CreateNewService(port int, dbClient db.DatabaseClient) (*Service, error) {
-	router, err := getRouter(dbClient, true)
+func CreateNewService(port int, dbClient db.DatabaseClient, notifier *notify.Dispatcher) (*Service, error) {
+	router, err := getRouter(dbClient, notifier, true)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create new service instance: %w", err)
 	}
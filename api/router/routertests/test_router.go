@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/notify"
 	"github.com/jgfranco17/aeternum/api/router"
 	"github.com/jgfranco17/aeternum/api/router/system"
 	v0 "github.com/jgfranco17/aeternum/api/router/v0"
@@ -65,7 +66,7 @@ func (s *TestServer) WithSystemRoutes() *TestServer {
 }
 
 func (s *TestServer) WithV0Routes(dbClient db.DatabaseClient) *TestServer {
-	v0.SetRoutes(s.service.Router, dbClient)
+	v0.SetRoutes(s.service.Router, dbClient, notify.NewDispatcher(dbClient))
 	return s
 }
 
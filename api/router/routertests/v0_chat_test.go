@@ -0,0 +1,88 @@
+package routertests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jgfranco17/aeternum/api/auth"
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateChatDestination(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	client := newMockDBClient()
+	client.On("GetSuite", mock.Anything, "test-user-123", "missing").Return(nil, db.ErrSuiteNotFound)
+	client.On("CreateChatDestination", mock.Anything, "test-user-123", mock.MatchedBy(func(destination *db.ChatDestination) bool {
+		return destination.Platform == "slack" && destination.SuiteID == ""
+	})).Return(&db.ChatDestination{ID: "chat-1", Name: "ops", Platform: "slack"}, nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:         "POST",
+			Endpoint:       "/v0/chat-destinations",
+			ExpectedCode:   http.StatusCreated,
+			Payload:        `{"name": "ops", "platform": "slack", "webhook_url": "https://hooks.slack.com/services/T/B/X"}`,
+			ExpectedFields: map[string]interface{}{"id": "chat-1", "platform": "slack"},
+		},
+		{
+			Method:       "POST",
+			Endpoint:     "/v0/chat-destinations",
+			ExpectedCode: http.StatusBadRequest,
+			Payload:      `{"name": "ops", "platform": "irc", "webhook_url": "https://irc.example.com"}`,
+		},
+		{
+			Method:       "POST",
+			Endpoint:     "/v0/chat-destinations",
+			ExpectedCode: http.StatusNotFound,
+			Payload:      `{"name": "ops", "platform": "teams", "webhook_url": "https://teams.example.com", "suite_id": "missing"}`,
+		},
+	}, token)
+	client.AssertExpectations(t)
+}
+
+func TestSendChatTestMessage(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	chat := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/revoked" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer chat.Close()
+
+	client := new(MockDBClient)
+	client.On("GetChatDestination", mock.Anything, "test-user-123", "chat-1").
+		Return(&db.ChatDestination{ID: "chat-1", Platform: "teams", WebhookURL: chat.URL}, nil)
+	client.On("GetChatDestination", mock.Anything, "test-user-123", "chat-2").
+		Return(&db.ChatDestination{ID: "chat-2", Platform: "slack", WebhookURL: chat.URL + "/revoked"}, nil)
+	client.On("GetChatDestination", mock.Anything, "test-user-123", "missing").Return(nil, db.ErrChatDestinationNotFound)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:         "POST",
+			Endpoint:       "/v0/chat-destinations/chat-1/test",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"delivered": true, "status_code": float64(200)},
+		},
+		{
+			Method:         "POST",
+			Endpoint:       "/v0/chat-destinations/chat-2/test",
+			ExpectedCode:   http.StatusBadGateway,
+			ExpectedFields: map[string]interface{}{"delivered": false, "status_code": float64(404)},
+		},
+		NewBasicExampleRequest("POST", "/v0/chat-destinations/missing/test", http.StatusNotFound),
+	}, token)
+	client.AssertExpectations(t)
+}
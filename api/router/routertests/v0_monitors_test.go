@@ -0,0 +1,88 @@
+package routertests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/auth"
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateMonitor(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	client := newMockDBClient()
+	client.On("GetSuite", mock.Anything, "test-user-123", "suite-1").Return(&db.Suite{ID: "suite-1"}, nil)
+	client.On("CreateMonitor", mock.Anything, "test-user-123", mock.MatchedBy(func(monitor *db.Monitor) bool {
+		return monitor.Schedule == "*/5 * * * *" && monitor.NextRunAt.After(time.Now())
+	})).Return(&db.Monitor{ID: "monitor-1", SuiteID: "suite-1", Name: "every five"}, nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:         "POST",
+			Endpoint:       "/v0/monitors",
+			ExpectedCode:   http.StatusCreated,
+			Payload:        `{"name": "every five", "suite_id": "suite-1", "schedule": "*/5 * * * *"}`,
+			ExpectedFields: map[string]interface{}{"id": "monitor-1", "suite_id": "suite-1"},
+		},
+		{
+			Method:       "POST",
+			Endpoint:     "/v0/monitors",
+			ExpectedCode: http.StatusBadRequest,
+			Payload:      `{"name": "broken", "suite_id": "suite-1", "schedule": "every minute"}`,
+		},
+		{
+			Method:       "POST",
+			Endpoint:     "/v0/monitors",
+			ExpectedCode: http.StatusBadRequest,
+			Payload:      `{"name": "no schedule", "suite_id": "suite-1"}`,
+		},
+	}, token)
+	client.AssertExpectations(t)
+}
+
+func TestPauseAndResumeMonitor(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	stale := time.Now().Add(-time.Hour)
+	client := newMockDBClient()
+	client.On("GetMonitor", mock.Anything, "test-user-123", "monitor-1").
+		Return(&db.Monitor{ID: "monitor-1", IntervalSeconds: 60, NextRunAt: stale}, nil).Once()
+	client.On("UpdateMonitor", mock.Anything, "test-user-123", mock.MatchedBy(func(monitor *db.Monitor) bool {
+		return monitor.Paused
+	})).Return(&db.Monitor{ID: "monitor-1", Paused: true}, nil).Once()
+	client.On("GetMonitor", mock.Anything, "test-user-123", "monitor-1").
+		Return(&db.Monitor{ID: "monitor-1", IntervalSeconds: 60, NextRunAt: stale, Paused: true}, nil).Once()
+	client.On("UpdateMonitor", mock.Anything, "test-user-123", mock.MatchedBy(func(monitor *db.Monitor) bool {
+		return !monitor.Paused && monitor.NextRunAt.After(time.Now())
+	})).Return(&db.Monitor{ID: "monitor-1"}, nil).Once()
+	client.On("GetMonitor", mock.Anything, "test-user-123", "missing").Return(nil, db.ErrMonitorNotFound)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:         "POST",
+			Endpoint:       "/v0/monitors/monitor-1/pause",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"paused": true},
+		},
+		{
+			Method:         "POST",
+			Endpoint:       "/v0/monitors/monitor-1/resume",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"paused": false},
+		},
+		NewBasicExampleRequest("POST", "/v0/monitors/missing/pause", http.StatusNotFound),
+	}, token)
+	client.AssertExpectations(t)
+	assert.Len(t, client.Calls, 5)
+}
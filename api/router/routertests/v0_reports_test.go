@@ -0,0 +1,142 @@
+package routertests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/auth"
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUptimeReport(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+	results := []db.TestResult{
+		{ID: "run-1", BaseURL: "https://example.com", Status: execution.StatusPass, CreatedAt: from.Add(time.Hour)},
+		{ID: "run-2", BaseURL: "https://example.com", Status: execution.StatusFail, CreatedAt: from.Add(25 * time.Hour)},
+	}
+
+	client := newMockDBClient()
+	// The report reads every page of results, not just the first
+	query := db.ResultQuery{
+		Limit:       500,
+		Statuses:    []execution.Status{execution.StatusPass, execution.StatusFail, execution.StatusError},
+		BaseURL:     "https://example.com",
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+		Ascending:   true,
+	}
+	client.On("GetUserTestResults", mock.Anything, "test-user-123", query).
+		Return(&db.ResultPage{Results: results[:1], NextCursor: "page-2"}, nil)
+	query.Cursor = "page-2"
+	client.On("GetUserTestResults", mock.Anything, "test-user-123", query).
+		Return(&db.ResultPage{Results: results[1:]}, nil)
+	client.On("GetUserTestResults", mock.Anything, "test-user-123", mock.MatchedBy(func(query db.ResultQuery) bool {
+		return query.BaseURL == "" && query.CreatedTo.Sub(*query.CreatedFrom) == 30*24*time.Hour
+	})).Return(&db.ResultPage{Results: []db.TestResult{}}, nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/reports/uptime?base_url=https://example.com&from=2024-03-01&to=2024-03-03",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"bucket": "day"},
+		},
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/reports/uptime?bucket=month",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"bucket": "month", "base_urls": []interface{}{}},
+		},
+		NewBasicExampleRequest("GET", "/v0/reports/uptime?bucket=hour", http.StatusBadRequest),
+		NewBasicExampleRequest("GET", "/v0/reports/uptime?format=xlsx", http.StatusBadRequest),
+		NewBasicExampleRequest("GET", "/v0/reports/uptime?from=2020-01-01&to=2024-01-01", http.StatusBadRequest),
+	}, token)
+
+	request := httptest.NewRequest("GET", "/v0/reports/uptime?base_url=https://example.com&from=2024-03-01&to=2024-03-03&format=csv", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	testService.service.Router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="uptime-2024-03-01-2024-03-03.csv"`, recorder.Header().Get("Content-Disposition"))
+	lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	assert.Equal(t, "record,base_url,method,path,start,end,runs,passed,availability_percent,duration_seconds", lines[0])
+	assert.Equal(t, "total,https://example.com,,,2024-03-01T00:00:00Z,2024-03-03T00:00:00Z,2,1,50.000,82800.000", lines[1])
+	client.AssertExpectations(t)
+}
+
+func TestExportTestResult(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	result := &db.TestResult{
+		ID:        "run-1",
+		RequestID: "run-1",
+		BaseURL:   "https://example.com",
+		Status:    execution.StatusFail,
+		CreatedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Results: []execution.CheckResult{
+			{Method: "GET", Path: "/health", ExpectedStatus: 200, ActualStatus: 200, StatusCode: "PASS"},
+			{Method: "GET", Path: "/users", ExpectedStatus: 200, ActualStatus: 500, StatusCode: "FAIL",
+				Message: "expected status 200, got 500"},
+		},
+	}
+	client := newMockDBClient()
+	client.On("GetTestResult", mock.Anything, "test-user-123", "run-1").Return(result, nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/tests/results?id=run-1",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"request_id": "run-1", "status": "FAIL"},
+		},
+		NewBasicExampleRequest("GET", "/v0/tests/results?id=run-1&format=html", http.StatusBadRequest),
+	}, token)
+
+	export := func(query, accept string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest("GET", "/v0/tests/results?id=run-1"+query, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		if accept != "" {
+			request.Header.Set("Accept", accept)
+		}
+		recorder := httptest.NewRecorder()
+		testService.service.Router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		return recorder
+	}
+
+	junit := export("&format=junit", "")
+	assert.Equal(t, "application/xml; charset=utf-8", junit.Header().Get("Content-Type"))
+	assert.Contains(t, junit.Body.String(), `<testsuites name="run-1" tests="2" failures="1" errors="0" skipped="0"`)
+
+	negotiated := export("", "application/xml")
+	assert.Equal(t, junit.Body.String(), negotiated.Body.String())
+
+	tap := export("", "text/x-tap")
+	assert.Equal(t, "text/plain; charset=utf-8", tap.Header().Get("Content-Type"))
+	assert.Contains(t, tap.Body.String(), "not ok 2 - GET /users\n")
+
+	markdown := export("&format=markdown", "application/json")
+	assert.Equal(t, "text/markdown; charset=utf-8", markdown.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(markdown.Body.String(), "### ❌ FAIL https://example.com\n"))
+
+	browser := export("", "text/html,application/xhtml+xml,*/*;q=0.8")
+	assert.Equal(t, "application/json; charset=utf-8", browser.Header().Get("Content-Type"))
+	client.AssertExpectations(t)
+}
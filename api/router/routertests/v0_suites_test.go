@@ -0,0 +1,191 @@
+package routertests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jgfranco17/aeternum/api/auth"
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSuiteRedactsCredentials(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	request := execution.TestExecutionRequest{
+		BaseURL:   "https://example.com/api",
+		Auth:      &execution.AuthConfig{Type: execution.AuthBearer, Token: "secret-token"},
+		Endpoints: []execution.Endpoint{{Path: "/health", ExpectedStatus: http.StatusOK}},
+	}
+	client := newMockDBClient()
+	client.On("CreateSuite", mock.Anything, "test-user-123", mock.MatchedBy(func(suite *db.Suite) bool {
+		return suite.Name == "smoke" && suite.Request.Auth.Token == "secret-token"
+	})).Return(&db.Suite{ID: "suite-1", Name: "smoke", Request: request}, nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:       "POST",
+			Endpoint:     "/v0/suites",
+			ExpectedCode: http.StatusCreated,
+			Payload: `{
+				"name": "smoke",
+				"request": {
+					"base_url": "https://example.com/api",
+					"auth": {"type": "bearer", "token": "secret-token"},
+					"endpoints": [{"path": "/health", "expected_status": 200}]
+				}
+			}`,
+			ExpectedFields: map[string]interface{}{
+				"id":      "suite-1",
+				"name":    "smoke",
+				"request": toJSONMap(t, request.Redacted()),
+			},
+		},
+		{
+			Method:       "POST",
+			Endpoint:     "/v0/suites",
+			ExpectedCode: http.StatusBadRequest,
+			Payload:      `{"request": {"base_url": "https://example.com/api"}}`,
+		},
+	}, token)
+	client.AssertExpectations(t)
+}
+
+func TestUpdateSuiteKeepsRedactedCredentials(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	stored := &db.Suite{
+		ID:   "suite-1",
+		Name: "smoke",
+		Request: execution.TestExecutionRequest{
+			BaseURL:   "https://example.com/api",
+			Auth:      &execution.AuthConfig{Type: execution.AuthBearer, Token: "secret-token"},
+			Endpoints: []execution.Endpoint{{Path: "/health", ExpectedStatus: http.StatusOK}},
+		},
+	}
+	client := newMockDBClient()
+	client.On("GetSuite", mock.Anything, "test-user-123", "suite-1").Return(stored, nil)
+	client.On("UpdateSuite", mock.Anything, "test-user-123", mock.MatchedBy(func(suite *db.Suite) bool {
+		return suite.Name == "renamed" && suite.Request.Auth.Token == "secret-token"
+	})).Return(&db.Suite{ID: "suite-1", Name: "renamed", Request: stored.Request}, nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	// Read the suite back and send it unchanged apart from its name
+	get := httptest.NewRequest("GET", "/v0/suites/suite-1", nil)
+	get.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	testService.service.Router.ServeHTTP(recorder, get)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var suite map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &suite))
+	suite["name"] = "renamed"
+	payload, err := json.Marshal(suite)
+	require.NoError(t, err)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:       "PUT",
+			Endpoint:     "/v0/suites/suite-1",
+			ExpectedCode: http.StatusOK,
+			Payload:      string(payload),
+			ExpectedFields: map[string]interface{}{
+				"name":    "renamed",
+				"request": toJSONMap(t, stored.Request.Redacted()),
+			},
+		},
+		{
+			// A masked credential under a different auth type has nothing to
+			// stand in for
+			Method:       "PUT",
+			Endpoint:     "/v0/suites/suite-1",
+			ExpectedCode: http.StatusBadRequest,
+			Payload: `{
+				"name": "renamed",
+				"request": {
+					"base_url": "https://example.com/api",
+					"auth": {"type": "basic", "username": "admin", "password": "[REDACTED]"},
+					"endpoints": [{"path": "/health", "expected_status": 200}]
+				}
+			}`,
+		},
+	}, token)
+	client.AssertExpectations(t)
+	client.AssertNumberOfCalls(t, "UpdateSuite", 1)
+}
+
+func TestSuiteNotFound(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	client := newMockDBClient()
+	client.On("GetSuite", mock.Anything, "test-user-123", "missing").Return(nil, db.ErrSuiteNotFound)
+	client.On("DeleteSuite", mock.Anything, "test-user-123", "missing").Return(db.ErrSuiteNotFound)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		NewBasicExampleRequest("GET", "/v0/suites/missing", http.StatusNotFound),
+		NewBasicExampleRequest("POST", "/v0/suites/missing/run", http.StatusNotFound),
+		NewBasicExampleRequest("DELETE", "/v0/suites/missing", http.StatusNotFound),
+	}, token)
+	client.AssertExpectations(t)
+}
+
+func TestRunSuiteStoresResultWithSuiteID(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	client := newMockDBClient()
+	client.On("GetSuite", mock.Anything, "test-user-123", "suite-1").Return(&db.Suite{
+		ID:   "suite-1",
+		Name: "smoke",
+		Request: execution.TestExecutionRequest{
+			BaseURL:   target.URL,
+			Endpoints: []execution.Endpoint{{Path: "/health", ExpectedStatus: http.StatusOK}},
+		},
+	}, nil)
+	client.On("StoreTestResult", mock.Anything, "test-user-123", mock.MatchedBy(func(result *execution.CheckResponse) bool {
+		return result.SuiteID == "suite-1"
+	})).Return(nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:       "POST",
+			Endpoint:     "/v0/suites/suite-1/run",
+			ExpectedCode: http.StatusOK,
+			ExpectedFields: map[string]interface{}{
+				"suite_id": "suite-1",
+				"status":   string(execution.StatusPass),
+			},
+		},
+	}, token)
+	client.AssertExpectations(t)
+	client.AssertNumberOfCalls(t, "StoreTestResult", 1)
+}
+
+func toJSONMap(t *testing.T, value interface{}) map[string]interface{} {
+	t.Helper()
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.NotContains(t, string(data), "secret-token")
+	return decoded
+}
@@ -2,12 +2,20 @@ package routertests
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jgfranco17/aeternum/api/auth"
 	"github.com/jgfranco17/aeternum/api/db"
 	"github.com/jgfranco17/aeternum/execution"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -16,11 +24,26 @@ type MockDBClient struct {
 	mock.Mock
 }
 
+// Creates a mock that tolerates the lookups made when runs are reported
+// to notification channels, which happen in the background.
+func newMockDBClient() *MockDBClient {
+	client := new(MockDBClient)
+	client.On("ListWebhooks", mock.Anything, mock.Anything).Return([]db.Webhook{}, nil).Maybe()
+	client.On("SetSuiteStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(execution.Status(""), nil).Maybe()
+	client.On("ListChatDestinations", mock.Anything, mock.Anything).Return([]db.ChatDestination{}, nil).Maybe()
+	return client
+}
+
 func (m *MockDBClient) StoreTestResult(ctx context.Context, userID string, result *execution.CheckResponse) error {
 	args := m.Called(ctx, userID, result)
 	return args.Error(0)
 }
 
+func (m *MockDBClient) UpdateTestResult(ctx context.Context, userID string, result *execution.CheckResponse) error {
+	args := m.Called(ctx, userID, result)
+	return args.Error(0)
+}
+
 func (m *MockDBClient) GetTestResult(ctx context.Context, userID, requestID string) (*db.TestResult, error) {
 	args := m.Called(ctx, userID, requestID)
 	if args.Get(0) == nil {
@@ -29,20 +52,227 @@ func (m *MockDBClient) GetTestResult(ctx context.Context, userID, requestID stri
 	return args.Get(0).(*db.TestResult), args.Error(1)
 }
 
-func (m *MockDBClient) GetUserTestResults(ctx context.Context, userID string, limit int) ([]db.TestResult, error) {
-	args := m.Called(ctx, userID, limit)
+func (m *MockDBClient) GetUserTestResults(ctx context.Context, userID string, query db.ResultQuery) (*db.ResultPage, error) {
+	args := m.Called(ctx, userID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.ResultPage), args.Error(1)
+}
+
+func (m *MockDBClient) DeleteTestResult(ctx context.Context, userID, requestID string) error {
+	args := m.Called(ctx, userID, requestID)
+	return args.Error(0)
+}
+
+func (m *MockDBClient) DeleteTestResults(ctx context.Context, userID string, query db.ResultQuery) (int64, error) {
+	args := m.Called(ctx, userID, query)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDBClient) DeleteTestResultsBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDBClient) TrimSuiteTestResults(ctx context.Context, keep int) (int64, error) {
+	args := m.Called(ctx, keep)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDBClient) GetSuiteTestResults(ctx context.Context, userID, suiteID string, limit int) ([]db.TestResult, error) {
+	args := m.Called(ctx, userID, suiteID, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]db.TestResult), args.Error(1)
 }
 
+func (m *MockDBClient) GetEndpointHealth(ctx context.Context, userID string, query db.ResultQuery) ([]db.EndpointHealth, error) {
+	args := m.Called(ctx, userID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.EndpointHealth), args.Error(1)
+}
+
+func (m *MockDBClient) CreateSuite(ctx context.Context, userID string, suite *db.Suite) (*db.Suite, error) {
+	args := m.Called(ctx, userID, suite)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Suite), args.Error(1)
+}
+
+func (m *MockDBClient) GetSuite(ctx context.Context, userID, suiteID string) (*db.Suite, error) {
+	args := m.Called(ctx, userID, suiteID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Suite), args.Error(1)
+}
+
+func (m *MockDBClient) ListSuites(ctx context.Context, userID string) ([]db.Suite, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.Suite), args.Error(1)
+}
+
+func (m *MockDBClient) UpdateSuite(ctx context.Context, userID string, suite *db.Suite) (*db.Suite, error) {
+	args := m.Called(ctx, userID, suite)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Suite), args.Error(1)
+}
+
+func (m *MockDBClient) DeleteSuite(ctx context.Context, userID, suiteID string) error {
+	args := m.Called(ctx, userID, suiteID)
+	return args.Error(0)
+}
+
+func (m *MockDBClient) CreateMonitor(ctx context.Context, userID string, monitor *db.Monitor) (*db.Monitor, error) {
+	args := m.Called(ctx, userID, monitor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Monitor), args.Error(1)
+}
+
+func (m *MockDBClient) GetMonitor(ctx context.Context, userID, monitorID string) (*db.Monitor, error) {
+	args := m.Called(ctx, userID, monitorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Monitor), args.Error(1)
+}
+
+func (m *MockDBClient) ListMonitors(ctx context.Context, userID string) ([]db.Monitor, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.Monitor), args.Error(1)
+}
+
+func (m *MockDBClient) UpdateMonitor(ctx context.Context, userID string, monitor *db.Monitor) (*db.Monitor, error) {
+	args := m.Called(ctx, userID, monitor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Monitor), args.Error(1)
+}
+
+func (m *MockDBClient) DeleteMonitor(ctx context.Context, userID, monitorID string) error {
+	args := m.Called(ctx, userID, monitorID)
+	return args.Error(0)
+}
+
+func (m *MockDBClient) ListDueMonitors(ctx context.Context, before time.Time) ([]db.Monitor, error) {
+	args := m.Called(ctx, before)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.Monitor), args.Error(1)
+}
+
+func (m *MockDBClient) ClaimMonitorRun(ctx context.Context, monitor *db.Monitor, nextRunAt time.Time) (bool, error) {
+	args := m.Called(ctx, monitor, nextRunAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDBClient) SetSuiteStatus(ctx context.Context, userID, suiteID string, status execution.Status) (execution.Status, error) {
+	args := m.Called(ctx, userID, suiteID, status)
+	return args.Get(0).(execution.Status), args.Error(1)
+}
+
+func (m *MockDBClient) CreateWebhook(ctx context.Context, userID string, webhook *db.Webhook) (*db.Webhook, error) {
+	args := m.Called(ctx, userID, webhook)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Webhook), args.Error(1)
+}
+
+func (m *MockDBClient) GetWebhook(ctx context.Context, userID, webhookID string) (*db.Webhook, error) {
+	args := m.Called(ctx, userID, webhookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Webhook), args.Error(1)
+}
+
+func (m *MockDBClient) ListWebhooks(ctx context.Context, userID string) ([]db.Webhook, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.Webhook), args.Error(1)
+}
+
+func (m *MockDBClient) UpdateWebhook(ctx context.Context, userID string, webhook *db.Webhook) (*db.Webhook, error) {
+	args := m.Called(ctx, userID, webhook)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Webhook), args.Error(1)
+}
+
+func (m *MockDBClient) DeleteWebhook(ctx context.Context, userID, webhookID string) error {
+	args := m.Called(ctx, userID, webhookID)
+	return args.Error(0)
+}
+
+func (m *MockDBClient) StoreWebhookDelivery(ctx context.Context, delivery *db.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockDBClient) ListWebhookDeliveries(ctx context.Context, userID, webhookID string, limit int) ([]db.WebhookDelivery, error) {
+	args := m.Called(ctx, userID, webhookID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockDBClient) CreateChatDestination(ctx context.Context, userID string, destination *db.ChatDestination) (*db.ChatDestination, error) {
+	args := m.Called(ctx, userID, destination)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.ChatDestination), args.Error(1)
+}
+
+func (m *MockDBClient) GetChatDestination(ctx context.Context, userID, destinationID string) (*db.ChatDestination, error) {
+	args := m.Called(ctx, userID, destinationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.ChatDestination), args.Error(1)
+}
+
+func (m *MockDBClient) ListChatDestinations(ctx context.Context, userID string) ([]db.ChatDestination, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.ChatDestination), args.Error(1)
+}
+
+func (m *MockDBClient) DeleteChatDestination(ctx context.Context, userID, destinationID string) error {
+	args := m.Called(ctx, userID, destinationID)
+	return args.Error(0)
+}
+
 func TestRunTestExecutionRequestSuccess(t *testing.T) {
 	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
 	token, err := auth.GenerateToken("test-user-123", "test@example.com")
 	require.NoError(t, err)
 
-	client := new(MockDBClient)
+	client := newMockDBClient()
 	client.On("StoreTestResult", mock.Anything, "test-user-123", mock.AnythingOfType("*execution.CheckResponse")).Return(nil)
 	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
 
@@ -70,3 +300,494 @@ func TestRunTestExecutionRequestSuccess(t *testing.T) {
 	testService.RunRequests(t, testRequest, token)
 	client.AssertExpectations(t)
 }
+
+func TestRunTestExecutionRequestMethodCase(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	var received string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Method
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer target.Close()
+
+	client := newMockDBClient()
+	client.On("StoreTestResult", mock.Anything, "test-user-123", mock.AnythingOfType("*execution.CheckResponse")).Return(nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:       "POST",
+			Endpoint:     "/v0/tests/run",
+			ExpectedCode: http.StatusOK,
+			Payload:      fmt.Sprintf(`{"base_url": %q, "endpoints": [{"path": "/items", "method": "post", "expected_status": 201}]}`, target.URL),
+			ExpectedFields: map[string]interface{}{
+				"status": string(execution.StatusPass),
+			},
+		},
+	}, token)
+	assert.Equal(t, http.MethodPost, received)
+	client.AssertExpectations(t)
+}
+
+func TestRunTestExecutionRequestAsync(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	statuses := make(chan execution.Status, 10)
+	client := newMockDBClient()
+	client.On("StoreTestResult", mock.Anything, "test-user-123", mock.MatchedBy(func(result *execution.CheckResponse) bool {
+		return result.Status == execution.StatusPending
+	})).Return(nil)
+	client.On("UpdateTestResult", mock.Anything, "test-user-123", mock.AnythingOfType("*execution.CheckResponse")).
+		Run(func(args mock.Arguments) {
+			statuses <- args.Get(2).(*execution.CheckResponse).Status
+		}).Return(nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:       "POST",
+			Endpoint:     "/v0/tests/run?async=true",
+			ExpectedCode: http.StatusAccepted,
+			Payload:      `{"base_url": "` + target.URL + `", "endpoints": [{"path": "/health", "expected_status": 200}]}`,
+			ExpectedFields: map[string]interface{}{
+				"status": string(execution.StatusPending),
+			},
+		},
+	}, token)
+
+	// The run is marked as running, with progress stored as endpoints
+	// complete, before the final result is written.
+	assert.Equal(t, execution.StatusRunning, <-statuses)
+	final := <-statuses
+	for final == execution.StatusRunning {
+		final = <-statuses
+	}
+	assert.Equal(t, execution.StatusPass, final)
+	client.AssertExpectations(t)
+}
+
+func TestStreamAsyncTestRun(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	client := newMockDBClient()
+	client.On("StoreTestResult", mock.Anything, "test-user-123", mock.AnythingOfType("*execution.CheckResponse")).Return(nil)
+	client.On("UpdateTestResult", mock.Anything, "test-user-123", mock.AnythingOfType("*execution.CheckResponse")).Return(nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+	server := httptest.NewServer(testService.service.Router)
+	defer server.Close()
+
+	payload := `{"base_url": "` + target.URL + `", "endpoints": [{"path": "/a", "expected_status": 200}, {"path": "/b", "expected_status": 200}]}`
+	request, err := http.NewRequest("POST", server.URL+"/v0/tests/run?async=true", strings.NewReader(payload))
+	require.NoError(t, err)
+	request.Header.Set("Authorization", "Bearer "+token)
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	var pending execution.CheckResponse
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&pending))
+	response.Body.Close()
+	require.Equal(t, http.StatusAccepted, response.StatusCode)
+
+	request, err = http.NewRequest("GET", server.URL+"/v0/tests/run/"+pending.RequestID+"/stream", nil)
+	require.NoError(t, err)
+	request.Header.Set("Authorization", "Bearer "+token)
+	response, err = http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Contains(t, response.Header.Get("Content-Type"), "text/event-stream")
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(body), "event:result"))
+	assert.Equal(t, 1, strings.Count(string(body), "event:summary"))
+	assert.Contains(t, string(body), `"status":"PASS"`)
+}
+
+func TestGetTestResultById(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	client := newMockDBClient()
+	client.On("GetTestResult", mock.Anything, "test-user-123", "run-1").
+		Return(&db.TestResult{RequestID: "run-1", Status: execution.StatusPass}, nil)
+	client.On("GetTestResult", mock.Anything, "test-user-123", "missing").Return(nil, db.ErrTestResultNotFound)
+	client.On("GetTestResult", mock.Anything, "test-user-123", "broken").Return(nil, errors.New("connection reset"))
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/tests/results?id=run-1",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"request_id": "run-1", "status": "PASS"},
+		},
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/tests/results?id=missing",
+			ExpectedCode:   http.StatusNotFound,
+			ExpectedFields: map[string]interface{}{"message": "No result found for ID missing"},
+		},
+		NewBasicExampleRequest("GET", "/v0/tests/results?id=broken", http.StatusInternalServerError),
+		NewBasicExampleRequest("GET", "/v0/tests/results", http.StatusBadRequest),
+	}, token)
+	client.AssertExpectations(t)
+}
+
+func TestStreamStoredTestRun(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	check := execution.CheckResult{Path: "/a", ExpectedStatus: 200, ActualStatus: 200, StatusCode: string(execution.StatusPass)}
+	client := newMockDBClient()
+	client.On("GetTestResult", mock.Anything, "test-user-123", "finished").Return(&db.TestResult{
+		RequestID: "finished", Status: execution.StatusPass, Results: []execution.CheckResult{check},
+	}, nil)
+	client.On("GetTestResult", mock.Anything, "test-user-123", "elsewhere").Return(&db.TestResult{
+		RequestID: "elsewhere", Status: execution.StatusRunning, Results: []execution.CheckResult{check},
+	}, nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+	server := httptest.NewServer(testService.service.Router)
+	defer server.Close()
+
+	stream := func(runID string) string {
+		request, err := http.NewRequest("GET", server.URL+"/v0/tests/run/"+runID+"/stream", nil)
+		require.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	body := stream("finished")
+	assert.Equal(t, 1, strings.Count(body, "event:result"))
+	assert.Contains(t, body, "event:summary")
+	assert.NotContains(t, body, "event:error")
+
+	// A run still executing on another server has no summary to replay yet
+	body = stream("elsewhere")
+	assert.Equal(t, 1, strings.Count(body, "event:result"))
+	assert.NotContains(t, body, "event:summary")
+	assert.Contains(t, body, "event:error")
+	assert.Contains(t, body, `"result_url":"/v0/tests/results?id=elsewhere"`)
+	client.AssertExpectations(t)
+}
+
+func TestStreamUnknownTestRun(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	client := newMockDBClient()
+	client.On("GetTestResult", mock.Anything, "test-user-123", "missing").Return(nil, db.ErrTestResultNotFound)
+	client.On("GetTestResult", mock.Anything, "test-user-123", "broken").Return(nil, errors.New("connection reset"))
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/tests/run/missing/stream",
+			ExpectedCode:   http.StatusNotFound,
+			ExpectedFields: map[string]interface{}{"message": "No result found for ID missing"},
+		},
+		NewBasicExampleRequest("GET", "/v0/tests/run/broken/stream", http.StatusInternalServerError),
+	}, token)
+	client.AssertExpectations(t)
+}
+
+func TestCancelAsyncTestRun(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	started := make(chan struct{}, 1)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer target.Close()
+
+	final := make(chan *execution.CheckResponse, 1)
+	client := newMockDBClient()
+	client.On("StoreTestResult", mock.Anything, "test-user-123", mock.AnythingOfType("*execution.CheckResponse")).Return(nil)
+	client.On("UpdateTestResult", mock.Anything, "test-user-123", mock.AnythingOfType("*execution.CheckResponse")).
+		Run(func(args mock.Arguments) {
+			if result := args.Get(2).(*execution.CheckResponse); result.Status != execution.StatusRunning {
+				final <- result
+			}
+		}).Return(nil)
+	client.On("GetTestResult", mock.Anything, "test-user-123", "finished").
+		Return(&db.TestResult{RequestID: "finished", Status: execution.StatusPass}, nil)
+	client.On("GetTestResult", mock.Anything, "test-user-123", "missing").Return(nil, db.ErrTestResultNotFound)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+	server := httptest.NewServer(testService.service.Router)
+	defer server.Close()
+
+	payload := `{"base_url": "` + target.URL + `", "endpoints": [{"path": "/slow", "expected_status": 200}]}`
+	request, err := http.NewRequest("POST", server.URL+"/v0/tests/run?async=true", strings.NewReader(payload))
+	require.NoError(t, err)
+	request.Header.Set("Authorization", "Bearer "+token)
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	var pending execution.CheckResponse
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&pending))
+	response.Body.Close()
+
+	<-started
+	testService.RunRequests(t, []ExampleHttpRequest{
+		NewBasicExampleRequest("POST", "/v0/tests/runs/"+pending.RequestID+"/cancel", http.StatusAccepted),
+		NewBasicExampleRequest("POST", "/v0/tests/runs/finished/cancel", http.StatusConflict),
+		NewBasicExampleRequest("POST", "/v0/tests/runs/missing/cancel", http.StatusNotFound),
+	}, token)
+
+	result := <-final
+	assert.Equal(t, execution.StatusCancelled, result.Status)
+	require.Len(t, result.Results, 1)
+	assert.Equal(t, string(execution.StatusCancelled), result.Results[0].StatusCode)
+}
+
+func TestGetTestHistory(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	client := newMockDBClient()
+	client.On("GetUserTestResults", mock.Anything, "test-user-123", db.ResultQuery{Limit: 10}).
+		Return(&db.ResultPage{Results: []db.TestResult{{RequestID: "run-1"}}}, nil)
+	client.On("GetUserTestResults", mock.Anything, "test-user-123", db.ResultQuery{Limit: 100}).
+		Return(&db.ResultPage{Results: []db.TestResult{{RequestID: "run-1"}}}, nil)
+	client.On("GetUserTestResults", mock.Anything, "test-user-123", db.ResultQuery{
+		Limit:       2,
+		Cursor:      "abc",
+		Statuses:    []execution.Status{execution.StatusFail, execution.StatusError},
+		BaseURL:     "https://example.com",
+		SuiteID:     "suite-1",
+		Tags:        []string{"prod", "smoke"},
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+		Ascending:   true,
+	}).Return(&db.ResultPage{Results: []db.TestResult{{RequestID: "run-2"}, {RequestID: "run-3"}}, NextCursor: "next"}, nil)
+	client.On("GetUserTestResults", mock.Anything, "test-user-123", db.ResultQuery{Limit: 10, Cursor: "stale"}).
+		Return(nil, db.ErrInvalidCursor)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/tests/history",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"count": float64(1), "next_cursor": nil},
+		},
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/tests/history?limit=2&cursor=abc&status=fail,error&base_url=https://example.com&suite_id=suite-1&tag=prod&tag=smoke&from=2024-03-01&to=2024-03-02T12:00:00Z&order=asc",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"count": float64(2), "next_cursor": "next"},
+		},
+		NewBasicExampleRequest("GET", "/v0/tests/history?cursor=stale", http.StatusBadRequest),
+		// A zero limit gets the default page size and a large one the largest
+		NewBasicExampleRequest("GET", "/v0/tests/history?limit=0", http.StatusOK),
+		NewBasicExampleRequest("GET", "/v0/tests/history?limit=500", http.StatusOK),
+		NewBasicExampleRequest("GET", "/v0/tests/history?limit=ten", http.StatusBadRequest),
+		NewBasicExampleRequest("GET", "/v0/tests/history?status=flaky", http.StatusBadRequest),
+		NewBasicExampleRequest("GET", "/v0/tests/history?from=yesterday", http.StatusBadRequest),
+		NewBasicExampleRequest("GET", "/v0/tests/history?from=2024-03-02&to=2024-03-01", http.StatusBadRequest),
+		NewBasicExampleRequest("GET", "/v0/tests/history?order=random", http.StatusBadRequest),
+	}, token)
+	client.AssertExpectations(t)
+}
+
+func TestDeleteTestResults(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	client := newMockDBClient()
+	client.On("DeleteTestResult", mock.Anything, "test-user-123", "run-1").Return(nil)
+	client.On("DeleteTestResult", mock.Anything, "test-user-123", "missing").Return(db.ErrTestResultNotFound)
+	client.On("DeleteTestResults", mock.Anything, "test-user-123", db.ResultQuery{
+		Statuses: []execution.Status{execution.StatusError},
+		SuiteID:  "suite-1",
+	}).Return(int64(3), nil)
+	client.On("DeleteTestResults", mock.Anything, "test-user-123", db.ResultQuery{}).Return(int64(7), nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		NewBasicExampleRequest("DELETE", "/v0/tests/results/run-1", http.StatusOK),
+		NewBasicExampleRequest("DELETE", "/v0/tests/results/missing", http.StatusNotFound),
+		{
+			Method:         "DELETE",
+			Endpoint:       "/v0/tests/results?status=error&suite_id=suite-1",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"deleted": float64(3)},
+		},
+		NewBasicExampleRequest("DELETE", "/v0/tests/results", http.StatusBadRequest),
+		NewBasicExampleRequest("DELETE", "/v0/tests/results?status=flaky", http.StatusBadRequest),
+		{
+			Method:         "DELETE",
+			Endpoint:       "/v0/tests/results?all=true",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"deleted": float64(7)},
+		},
+	}, token)
+	client.AssertExpectations(t)
+}
+
+func TestCompareTestResults(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	headCreated := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	base := &db.TestResult{
+		ID:      "run-1",
+		BaseURL: "https://example.com",
+		Status:  execution.StatusPass,
+		Results: []execution.CheckResult{
+			{Path: "/health", Method: "GET", ActualStatus: 200, StatusCode: "PASS"},
+			{Path: "/legacy", Method: "GET", ActualStatus: 200, StatusCode: "PASS"},
+		},
+	}
+	head := &db.TestResult{
+		ID:        "run-2",
+		BaseURL:   "https://example.com",
+		Status:    execution.StatusFail,
+		CreatedAt: headCreated,
+		Results: []execution.CheckResult{
+			{Path: "/health", Method: "GET", ActualStatus: 503, StatusCode: "FAIL"},
+			{Path: "/users", Method: "POST", ActualStatus: 201, StatusCode: "PASS"},
+		},
+	}
+	summary := map[string]interface{}{
+		"newly_failing":       float64(1),
+		"newly_passing":       float64(0),
+		"still_failing":       float64(0),
+		"unchanged":           float64(0),
+		"added":               float64(1),
+		"removed":             float64(1),
+		"status_code_changes": float64(1),
+	}
+
+	client := newMockDBClient()
+	client.On("GetTestResult", mock.Anything, "test-user-123", "run-1").Return(base, nil)
+	client.On("GetTestResult", mock.Anything, "test-user-123", "run-2").Return(head, nil)
+	client.On("GetTestResult", mock.Anything, "test-user-123", "missing").Return(nil, db.ErrTestResultNotFound)
+	client.On("GetTestResult", mock.Anything, "test-user-123", "run-3").
+		Return(&db.TestResult{ID: "run-3", BaseURL: "https://other.example.com", CreatedAt: headCreated}, nil)
+	client.On("GetUserTestResults", mock.Anything, "test-user-123", db.ResultQuery{
+		Limit:     1,
+		Statuses:  []execution.Status{execution.StatusPass},
+		BaseURL:   "https://example.com",
+		CreatedTo: &headCreated,
+	}).Return(&db.ResultPage{Results: []db.TestResult{*base}}, nil)
+	client.On("GetUserTestResults", mock.Anything, "test-user-123", db.ResultQuery{
+		Limit:     1,
+		Statuses:  []execution.Status{execution.StatusPass},
+		BaseURL:   "https://other.example.com",
+		CreatedTo: &headCreated,
+	}).Return(&db.ResultPage{Results: []db.TestResult{}}, nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/tests/compare?base=run-1&head=run-2",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"summary": summary},
+		},
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/tests/compare?head=run-2",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"summary": summary},
+		},
+		NewBasicExampleRequest("GET", "/v0/tests/compare", http.StatusBadRequest),
+		NewBasicExampleRequest("GET", "/v0/tests/compare?head=missing", http.StatusNotFound),
+		NewBasicExampleRequest("GET", "/v0/tests/compare?base=missing&head=run-2", http.StatusNotFound),
+		NewBasicExampleRequest("GET", "/v0/tests/compare?head=run-3", http.StatusNotFound),
+	}, token)
+	client.AssertExpectations(t)
+}
+
+func TestGetEndpointHealth(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	health := []db.EndpointHealth{
+		{BaseURL: "https://example.com", Method: "GET", Path: "/health", Runs: 5, Passed: 3, Flips: 3, FlipRate: 0.75},
+		{BaseURL: "https://example.com", Method: "GET", Path: "/users", Runs: 2, Passed: 1, Flips: 1, FlipRate: 1},
+	}
+
+	client := newMockDBClient()
+	client.On("GetEndpointHealth", mock.Anything, "test-user-123", db.ResultQuery{
+		BaseURL:     "https://example.com",
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+	}).Return(health, nil)
+	client.On("GetEndpointHealth", mock.Anything, "test-user-123", mock.MatchedBy(func(query db.ResultQuery) bool {
+		return query.BaseURL == "" && query.CreatedFrom != nil && query.CreatedTo != nil &&
+			query.CreatedTo.Sub(*query.CreatedFrom) == 7*24*time.Hour
+	})).Return([]db.EndpointHealth{}, nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/analytics/endpoints?base_url=https://example.com&from=2024-03-01&to=2024-03-08",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"flaky": float64(1), "flaky_threshold": 0.2},
+		},
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/analytics/endpoints?base_url=https://example.com&from=2024-03-01&to=2024-03-08&flaky_threshold=0.8",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"flaky": float64(0), "flaky_threshold": 0.8},
+		},
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/analytics/endpoints",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"flaky": float64(0), "endpoints": []interface{}{}},
+		},
+		NewBasicExampleRequest("GET", "/v0/analytics/endpoints?flaky_threshold=2", http.StatusBadRequest),
+		NewBasicExampleRequest("GET", "/v0/analytics/endpoints?flaky_threshold=often", http.StatusBadRequest),
+		NewBasicExampleRequest("GET", "/v0/analytics/endpoints?from=yesterday", http.StatusBadRequest),
+	}, token)
+
+	t.Setenv("AETERNUM_FLAKY_THRESHOLD", "0.8")
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/analytics/endpoints?base_url=https://example.com&from=2024-03-01&to=2024-03-08",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"flaky": float64(0), "flaky_threshold": 0.8},
+		},
+	}, token)
+	client.AssertExpectations(t)
+}
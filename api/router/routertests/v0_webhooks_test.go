@@ -0,0 +1,71 @@
+package routertests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jgfranco17/aeternum/api/auth"
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateWebhookGeneratesSecret(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	client := newMockDBClient()
+	client.On("CreateWebhook", mock.Anything, "test-user-123", mock.MatchedBy(func(webhook *db.Webhook) bool {
+		return len(webhook.Secret) > 16 && webhook.Active
+	})).Return(&db.Webhook{ID: "hook-1", URL: "https://hooks.example.com", Secret: "whsec_generated", Active: true}, nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:         "POST",
+			Endpoint:       "/v0/webhooks",
+			ExpectedCode:   http.StatusCreated,
+			Payload:        `{"url": "https://hooks.example.com", "events": ["run.completed"]}`,
+			ExpectedFields: map[string]interface{}{"id": "hook-1", "secret": "whsec_generated"},
+		},
+		{
+			Method:       "POST",
+			Endpoint:     "/v0/webhooks",
+			ExpectedCode: http.StatusBadRequest,
+			Payload:      `{"url": "https://hooks.example.com", "events": ["run.started"]}`,
+		},
+	}, token)
+	client.AssertExpectations(t)
+}
+
+func TestGetWebhookAndDeliveries(t *testing.T) {
+	t.Setenv("AETERNUM_JWT_SECRET", "test-secret-key")
+	token, err := auth.GenerateToken("test-user-123", "test@example.com")
+	require.NoError(t, err)
+
+	client := new(MockDBClient)
+	client.On("GetWebhook", mock.Anything, "test-user-123", "hook-1").
+		Return(&db.Webhook{ID: "hook-1", URL: "https://hooks.example.com", Secret: "whsec_stored"}, nil)
+	client.On("GetWebhook", mock.Anything, "test-user-123", "missing").Return(nil, db.ErrWebhookNotFound)
+	client.On("ListWebhookDeliveries", mock.Anything, "test-user-123", "hook-1", 5).
+		Return([]db.WebhookDelivery{{WebhookID: "hook-1", Attempt: 1, StatusCode: 500}}, nil)
+	testService := NewTestServer(8800).WithSystemRoutes().WithV0Routes(client)
+
+	testService.RunRequests(t, []ExampleHttpRequest{
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/webhooks/hook-1",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"id": "hook-1", "url": "https://hooks.example.com"},
+		},
+		{
+			Method:         "GET",
+			Endpoint:       "/v0/webhooks/hook-1/deliveries?limit=5",
+			ExpectedCode:   http.StatusOK,
+			ExpectedFields: map[string]interface{}{"count": float64(1)},
+		},
+		NewBasicExampleRequest("GET", "/v0/webhooks/missing/deliveries", http.StatusNotFound),
+	}, token)
+	client.AssertExpectations(t)
+}
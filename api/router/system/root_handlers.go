@@ -40,6 +40,9 @@ func RegisterHandler() func(c *gin.Context) error {
 		}
 
 		client := db.GetSupabaseClient()
+		if client == nil {
+			return fmt.Errorf("Supabase Auth is not available")
+		}
 		signupReq := types.SignupRequest{
 			Email:    regReq.Email,
 			Password: regReq.Password,
@@ -66,6 +69,9 @@ func LoginHandler() func(c *gin.Context) error {
 		}
 
 		client := db.GetSupabaseClient()
+		if client == nil {
+			return fmt.Errorf("Supabase Auth is not available")
+		}
 		tokenReq := types.TokenRequest{
 			Email:     loginReq.Email,
 			Password:  loginReq.Password,
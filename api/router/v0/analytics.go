@@ -0,0 +1,84 @@
+package v0
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/auth"
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/environment"
+	"github.com/jgfranco17/aeternum/api/httperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultHealthWindow is how far back endpoint health looks without a from
+	defaultHealthWindow = 7 * 24 * time.Hour
+	// defaultFlakyThreshold is the flip rate above which endpoints are flaky
+	defaultFlakyThreshold = 0.2
+	// minFlakyRuns keeps endpoints with too few runs to judge from being flagged
+	minFlakyRuns = 3
+)
+
+// Report the pass rate, flips, failing streaks and recovery time of each
+// endpoint the user checked within a time window, flagging flaky ones
+func getEndpointHealth(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		var query db.ResultQuery
+		if err := resultFilters(c, &query); err != nil {
+			return httperror.New(c, http.StatusBadRequest, err.Error())
+		}
+		defaultWindow(&query, defaultHealthWindow)
+
+		threshold := environment.GetFloatEnvWithDefault(environment.ENV_KEY_FLAKY_THRESHOLD, defaultFlakyThreshold)
+		if value := c.Query("flaky_threshold"); value != "" {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil || parsed <= 0 || parsed > 1 {
+				return httperror.New(c, http.StatusBadRequest, "flaky_threshold must be a number above 0 and at most 1")
+			}
+			threshold = parsed
+		}
+
+		health, err := dbClient.GetEndpointHealth(c, userClaims.UserID, query)
+		if err != nil {
+			return fmt.Errorf("Failed to fetch endpoint health: %w", err)
+		}
+		flaky := 0
+		for i := range health {
+			health[i].Flaky = health[i].Runs >= minFlakyRuns && health[i].FlipRate >= threshold
+			if health[i].Flaky {
+				flaky++
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"from":            query.CreatedFrom,
+			"to":              query.CreatedTo,
+			"flaky_threshold": threshold,
+			"flaky":           flaky,
+			"endpoints":       health,
+		})
+		return nil
+	}
+}
+
+// defaultWindow ends a window without a to at the current time, and starts
+// one without a from the given length before its end
+func defaultWindow(query *db.ResultQuery, length time.Duration) {
+	if query.CreatedTo == nil {
+		now := time.Now().UTC()
+		query.CreatedTo = &now
+	}
+	if query.CreatedFrom == nil {
+		from := query.CreatedTo.Add(-length)
+		query.CreatedFrom = &from
+	}
+}
@@ -0,0 +1,146 @@
+package v0
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jgfranco17/aeternum/api/auth"
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/httperror"
+	"github.com/jgfranco17/aeternum/api/notify"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChatDestinationRequest represents the create chat destination request body
+type ChatDestinationRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Platform   string `json:"platform" binding:"required,oneof=slack teams generic"`
+	WebhookURL string `json:"webhook_url" binding:"required,url"`
+	SuiteID    string `json:"suite_id,omitempty"`
+}
+
+func chatDestinationNotFound(c *gin.Context, destinationID string) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"message": fmt.Sprintf("No chat destination found for ID %s", destinationID),
+	})
+}
+
+func createChatDestination(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		var req ChatDestinationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			return httperror.New(c, http.StatusBadRequest, "Invalid request body: %v", err)
+		}
+
+		if req.SuiteID != "" {
+			_, err := dbClient.GetSuite(c, userClaims.UserID, req.SuiteID)
+			if errors.Is(err, db.ErrSuiteNotFound) {
+				suiteNotFound(c, req.SuiteID)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("Failed to fetch suite: %w", err)
+			}
+		}
+
+		created, err := dbClient.CreateChatDestination(c, userClaims.UserID, &db.ChatDestination{
+			Name:       req.Name,
+			Platform:   req.Platform,
+			WebhookURL: req.WebhookURL,
+			SuiteID:    req.SuiteID,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to create chat destination: %w", err)
+		}
+
+		c.JSON(http.StatusCreated, created)
+		return nil
+	}
+}
+
+func listChatDestinations(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		destinations, err := dbClient.ListChatDestinations(c, userClaims.UserID)
+		if err != nil {
+			return fmt.Errorf("Failed to fetch chat destinations: %w", err)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"destinations": destinations,
+			"count":        len(destinations),
+		})
+		return nil
+	}
+}
+
+func deleteChatDestination(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		destinationID := c.Param("id")
+		err := dbClient.DeleteChatDestination(c, userClaims.UserID, destinationID)
+		if errors.Is(err, db.ErrChatDestinationNotFound) {
+			chatDestinationNotFound(c, destinationID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to delete chat destination: %w", err)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": fmt.Sprintf("Deleted chat destination %s", destinationID),
+		})
+		return nil
+	}
+}
+
+// Post a sample failing run to the destination so its setup can be checked
+func testChatDestination(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		destinationID := c.Param("id")
+		destination, err := dbClient.GetChatDestination(c, userClaims.UserID, destinationID)
+		if errors.Is(err, db.ErrChatDestinationNotFound) {
+			chatDestinationNotFound(c, destinationID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to fetch chat destination: %w", err)
+		}
+
+		statusCode, err := notify.NewChatNotifier(dbClient).Send(c, *destination, notify.SampleEvent(userClaims.UserID))
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{
+				"delivered":   false,
+				"status_code": statusCode,
+				"message":     fmt.Sprintf("Failed to send test message: %v", err),
+			})
+			return nil
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"delivered":   true,
+			"status_code": statusCode,
+		})
+		return nil
+	}
+}
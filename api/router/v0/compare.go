@@ -0,0 +1,82 @@
+package v0
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	exec "github.com/jgfranco17/aeternum/execution"
+
+	"github.com/jgfranco17/aeternum/api/auth"
+	"github.com/jgfranco17/aeternum/api/compare"
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/httperror"
+	"github.com/jgfranco17/aeternum/api/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Compare two stored runs by endpoint. Without a base run, the head run is
+// compared with the latest passing run against the same base URL before it.
+func compareTestResults(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		headID := c.Query("head")
+		if headID == "" {
+			return httperror.New(c, http.StatusBadRequest, "Empty head parameter")
+		}
+		head, err := dbClient.GetTestResult(c, userClaims.UserID, headID)
+		if errors.Is(err, db.ErrTestResultNotFound) {
+			resultNotFound(c, headID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to fetch test result: %w", err)
+		}
+
+		var base *db.TestResult
+		if baseID := c.Query("base"); baseID != "" {
+			base, err = dbClient.GetTestResult(c, userClaims.UserID, baseID)
+			if errors.Is(err, db.ErrTestResultNotFound) {
+				resultNotFound(c, baseID)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("Failed to fetch test result: %w", err)
+			}
+		} else {
+			page, err := dbClient.GetUserTestResults(c, userClaims.UserID, db.ResultQuery{
+				Limit:     1,
+				Statuses:  []exec.Status{exec.StatusPass},
+				BaseURL:   head.BaseURL,
+				CreatedTo: &head.CreatedAt,
+			})
+			if err != nil {
+				return fmt.Errorf("Failed to fetch last passing run: %w", err)
+			}
+			if len(page.Results) == 0 {
+				c.JSON(http.StatusNotFound, gin.H{
+					"message": fmt.Sprintf("No passing run of %s found before %s", head.BaseURL, headID),
+				})
+				return nil
+			}
+			base = &page.Results[0]
+		}
+
+		report := compare.Compare(*base, *head)
+		logging.FromContext(c).Infof("Compared run %s with %s: %d newly failing, %d newly passing",
+			head.ID, base.ID, report.Summary.NewlyFailing, report.Summary.NewlyPassing)
+		c.JSON(http.StatusOK, report)
+		return nil
+	}
+}
+
+func resultNotFound(c *gin.Context, resultID string) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"message": fmt.Sprintf("No result found for ID %s", resultID),
+	})
+}
@@ -1,8 +1,13 @@
 package v0
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	exec "github.com/jgfranco17/aeternum/execution"
 
@@ -10,11 +15,25 @@ import (
 	"github.com/jgfranco17/aeternum/api/db"
 	"github.com/jgfranco17/aeternum/api/httperror"
 	"github.com/jgfranco17/aeternum/api/logging"
+	"github.com/jgfranco17/aeternum/api/notify"
+	"github.com/jgfranco17/aeternum/api/reports"
 
 	"github.com/gin-gonic/gin"
 )
 
-func runTests(dbClient db.DatabaseClient) func(c *gin.Context) error {
+const (
+	// defaultHistoryLimit is the page size of test history without a limit
+	defaultHistoryLimit = 10
+	// maxHistoryLimit is the largest page of test history returned at once
+	maxHistoryLimit = 100
+)
+
+// historyStatuses are the statuses test history can be filtered by
+var historyStatuses = []exec.Status{
+	exec.StatusPending, exec.StatusRunning, exec.StatusPass, exec.StatusFail, exec.StatusError, exec.StatusCancelled,
+}
+
+func runTests(dbClient db.DatabaseClient, runs *asyncRuns, notifier *notify.Dispatcher) func(c *gin.Context) error {
 	return func(c *gin.Context) error {
 		// Get user claims from context
 		userClaims, exists := auth.GetUserClaims(c)
@@ -27,16 +46,21 @@ func runTests(dbClient db.DatabaseClient) func(c *gin.Context) error {
 			return fmt.Errorf("Invalid request body: %w", err)
 		}
 
-		response, err := exec.ExecuteTests(c, req)
-		if err != nil {
-			return fmt.Errorf("Failed to execute tests: %w", err)
+		async := false
+		if asyncStr := c.Query("async"); asyncStr != "" {
+			parsed, err := strconv.ParseBool(asyncStr)
+			if err != nil {
+				return httperror.New(c, http.StatusBadRequest, "Invalid async parameter")
+			}
+			async = parsed
+		}
+		if async {
+			return runs.enqueue(c, dbClient, userClaims.UserID, req)
 		}
 
-		err = dbClient.StoreTestResult(c, userClaims.UserID, response)
+		response, err := executeAndStore(c, dbClient, notifier, userClaims.UserID, req, "")
 		if err != nil {
-			// Log the error but don't fail the request
-			log := logging.FromContext(c)
-			log.Errorf("Failed to store test result: %v", err)
+			return err
 		}
 
 		c.JSON(http.StatusOK, response)
@@ -44,6 +68,26 @@ func runTests(dbClient db.DatabaseClient) func(c *gin.Context) error {
 	}
 }
 
+// Run the tests and store the result, linking it to a suite if one is given
+func executeAndStore(c *gin.Context, dbClient db.DatabaseClient, notifier *notify.Dispatcher, userID string, req exec.TestExecutionRequest, suiteID string) (*exec.CheckResponse, error) {
+	response, err := exec.ExecuteTests(c, req)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to execute tests: %w", err)
+	}
+	response.SuiteID = suiteID
+
+	err = dbClient.StoreTestResult(c, userID, response)
+	if err != nil {
+		// Log the error but don't fail the request
+		log := logging.FromContext(c)
+		log.Errorf("Failed to store test result: %v", err)
+	}
+	// Gin reuses the context once the handler returns, so notifications
+	// delivered in the background get a copy of it
+	notifier.RunCompleted(c.Copy(), userID, response)
+	return response, nil
+}
+
 func getTestResultsById(dbClient db.DatabaseClient) func(c *gin.Context) error {
 	return func(c *gin.Context) error {
 		// Get user claims from context
@@ -57,24 +101,29 @@ func getTestResultsById(dbClient db.DatabaseClient) func(c *gin.Context) error {
 		if resultId == "" {
 			return httperror.New(c, http.StatusBadRequest, "Empty ID parameter")
 		}
+		format, ok := resultFormat(c)
+		if !ok {
+			return httperror.New(c, http.StatusBadRequest, "format must be 'json', 'junit', 'tap' or 'markdown'")
+		}
 
 		result, err := dbClient.GetTestResult(c, userClaims.UserID, resultId)
+		if errors.Is(err, db.ErrTestResultNotFound) {
+			resultNotFound(c, resultId)
+			return nil
+		}
 		if err != nil {
 			return fmt.Errorf("Failed to fetch test result: %w", err)
 		}
-		if result == nil {
-			c.JSON(http.StatusNotFound, gin.H{
-				"message": fmt.Sprintf("No result found for ID %s", resultId),
-			})
-			return fmt.Errorf("No result found for ID %s", resultId)
-		}
 		log.Infof("Found results for ID %s", resultId)
+		if format != reports.FormatJSON {
+			return writeResult(c, *result, format)
+		}
 		c.JSON(http.StatusOK, result)
 		return nil
 	}
 }
 
-// New handler to get all test results for a user
+// New handler to get a page of the test results of a user
 func getUserTestResults(dbClient db.DatabaseClient) func(c *gin.Context) error {
 	return func(c *gin.Context) error {
 		// Get user claims from context
@@ -83,23 +132,167 @@ func getUserTestResults(dbClient db.DatabaseClient) func(c *gin.Context) error {
 			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
 		}
 
-		// Get limit from query parameter, default to 10
-		limit := 10
-		if limitStr := c.Query("limit"); limitStr != "" {
-			if parsed, err := fmt.Sscanf(limitStr, "%d", &limit); err != nil || parsed != 1 {
-				return httperror.New(c, http.StatusBadRequest, "Invalid limit parameter")
-			}
+		query, err := historyQuery(c)
+		if err != nil {
+			return httperror.New(c, http.StatusBadRequest, err.Error())
 		}
 
-		results, err := dbClient.GetUserTestResults(c, userClaims.UserID, limit)
+		page, err := dbClient.GetUserTestResults(c, userClaims.UserID, query)
+		if errors.Is(err, db.ErrInvalidCursor) {
+			return httperror.New(c, http.StatusBadRequest, "Invalid cursor parameter")
+		}
 		if err != nil {
 			return fmt.Errorf("Failed to fetch user test results: %w", err)
 		}
 
+		response := gin.H{
+			"results":     page.Results,
+			"count":       len(page.Results),
+			"next_cursor": nil,
+		}
+		if page.NextCursor != "" {
+			response["next_cursor"] = page.NextCursor
+		}
+		c.JSON(http.StatusOK, response)
+		return nil
+	}
+}
+
+func deleteTestResult(dbClient db.DatabaseClient, runs *asyncRuns) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		resultID := c.Param("id")
+		err := dbClient.DeleteTestResult(c, userClaims.UserID, resultID)
+		if errors.Is(err, db.ErrTestResultNotFound) {
+			resultNotFound(c, resultID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to delete test result: %w", err)
+		}
+		// There is nothing left to store the outcome of a run still
+		// executing on this server in
+		runs.active.Cancel(resultID, userClaims.UserID)
+
 		c.JSON(http.StatusOK, gin.H{
-			"results": results,
-			"count":   len(results),
+			"message": fmt.Sprintf("Deleted result %s", resultID),
 		})
 		return nil
 	}
 }
+
+// Delete every result matching the history filters. At least one filter
+// is required, so that a bare request cannot wipe the whole history.
+func deleteTestResults(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		var query db.ResultQuery
+		if err := resultFilters(c, &query); err != nil {
+			return httperror.New(c, http.StatusBadRequest, err.Error())
+		}
+		if !query.Filtered() && c.Query("all") != "true" {
+			return httperror.New(c, http.StatusBadRequest, "At least one filter is required, or all=true to delete every result")
+		}
+
+		deleted, err := dbClient.DeleteTestResults(c, userClaims.UserID, query)
+		if err != nil {
+			return fmt.Errorf("Failed to delete test results: %w", err)
+		}
+
+		logging.FromContext(c).Infof("Deleted %d test results", deleted)
+		c.JSON(http.StatusOK, gin.H{
+			"deleted": deleted,
+		})
+		return nil
+	}
+}
+
+// historyQuery reads the page size, cursor, filters and sort order of a
+// history request from its query parameters
+func historyQuery(c *gin.Context) (db.ResultQuery, error) {
+	// Get limit from query parameter, where a missing or zero limit gets the
+	// default page size and larger ones are capped
+	query := db.ResultQuery{Cursor: c.Query("cursor")}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsed, err := fmt.Sscanf(limitStr, "%d", &query.Limit); err != nil || parsed != 1 {
+			return query, fmt.Errorf("Invalid limit parameter")
+		}
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultHistoryLimit
+	}
+	query.Limit = min(query.Limit, maxHistoryLimit)
+	if err := resultFilters(c, &query); err != nil {
+		return query, err
+	}
+
+	switch c.DefaultQuery("order", "desc") {
+	case "desc":
+	case "asc":
+		query.Ascending = true
+	default:
+		return query, fmt.Errorf("order must be 'asc' or 'desc'")
+	}
+	return query, nil
+}
+
+// resultFilters reads the status, base URL, suite, tag and creation time
+// filters shared by listing and deleting test results
+func resultFilters(c *gin.Context, query *db.ResultQuery) error {
+	for _, value := range listParameter(c, "status") {
+		status := exec.Status(strings.ToUpper(value))
+		if !slices.Contains(historyStatuses, status) {
+			return fmt.Errorf("Invalid status '%s'", value)
+		}
+		query.Statuses = append(query.Statuses, status)
+	}
+	query.BaseURL = c.Query("base_url")
+	query.SuiteID = c.Query("suite_id")
+	query.Tags = listParameter(c, "tag")
+
+	for parameter, bound := range map[string]**time.Time{"from": &query.CreatedFrom, "to": &query.CreatedTo} {
+		value := c.Query(parameter)
+		if value == "" {
+			continue
+		}
+		parsed, err := parseHistoryTime(value)
+		if err != nil {
+			return fmt.Errorf("Invalid %s parameter, expected an RFC 3339 timestamp or a YYYY-MM-DD date", parameter)
+		}
+		*bound = &parsed
+	}
+	if query.CreatedFrom != nil && query.CreatedTo != nil && !query.CreatedFrom.Before(*query.CreatedTo) {
+		return fmt.Errorf("from must be before to")
+	}
+	return nil
+}
+
+// listParameter collects a query parameter given several times or as a
+// comma separated list
+func listParameter(c *gin.Context, name string) []string {
+	var values []string
+	for _, value := range c.QueryArray(name) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+// parseHistoryTime accepts a full timestamp or a date, read as midnight UTC
+func parseHistoryTime(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
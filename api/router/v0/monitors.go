@@ -0,0 +1,181 @@
+package v0
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/auth"
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/httperror"
+	"github.com/jgfranco17/aeternum/api/scheduler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MonitorRequest represents the create monitor request body
+type MonitorRequest struct {
+	Name            string `json:"name" binding:"required"`
+	SuiteID         string `json:"suite_id" binding:"required"`
+	Schedule        string `json:"schedule,omitempty" binding:"required_without=IntervalSeconds"`
+	IntervalSeconds int    `json:"interval_seconds,omitempty" binding:"required_without=Schedule"`
+}
+
+func monitorNotFound(c *gin.Context, monitorID string) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"message": fmt.Sprintf("No monitor found for ID %s", monitorID),
+	})
+}
+
+func createMonitor(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		var req MonitorRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			return httperror.New(c, http.StatusBadRequest, "Invalid request body: %v", err)
+		}
+
+		monitor := db.Monitor{
+			Name:            req.Name,
+			SuiteID:         req.SuiteID,
+			Schedule:        req.Schedule,
+			IntervalSeconds: req.IntervalSeconds,
+		}
+		next, err := scheduler.NextRun(monitor, time.Now())
+		if err != nil {
+			return httperror.New(c, http.StatusBadRequest, "Invalid schedule: %v", err)
+		}
+		monitor.NextRunAt = next
+
+		_, err = dbClient.GetSuite(c, userClaims.UserID, req.SuiteID)
+		if errors.Is(err, db.ErrSuiteNotFound) {
+			suiteNotFound(c, req.SuiteID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to fetch suite: %w", err)
+		}
+
+		created, err := dbClient.CreateMonitor(c, userClaims.UserID, &monitor)
+		if err != nil {
+			return fmt.Errorf("Failed to create monitor: %w", err)
+		}
+
+		c.JSON(http.StatusCreated, created)
+		return nil
+	}
+}
+
+func listMonitors(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		monitors, err := dbClient.ListMonitors(c, userClaims.UserID)
+		if err != nil {
+			return fmt.Errorf("Failed to fetch monitors: %w", err)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"monitors": monitors,
+			"count":    len(monitors),
+		})
+		return nil
+	}
+}
+
+func getMonitor(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		monitorID := c.Param("id")
+		monitor, err := dbClient.GetMonitor(c, userClaims.UserID, monitorID)
+		if errors.Is(err, db.ErrMonitorNotFound) {
+			monitorNotFound(c, monitorID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to fetch monitor: %w", err)
+		}
+
+		c.JSON(http.StatusOK, monitor)
+		return nil
+	}
+}
+
+// Pausing stops the monitor from being picked up by the scheduler; resuming
+// schedules its next run from now, so runs missed while paused are skipped
+func setMonitorPaused(dbClient db.DatabaseClient, paused bool) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		monitorID := c.Param("id")
+		monitor, err := dbClient.GetMonitor(c, userClaims.UserID, monitorID)
+		if errors.Is(err, db.ErrMonitorNotFound) {
+			monitorNotFound(c, monitorID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to fetch monitor: %w", err)
+		}
+
+		if monitor.Paused != paused {
+			monitor.Paused = paused
+			if !paused {
+				next, err := scheduler.NextRun(*monitor, time.Now())
+				if err != nil {
+					return httperror.New(c, http.StatusBadRequest, "Invalid schedule: %v", err)
+				}
+				monitor.NextRunAt = next
+			}
+			monitor, err = dbClient.UpdateMonitor(c, userClaims.UserID, monitor)
+			if errors.Is(err, db.ErrMonitorNotFound) {
+				monitorNotFound(c, monitorID)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("Failed to update monitor: %w", err)
+			}
+		}
+
+		c.JSON(http.StatusOK, monitor)
+		return nil
+	}
+}
+
+func deleteMonitor(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		monitorID := c.Param("id")
+		err := dbClient.DeleteMonitor(c, userClaims.UserID, monitorID)
+		if errors.Is(err, db.ErrMonitorNotFound) {
+			monitorNotFound(c, monitorID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to delete monitor: %w", err)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": fmt.Sprintf("Deleted monitor %s", monitorID),
+		})
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package v0
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/logging"
+	exec "github.com/jgfranco17/aeternum/execution"
+)
+
+// progressWriter stores the partial results of a running test as they
+// arrive. Writes are coalesced, so a slow database never holds up the run.
+type progressWriter struct {
+	mu       sync.Mutex
+	snapshot exec.CheckResponse
+	dirty    chan struct{}
+	stopped  chan struct{}
+	done     chan struct{}
+}
+
+func newProgressWriter(ctx context.Context, dbClient db.DatabaseClient, userID string, running exec.CheckResponse) *progressWriter {
+	p := &progressWriter{
+		snapshot: running,
+		dirty:    make(chan struct{}, 1),
+		stopped:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	log := logging.FromContext(ctx)
+	go func() {
+		defer close(p.done)
+		for {
+			select {
+			case <-p.stopped:
+				return
+			case <-p.dirty:
+				p.mu.Lock()
+				snapshot := p.snapshot
+				snapshot.Results = append([]exec.CheckResult{}, p.snapshot.Results...)
+				snapshot.Scenarios = append([]exec.ScenarioResult{}, p.snapshot.Scenarios...)
+				p.mu.Unlock()
+				if err := dbClient.UpdateTestResult(ctx, userID, &snapshot); err != nil {
+					log.Errorf("Failed to store progress of test run %s: %v", snapshot.RequestID, err)
+				}
+			}
+		}
+	}()
+	return p
+}
+
+func (p *progressWriter) addResult(result exec.CheckResult) {
+	p.mu.Lock()
+	p.snapshot.Results = append(p.snapshot.Results, result)
+	p.mu.Unlock()
+	p.markDirty()
+}
+
+func (p *progressWriter) addScenario(scenario exec.ScenarioResult) {
+	p.mu.Lock()
+	p.snapshot.Scenarios = append(p.snapshot.Scenarios, scenario)
+	p.mu.Unlock()
+	p.markDirty()
+}
+
+func (p *progressWriter) markDirty() {
+	select {
+	case p.dirty <- struct{}{}:
+	default:
+	}
+}
+
+// stop waits for any write in flight, so that a final write made after
+// stop is never overwritten by partial results.
+func (p *progressWriter) stop() {
+	close(p.stopped)
+	<-p.done
+}
@@ -0,0 +1,126 @@
+package v0
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	exec "github.com/jgfranco17/aeternum/execution"
+
+	"github.com/jgfranco17/aeternum/api/auth"
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/httperror"
+	"github.com/jgfranco17/aeternum/api/reports"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultUptimeWindow is how far back uptime reports look without a from
+	defaultUptimeWindow = 30 * 24 * time.Hour
+	// maxUptimePeriods bounds the number of buckets a report is split into
+	maxUptimePeriods = 400
+)
+
+// resultMediaTypes are the media types a result can be requested as in the
+// Accept header, in order of preference for wildcards
+var resultMediaTypes = []string{gin.MIMEJSON, gin.MIMEXML, gin.MIMEXML2, "text/markdown", "text/x-tap"}
+
+// resultMediaFormats maps each of the media types onto its format
+var resultMediaFormats = map[string]reports.Format{
+	gin.MIMEJSON:    reports.FormatJSON,
+	gin.MIMEXML:     reports.FormatJUnit,
+	gin.MIMEXML2:    reports.FormatJUnit,
+	"text/markdown": reports.FormatMarkdown,
+	"text/x-tap":    reports.FormatTAP,
+}
+
+// resultContentTypes are the content types results are exported with
+var resultContentTypes = map[reports.Format]string{
+	reports.FormatJUnit:    "application/xml; charset=utf-8",
+	reports.FormatTAP:      "text/plain; charset=utf-8",
+	reports.FormatMarkdown: "text/markdown; charset=utf-8",
+}
+
+// uptimeStatuses are the run statuses that tell whether a target was up
+var uptimeStatuses = []exec.Status{exec.StatusPass, exec.StatusFail, exec.StatusError}
+
+// resultFormat picks the rendering of a result from the format parameter,
+// then the Accept header, and otherwise falls back to JSON. It reports false
+// for an unknown format parameter.
+func resultFormat(c *gin.Context) (reports.Format, bool) {
+	if format := reports.Format(c.Query("format")); format != "" {
+		return format, slices.Contains(reports.Formats, format)
+	}
+	if format, ok := resultMediaFormats[c.NegotiateFormat(resultMediaTypes...)]; ok {
+		return format, true
+	}
+	return reports.FormatJSON, true
+}
+
+// writeResult responds with a result exported as JUnit XML, TAP or Markdown
+func writeResult(c *gin.Context, result db.TestResult, format reports.Format) error {
+	var body bytes.Buffer
+	if err := reports.WriteResult(&body, result, format); err != nil {
+		return fmt.Errorf("Failed to render test result: %w", err)
+	}
+	c.Data(http.StatusOK, resultContentTypes[format], body.Bytes())
+	return nil
+}
+
+// Report the availability and incidents of each base URL and endpoint the
+// user checked, split into day, week or month buckets
+func getUptimeReport(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		var query db.ResultQuery
+		if err := resultFilters(c, &query); err != nil {
+			return httperror.New(c, http.StatusBadRequest, err.Error())
+		}
+		defaultWindow(&query, defaultUptimeWindow)
+		query.Statuses = uptimeStatuses
+		query.Ascending = true
+
+		window := reports.UptimeWindow{
+			From:   *query.CreatedFrom,
+			To:     *query.CreatedTo,
+			Bucket: reports.Bucket(c.DefaultQuery("bucket", string(reports.BucketDay))),
+		}
+		if !slices.Contains(reports.Buckets, window.Bucket) {
+			return httperror.New(c, http.StatusBadRequest, "bucket must be 'day', 'week' or 'month'")
+		}
+		if window.Periods() > maxUptimePeriods {
+			return httperror.New(c, http.StatusBadRequest,
+				fmt.Sprintf("The window spans more than %d buckets, use a larger bucket or a shorter window", maxUptimePeriods))
+		}
+		format := c.DefaultQuery("format", "json")
+		if format != "json" && format != "csv" {
+			return httperror.New(c, http.StatusBadRequest, "format must be 'json' or 'csv'")
+		}
+
+		results, err := db.AllTestResults(c, dbClient, userClaims.UserID, query)
+		if err != nil {
+			return fmt.Errorf("Failed to fetch test results: %w", err)
+		}
+		report := reports.NewUptimeReport(results, window, time.Now())
+
+		if format == "csv" {
+			var body bytes.Buffer
+			if err := report.WriteCSV(&body); err != nil {
+				return fmt.Errorf("Failed to render uptime report: %w", err)
+			}
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="uptime-%s-%s.csv"`,
+				window.From.Format(time.DateOnly), window.To.Format(time.DateOnly)))
+			c.Data(http.StatusOK, "text/csv; charset=utf-8", body.Bytes())
+			return nil
+		}
+		c.JSON(http.StatusOK, report)
+		return nil
+	}
+}
@@ -3,21 +3,73 @@ package v0
 import (
 	"github.com/jgfranco17/aeternum/api/auth"
 	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/notify"
 
 	"github.com/gin-gonic/gin"
 )
 
-// Adds v0 routes to the router.
-func SetRoutes(route *gin.Engine, dbClient db.DatabaseClient) error {
+// Adds v0 routes to the router. Runs are notified through the given
+// dispatcher, which the monitor scheduler shares.
+func SetRoutes(route *gin.Engine, dbClient db.DatabaseClient, notifier *notify.Dispatcher) error {
+	runs := newAsyncRuns(notifier)
+
 	v0 := route.Group("/v0")
 	// Apply authentication middleware to all v0 routes
 	v0.Use(auth.AuthMiddleware())
 	{
 		testExecutionRoutes := v0.Group("/tests")
 		{
-			testExecutionRoutes.POST("/run", WithErrorHandling(runTests(dbClient)))
+			testExecutionRoutes.POST("/run", WithErrorHandling(runTests(dbClient, runs, notifier)))
+			testExecutionRoutes.GET("/run/:id/stream", WithErrorHandling(streamTestRun(dbClient, runs.events)))
+			testExecutionRoutes.POST("/runs/:id/cancel", WithErrorHandling(cancelTestRun(dbClient, runs)))
 			testExecutionRoutes.GET("/results", WithErrorHandling(getTestResultsById(dbClient)))
+			testExecutionRoutes.DELETE("/results", WithErrorHandling(deleteTestResults(dbClient)))
+			testExecutionRoutes.DELETE("/results/:id", WithErrorHandling(deleteTestResult(dbClient, runs)))
 			testExecutionRoutes.GET("/history", WithErrorHandling(getUserTestResults(dbClient)))
+			testExecutionRoutes.GET("/compare", WithErrorHandling(compareTestResults(dbClient)))
+		}
+		analyticsRoutes := v0.Group("/analytics")
+		{
+			analyticsRoutes.GET("/endpoints", WithErrorHandling(getEndpointHealth(dbClient)))
+		}
+		reportRoutes := v0.Group("/reports")
+		{
+			reportRoutes.GET("/uptime", WithErrorHandling(getUptimeReport(dbClient)))
+		}
+		suiteRoutes := v0.Group("/suites")
+		{
+			suiteRoutes.POST("", WithErrorHandling(createSuite(dbClient)))
+			suiteRoutes.GET("", WithErrorHandling(listSuites(dbClient)))
+			suiteRoutes.GET("/:id", WithErrorHandling(getSuite(dbClient)))
+			suiteRoutes.PUT("/:id", WithErrorHandling(updateSuite(dbClient)))
+			suiteRoutes.DELETE("/:id", WithErrorHandling(deleteSuite(dbClient)))
+			suiteRoutes.POST("/:id/run", WithErrorHandling(runSuite(dbClient, notifier)))
+			suiteRoutes.GET("/:id/history", WithErrorHandling(getSuiteTestResults(dbClient)))
+		}
+		monitorRoutes := v0.Group("/monitors")
+		{
+			monitorRoutes.POST("", WithErrorHandling(createMonitor(dbClient)))
+			monitorRoutes.GET("", WithErrorHandling(listMonitors(dbClient)))
+			monitorRoutes.GET("/:id", WithErrorHandling(getMonitor(dbClient)))
+			monitorRoutes.POST("/:id/pause", WithErrorHandling(setMonitorPaused(dbClient, true)))
+			monitorRoutes.POST("/:id/resume", WithErrorHandling(setMonitorPaused(dbClient, false)))
+			monitorRoutes.DELETE("/:id", WithErrorHandling(deleteMonitor(dbClient)))
+		}
+		webhookRoutes := v0.Group("/webhooks")
+		{
+			webhookRoutes.POST("", WithErrorHandling(createWebhook(dbClient)))
+			webhookRoutes.GET("", WithErrorHandling(listWebhooks(dbClient)))
+			webhookRoutes.GET("/:id", WithErrorHandling(getWebhook(dbClient)))
+			webhookRoutes.PUT("/:id", WithErrorHandling(updateWebhook(dbClient)))
+			webhookRoutes.DELETE("/:id", WithErrorHandling(deleteWebhook(dbClient)))
+			webhookRoutes.GET("/:id/deliveries", WithErrorHandling(listWebhookDeliveries(dbClient)))
+		}
+		chatRoutes := v0.Group("/chat-destinations")
+		{
+			chatRoutes.POST("", WithErrorHandling(createChatDestination(dbClient)))
+			chatRoutes.GET("", WithErrorHandling(listChatDestinations(dbClient)))
+			chatRoutes.DELETE("/:id", WithErrorHandling(deleteChatDestination(dbClient)))
+			chatRoutes.POST("/:id/test", WithErrorHandling(testChatDestination(dbClient)))
 		}
 	}
 	return nil
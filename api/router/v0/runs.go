@@ -0,0 +1,158 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jgfranco17/aeternum/api/auth"
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/httperror"
+	"github.com/jgfranco17/aeternum/api/jobs"
+	"github.com/jgfranco17/aeternum/api/logging"
+	"github.com/jgfranco17/aeternum/api/notify"
+	"github.com/jgfranco17/aeternum/api/stream"
+	exec "github.com/jgfranco17/aeternum/execution"
+
+	"github.com/gin-gonic/gin"
+)
+
+// asyncRuns holds what this server needs to execute runs in the background:
+// the worker queue, the event broker streams are served from, the active
+// runs that may be cancelled, and where finished runs are reported.
+type asyncRuns struct {
+	queue    *jobs.Queue
+	events   *stream.Broker
+	active   *jobs.Runs
+	notifier *notify.Dispatcher
+}
+
+func newAsyncRuns(notifier *notify.Dispatcher) *asyncRuns {
+	queue := jobs.NewQueueFromEnvironment()
+	queue.Start(context.Background())
+	return &asyncRuns{
+		queue:    queue,
+		events:   stream.NewBroker(),
+		active:   jobs.NewRuns(),
+		notifier: notifier,
+	}
+}
+
+// Store the run as pending and execute it on the queue, so the client can
+// poll for the result instead of waiting on the request
+func (a *asyncRuns) enqueue(c *gin.Context, dbClient db.DatabaseClient, userID string, req exec.TestExecutionRequest) error {
+	log := logging.FromContext(c)
+	pending := &exec.CheckResponse{
+		RequestID: exec.NewRequestID(),
+		BaseURL:   req.BaseURL,
+		Status:    exec.StatusPending,
+		Results:   []exec.CheckResult{},
+		Tags:      req.Tags,
+	}
+	if err := dbClient.StoreTestResult(c, userID, pending); err != nil {
+		return fmt.Errorf("Failed to store pending test run: %w", err)
+	}
+
+	runID := pending.RequestID
+	a.events.Open(runID, userID)
+	cancelled := a.active.Register(runID, userID)
+	err := a.queue.Submit(func(ctx context.Context) {
+		defer a.events.Close(runID)
+		defer a.active.Release(runID)
+
+		// Results are still stored after a cancellation, so only the
+		// execution itself is bound to the cancellable context
+		runCtx, stop := context.WithCancel(ctx)
+		defer stop()
+		context.AfterFunc(cancelled, stop)
+
+		running := *pending
+		running.Status = exec.StatusRunning
+		if err := dbClient.UpdateTestResult(ctx, userID, &running); err != nil {
+			log.Errorf("Failed to mark test run %s as running: %v", runID, err)
+		}
+
+		progress := newProgressWriter(ctx, dbClient, userID, running)
+		response, err := exec.ExecuteTestsWithOptions(runCtx, req, exec.RunOptions{
+			RequestID: runID,
+			OnResult: func(result exec.CheckResult) {
+				a.events.Publish(runID, stream.Event{Type: stream.EventResult, Data: result})
+				progress.addResult(result)
+			},
+			OnScenario: func(scenario exec.ScenarioResult) {
+				a.events.Publish(runID, stream.Event{Type: stream.EventScenario, Data: scenario})
+				progress.addScenario(scenario)
+			},
+		})
+		progress.stop()
+		if err != nil {
+			log.Errorf("Failed to execute test run %s: %v", runID, err)
+			response = &running
+			response.Status = exec.StatusError
+		}
+		a.events.Publish(runID, stream.Event{Type: stream.EventSummary, Data: response})
+		if err := dbClient.UpdateTestResult(ctx, userID, response); err != nil {
+			log.Errorf("Failed to store test result: %v", err)
+		}
+		a.notifier.RunCompleted(ctx, userID, response)
+	})
+	if errors.Is(err, jobs.ErrQueueFull) {
+		a.events.Close(runID)
+		a.active.Release(runID)
+		rejected := *pending
+		rejected.Status = exec.StatusError
+		if err := dbClient.UpdateTestResult(c, userID, &rejected); err != nil {
+			log.Errorf("Failed to mark test run %s as rejected: %v", runID, err)
+		}
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"message": "Too many test runs are queued, try again later",
+		})
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to queue test run: %w", err)
+	}
+
+	log.Infof("Queued test run %s", runID)
+	c.Header("Location", fmt.Sprintf("/v0/tests/results?id=%s", runID))
+	c.JSON(http.StatusAccepted, pending)
+	return nil
+}
+
+func cancelTestRun(dbClient db.DatabaseClient, runs *asyncRuns) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		runID := c.Param("id")
+		if runs.active.Cancel(runID, userClaims.UserID) {
+			logging.FromContext(c).Infof("Cancelling test run %s", runID)
+			c.JSON(http.StatusAccepted, gin.H{
+				"message": fmt.Sprintf("Cancelling run %s", runID),
+			})
+			return nil
+		}
+
+		// The run is not active here: it has finished, is executing on
+		// another server instance, or does not exist
+		result, err := dbClient.GetTestResult(c, userClaims.UserID, runID)
+		if errors.Is(err, db.ErrTestResultNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"message": fmt.Sprintf("No run found for ID %s", runID),
+			})
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to fetch test result: %w", err)
+		}
+		message := fmt.Sprintf("Run %s has already finished with status %s", runID, result.Status)
+		if result.Status == exec.StatusPending || result.Status == exec.StatusRunning {
+			message = fmt.Sprintf("Run %s is not executing on this server", runID)
+		}
+		c.JSON(http.StatusConflict, gin.H{"message": message})
+		return nil
+	}
+}
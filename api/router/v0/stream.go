@@ -0,0 +1,114 @@
+package v0
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/jgfranco17/aeternum/api/auth"
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/httperror"
+	"github.com/jgfranco17/aeternum/api/stream"
+	exec "github.com/jgfranco17/aeternum/execution"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Stream the results of an asynchronous run as server-sent events. Runs
+// executing on this server are streamed live; any other run is replayed
+// from storage.
+func streamTestRun(dbClient db.DatabaseClient, broker *stream.Broker) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		runID := c.Param("id")
+		history, live, cancel, ok := broker.Subscribe(runID, userClaims.UserID)
+		defer cancel()
+		if !ok {
+			result, err := dbClient.GetTestResult(c, userClaims.UserID, runID)
+			if errors.Is(err, db.ErrTestResultNotFound) {
+				resultNotFound(c, runID)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("Failed to fetch test result: %w", err)
+			}
+			history = storedEvents(result)
+		}
+
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		sent := 0
+		finished := false
+		send := func(event stream.Event) {
+			c.SSEvent(string(event.Type), event.Data)
+			finished = finished || event.Type == stream.EventSummary
+		}
+		c.Stream(func(w io.Writer) bool {
+			if sent < len(history) {
+				send(history[sent])
+				sent++
+				return true
+			}
+			if live == nil {
+				endStream(c, runID, finished)
+				return false
+			}
+			select {
+			case <-c.Request.Context().Done():
+				return false
+			case event, open := <-live:
+				if !open {
+					endStream(c, runID, finished)
+					return false
+				}
+				send(event)
+				return true
+			}
+		})
+		return nil
+	}
+}
+
+// endStream tells the client when the stream stopped before the summary of
+// the run, because it read too slowly or the run never completed, so that it
+// is not mistaken for a finished run
+func endStream(c *gin.Context, runID string, finished bool) {
+	if finished {
+		return
+	}
+	c.SSEvent(string(stream.EventError), gin.H{
+		"message":    "The stream ended before the run finished",
+		"result_url": fmt.Sprintf("/v0/tests/results?id=%s", url.QueryEscape(runID)),
+	})
+}
+
+// storedEvents replays a stored result. Only a finished run gets a summary;
+// a run still pending or running elsewhere ends with the error event instead.
+func storedEvents(result *db.TestResult) []stream.Event {
+	events := []stream.Event{}
+	for _, check := range result.Results {
+		events = append(events, stream.Event{Type: stream.EventResult, Data: check})
+	}
+	for _, scenario := range result.Scenarios {
+		events = append(events, stream.Event{Type: stream.EventScenario, Data: scenario})
+	}
+	if result.Status == exec.StatusPending || result.Status == exec.StatusRunning {
+		return events
+	}
+	return append(events, stream.Event{Type: stream.EventSummary, Data: exec.CheckResponse{
+		RequestID: result.RequestID,
+		SuiteID:   result.SuiteID,
+		BaseURL:   result.BaseURL,
+		Status:    result.Status,
+		Results:   result.Results,
+		Scenarios: result.Scenarios,
+		Tags:      result.Tags,
+	}})
+}
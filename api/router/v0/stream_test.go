@@ -0,0 +1,107 @@
+package v0
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jgfranco17/aeternum/api/auth"
+	"github.com/jgfranco17/aeternum/api/stream"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// stalledWriter holds back the first write of a response until released,
+// to play a client that stops reading
+type stalledWriter struct {
+	*httptest.ResponseRecorder
+	once     sync.Once
+	writing  chan struct{}
+	released chan struct{}
+}
+
+func (w *stalledWriter) Write(data []byte) (int, error) {
+	w.stall()
+	return w.ResponseRecorder.Write(data)
+}
+
+func (w *stalledWriter) WriteString(data string) (int, error) {
+	w.stall()
+	return w.ResponseRecorder.WriteString(data)
+}
+
+func (w *stalledWriter) stall() {
+	w.once.Do(func() {
+		close(w.writing)
+		<-w.released
+	})
+}
+
+func (w *stalledWriter) CloseNotify() <-chan bool {
+	return nil
+}
+
+func TestStreamTestRunEndsSlowSubscribersWithError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	broker := stream.NewBroker()
+	broker.Open("run-1", "user")
+	broker.Publish("run-1", stream.Event{Type: stream.EventResult, Data: 0})
+
+	router := gin.New()
+	router.GET("/stream/:id", func(c *gin.Context) {
+		c.Set(auth.UserClaimsKey, &auth.Claims{UserID: "user"})
+	}, WithErrorHandling(streamTestRun(nil, broker)))
+
+	writer := &stalledWriter{
+		ResponseRecorder: httptest.NewRecorder(),
+		writing:          make(chan struct{}),
+		released:         make(chan struct{}),
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		router.ServeHTTP(writer, httptest.NewRequest("GET", "/stream/run-1", nil))
+	}()
+
+	// The handler has subscribed once it writes the first event, and falls
+	// behind while that write is held back
+	<-writer.writing
+	for i := 1; i <= 100; i++ {
+		broker.Publish("run-1", stream.Event{Type: stream.EventResult, Data: i})
+	}
+	broker.Publish("run-1", stream.Event{Type: stream.EventSummary, Data: "done"})
+	close(writer.released)
+	<-done
+
+	body := writer.Body.String()
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.NotContains(t, body, "event:summary")
+	assert.True(t, strings.HasSuffix(body, "event:error\ndata:{\"message\":\"The stream ended before the run finished\",\"result_url\":\"/v0/tests/results?id=run-1\"}\n\n"))
+}
+
+func TestStreamTestRunEndsFinishedRunsWithSummary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	broker := stream.NewBroker()
+	broker.Open("run-1", "user")
+	broker.Publish("run-1", stream.Event{Type: stream.EventResult, Data: 1})
+	broker.Publish("run-1", stream.Event{Type: stream.EventSummary, Data: "done"})
+	broker.Close("run-1")
+
+	router := gin.New()
+	router.GET("/stream/:id", func(c *gin.Context) {
+		c.Set(auth.UserClaimsKey, &auth.Claims{UserID: "user"})
+	}, WithErrorHandling(streamTestRun(nil, broker)))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/stream/run-1")
+	assert.NoError(t, err)
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "event:result\ndata:1\n\nevent:summary\ndata:done\n\n", string(body))
+}
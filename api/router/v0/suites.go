@@ -0,0 +1,240 @@
+package v0
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jgfranco17/aeternum/api/auth"
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/httperror"
+	"github.com/jgfranco17/aeternum/api/logging"
+	"github.com/jgfranco17/aeternum/api/notify"
+	exec "github.com/jgfranco17/aeternum/execution"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SuiteRequest represents the create and update suite request body
+type SuiteRequest struct {
+	Name        string                    `json:"name" binding:"required"`
+	Description string                    `json:"description,omitempty"`
+	Request     exec.TestExecutionRequest `json:"request" binding:"required"`
+}
+
+// redactedSecretMessage rejects a masked credential that has no stored
+// credential of the same auth type at the same place in the suite
+const redactedSecretMessage = "Credentials set to [REDACTED] must match a stored credential of the same auth type, send the real value instead"
+
+// Returns the suite with credentials masked, for use in responses
+func redactSuite(suite db.Suite) db.Suite {
+	suite.Request = suite.Request.Redacted()
+	return suite
+}
+
+func suiteNotFound(c *gin.Context, suiteID string) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"message": fmt.Sprintf("No suite found for ID %s", suiteID),
+	})
+}
+
+func createSuite(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		var req SuiteRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			return httperror.New(c, http.StatusBadRequest, "Invalid request body: %v", err)
+		}
+
+		if _, err := req.Request.WithStoredSecrets(exec.TestExecutionRequest{}); errors.Is(err, exec.ErrRedactedSecret) {
+			return httperror.New(c, http.StatusBadRequest, redactedSecretMessage)
+		}
+
+		suite, err := dbClient.CreateSuite(c, userClaims.UserID, &db.Suite{
+			Name:        req.Name,
+			Description: req.Description,
+			Request:     req.Request,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to create suite: %w", err)
+		}
+
+		c.JSON(http.StatusCreated, redactSuite(*suite))
+		return nil
+	}
+}
+
+func listSuites(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		suites, err := dbClient.ListSuites(c, userClaims.UserID)
+		if err != nil {
+			return fmt.Errorf("Failed to fetch suites: %w", err)
+		}
+		for i := range suites {
+			suites[i] = redactSuite(suites[i])
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"suites": suites,
+			"count":  len(suites),
+		})
+		return nil
+	}
+}
+
+func getSuite(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		suiteID := c.Param("id")
+		suite, err := dbClient.GetSuite(c, userClaims.UserID, suiteID)
+		if errors.Is(err, db.ErrSuiteNotFound) {
+			suiteNotFound(c, suiteID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to fetch suite: %w", err)
+		}
+
+		c.JSON(http.StatusOK, redactSuite(*suite))
+		return nil
+	}
+}
+
+func updateSuite(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		var req SuiteRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			return httperror.New(c, http.StatusBadRequest, "Invalid request body: %v", err)
+		}
+
+		suiteID := c.Param("id")
+		existing, err := dbClient.GetSuite(c, userClaims.UserID, suiteID)
+		if errors.Is(err, db.ErrSuiteNotFound) {
+			suiteNotFound(c, suiteID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to fetch suite: %w", err)
+		}
+		// Suites are returned with their credentials masked, so a request
+		// read back from this API keeps the credentials already stored
+		request, err := req.Request.WithStoredSecrets(existing.Request)
+		if errors.Is(err, exec.ErrRedactedSecret) {
+			return httperror.New(c, http.StatusBadRequest, redactedSecretMessage)
+		}
+		suite, err := dbClient.UpdateSuite(c, userClaims.UserID, &db.Suite{
+			ID:          suiteID,
+			Name:        req.Name,
+			Description: req.Description,
+			Request:     request,
+		})
+		if errors.Is(err, db.ErrSuiteNotFound) {
+			suiteNotFound(c, suiteID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to update suite: %w", err)
+		}
+
+		c.JSON(http.StatusOK, redactSuite(*suite))
+		return nil
+	}
+}
+
+func deleteSuite(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		suiteID := c.Param("id")
+		err := dbClient.DeleteSuite(c, userClaims.UserID, suiteID)
+		if errors.Is(err, db.ErrSuiteNotFound) {
+			suiteNotFound(c, suiteID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to delete suite: %w", err)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": fmt.Sprintf("Deleted suite %s", suiteID),
+		})
+		return nil
+	}
+}
+
+func runSuite(dbClient db.DatabaseClient, notifier *notify.Dispatcher) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		suiteID := c.Param("id")
+		suite, err := dbClient.GetSuite(c, userClaims.UserID, suiteID)
+		if errors.Is(err, db.ErrSuiteNotFound) {
+			suiteNotFound(c, suiteID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to fetch suite: %w", err)
+		}
+
+		log := logging.FromContext(c)
+		log.Infof("Running suite %s (%s)", suite.ID, suite.Name)
+		response, err := executeAndStore(c, dbClient, notifier, userClaims.UserID, suite.Request, suite.ID)
+		if err != nil {
+			return err
+		}
+
+		c.JSON(http.StatusOK, response)
+		return nil
+	}
+}
+
+func getSuiteTestResults(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		limit := 10
+		if limitStr := c.Query("limit"); limitStr != "" {
+			if parsed, err := fmt.Sscanf(limitStr, "%d", &limit); err != nil || parsed != 1 {
+				return httperror.New(c, http.StatusBadRequest, "Invalid limit parameter")
+			}
+		}
+
+		results, err := dbClient.GetSuiteTestResults(c, userClaims.UserID, c.Param("id"), limit)
+		if err != nil {
+			return fmt.Errorf("Failed to fetch suite test results: %w", err)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"results": results,
+			"count":   len(results),
+		})
+		return nil
+	}
+}
@@ -0,0 +1,226 @@
+package v0
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jgfranco17/aeternum/api/auth"
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/httperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookRequest represents the create and update webhook request body
+type WebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url"`
+	Events []string `json:"events,omitempty" binding:"omitempty,dive,oneof=run.completed suite.status_changed"`
+	Secret string   `json:"secret,omitempty" binding:"omitempty,min=16"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+func (r WebhookRequest) toWebhook(id string) db.Webhook {
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+	return db.Webhook{
+		ID:     id,
+		URL:    r.URL,
+		Events: r.Events,
+		Secret: r.Secret,
+		Active: active,
+	}
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
+
+// Secrets are only shown when they are set, never when a webhook is read back
+func withoutSecret(webhook db.Webhook) db.Webhook {
+	webhook.Secret = ""
+	return webhook
+}
+
+func webhookNotFound(c *gin.Context, webhookID string) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"message": fmt.Sprintf("No webhook found for ID %s", webhookID),
+	})
+}
+
+func createWebhook(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		var req WebhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			return httperror.New(c, http.StatusBadRequest, "Invalid request body: %v", err)
+		}
+
+		webhook := req.toWebhook("")
+		if webhook.Secret == "" {
+			secret, err := generateWebhookSecret()
+			if err != nil {
+				return fmt.Errorf("Failed to generate webhook secret: %w", err)
+			}
+			webhook.Secret = secret
+		}
+
+		created, err := dbClient.CreateWebhook(c, userClaims.UserID, &webhook)
+		if err != nil {
+			return fmt.Errorf("Failed to create webhook: %w", err)
+		}
+
+		c.JSON(http.StatusCreated, created)
+		return nil
+	}
+}
+
+func listWebhooks(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		webhooks, err := dbClient.ListWebhooks(c, userClaims.UserID)
+		if err != nil {
+			return fmt.Errorf("Failed to fetch webhooks: %w", err)
+		}
+		for i := range webhooks {
+			webhooks[i] = withoutSecret(webhooks[i])
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"webhooks": webhooks,
+			"count":    len(webhooks),
+		})
+		return nil
+	}
+}
+
+func getWebhook(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		webhookID := c.Param("id")
+		webhook, err := dbClient.GetWebhook(c, userClaims.UserID, webhookID)
+		if errors.Is(err, db.ErrWebhookNotFound) {
+			webhookNotFound(c, webhookID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to fetch webhook: %w", err)
+		}
+
+		c.JSON(http.StatusOK, withoutSecret(*webhook))
+		return nil
+	}
+}
+
+func updateWebhook(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		var req WebhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			return httperror.New(c, http.StatusBadRequest, "Invalid request body: %v", err)
+		}
+
+		webhookID := c.Param("id")
+		webhook := req.toWebhook(webhookID)
+		updated, err := dbClient.UpdateWebhook(c, userClaims.UserID, &webhook)
+		if errors.Is(err, db.ErrWebhookNotFound) {
+			webhookNotFound(c, webhookID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to update webhook: %w", err)
+		}
+
+		response := withoutSecret(*updated)
+		if req.Secret != "" {
+			response.Secret = req.Secret
+		}
+		c.JSON(http.StatusOK, response)
+		return nil
+	}
+}
+
+func deleteWebhook(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		webhookID := c.Param("id")
+		err := dbClient.DeleteWebhook(c, userClaims.UserID, webhookID)
+		if errors.Is(err, db.ErrWebhookNotFound) {
+			webhookNotFound(c, webhookID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to delete webhook: %w", err)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": fmt.Sprintf("Deleted webhook %s", webhookID),
+		})
+		return nil
+	}
+}
+
+func listWebhookDeliveries(dbClient db.DatabaseClient) func(c *gin.Context) error {
+	return func(c *gin.Context) error {
+		userClaims, exists := auth.GetUserClaims(c)
+		if !exists {
+			return httperror.New(c, http.StatusBadRequest, "user claims not found in request context")
+		}
+
+		limit := 50
+		if limitStr := c.Query("limit"); limitStr != "" {
+			if parsed, err := fmt.Sscanf(limitStr, "%d", &limit); err != nil || parsed != 1 {
+				return httperror.New(c, http.StatusBadRequest, "Invalid limit parameter")
+			}
+		}
+
+		webhookID := c.Param("id")
+		_, err := dbClient.GetWebhook(c, userClaims.UserID, webhookID)
+		if errors.Is(err, db.ErrWebhookNotFound) {
+			webhookNotFound(c, webhookID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to fetch webhook: %w", err)
+		}
+
+		deliveries, err := dbClient.ListWebhookDeliveries(c, userClaims.UserID, webhookID, limit)
+		if err != nil {
+			return fmt.Errorf("Failed to fetch webhook deliveries: %w", err)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"deliveries": deliveries,
+			"count":      len(deliveries),
+		})
+		return nil
+	}
+}
@@ -0,0 +1,150 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/environment"
+	"github.com/jgfranco17/aeternum/api/logging"
+	"github.com/jgfranco17/aeternum/api/notify"
+	exec "github.com/jgfranco17/aeternum/execution"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	defaultPollSeconds = 15
+	minIntervalSeconds = 60
+)
+
+// NextRun returns the first time after the given one at which the monitor
+// is due, using its cron schedule or its fixed interval.
+func NextRun(monitor db.Monitor, after time.Time) (time.Time, error) {
+	if monitor.Schedule != "" && monitor.IntervalSeconds != 0 {
+		return time.Time{}, fmt.Errorf("only one of schedule and interval_seconds may be set")
+	}
+	if monitor.Schedule != "" {
+		schedule, err := cron.ParseStandard(monitor.Schedule)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid cron schedule '%s': %w", monitor.Schedule, err)
+		}
+		return schedule.Next(after).UTC(), nil
+	}
+	if monitor.IntervalSeconds < minIntervalSeconds {
+		return time.Time{}, fmt.Errorf("interval_seconds must be at least %d", minIntervalSeconds)
+	}
+	interval := time.Duration(monitor.IntervalSeconds) * time.Second
+	return after.Add(interval).UTC().Truncate(time.Second), nil
+}
+
+// Scheduler runs due monitors. Schedules live in the database, so every
+// replica polls the same state and a restarted server picks up where it
+// left off; a run is only executed by the replica that claims it.
+type Scheduler struct {
+	dbClient     db.DatabaseClient
+	pollInterval time.Duration
+	now          func() time.Time
+	execute      func(ctx context.Context, req exec.TestExecutionRequest) (*exec.CheckResponse, error)
+	notifier     *notify.Dispatcher
+	wg           sync.WaitGroup
+}
+
+// New creates a scheduler that reports runs through the given dispatcher,
+// the one the API routes use so alerts are deduplicated across both.
+func New(dbClient db.DatabaseClient, notifier *notify.Dispatcher) *Scheduler {
+	pollSeconds := environment.GetIntEnvWithDefault(environment.ENV_KEY_SCHEDULER_POLL_SECONDS, defaultPollSeconds)
+	if pollSeconds <= 0 {
+		pollSeconds = defaultPollSeconds
+	}
+	return &Scheduler{
+		dbClient:     dbClient,
+		pollInterval: time.Duration(pollSeconds) * time.Second,
+		now:          time.Now,
+		execute:      exec.ExecuteTests,
+		notifier:     notifier,
+	}
+}
+
+// Start polls for due monitors in the background until the context is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	log := logging.FromContext(ctx)
+	log.Infof("Starting monitor scheduler, polling every %s", s.pollInterval)
+	go func() {
+		ticker := time.NewTicker(s.pollInterval)
+		defer ticker.Stop()
+		for {
+			s.Tick(ctx)
+			select {
+			case <-ctx.Done():
+				s.wg.Wait()
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Tick claims every monitor that is due and runs it in the background.
+func (s *Scheduler) Tick(ctx context.Context) {
+	log := logging.FromContext(ctx)
+	now := s.now()
+	monitors, err := s.dbClient.ListDueMonitors(ctx, now)
+	if err != nil {
+		log.Errorf("Failed to list due monitors: %v", err)
+		return
+	}
+	for _, monitor := range monitors {
+		next, err := NextRun(monitor, now)
+		if err != nil {
+			log.Errorf("Monitor %s has an invalid schedule: %v", monitor.ID, err)
+			continue
+		}
+		claimed, err := s.dbClient.ClaimMonitorRun(ctx, &monitor, next)
+		if err != nil {
+			log.Errorf("Failed to claim run of monitor %s: %v", monitor.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		s.wg.Add(1)
+		go func(monitor db.Monitor) {
+			defer s.wg.Done()
+			s.run(ctx, monitor)
+		}(monitor)
+	}
+}
+
+// Wait blocks until every run started by Tick has finished.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(ctx context.Context, monitor db.Monitor) {
+	log := logging.FromContext(ctx)
+	suite, err := s.dbClient.GetSuite(ctx, monitor.UserID, monitor.SuiteID)
+	if errors.Is(err, db.ErrSuiteNotFound) {
+		log.Warnf("Monitor %s refers to missing suite %s", monitor.ID, monitor.SuiteID)
+		return
+	}
+	if err != nil {
+		log.Errorf("Failed to fetch suite for monitor %s: %v", monitor.ID, err)
+		return
+	}
+
+	log.Infof("Running monitor %s (%s) for suite %s", monitor.ID, monitor.Name, suite.ID)
+	response, err := s.execute(ctx, suite.Request)
+	if err != nil {
+		log.Errorf("Monitor %s failed to execute: %v", monitor.ID, err)
+		return
+	}
+	response.SuiteID = suite.ID
+	if err := s.dbClient.StoreTestResult(ctx, monitor.UserID, response); err != nil {
+		log.Errorf("Failed to store result of monitor %s: %v", monitor.ID, err)
+	}
+	s.notifier.RunCompleted(ctx, monitor.UserID, response)
+}
@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jgfranco17/aeternum/api/db"
+	"github.com/jgfranco17/aeternum/api/notify"
+	exec "github.com/jgfranco17/aeternum/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDB keeps monitors in memory; only the methods used by the scheduler
+// are implemented.
+type fakeDB struct {
+	db.DatabaseClient
+	mu       sync.Mutex
+	monitors map[string]*db.Monitor
+	stored   []*exec.CheckResponse
+}
+
+func (f *fakeDB) ListDueMonitors(ctx context.Context, before time.Time) ([]db.Monitor, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	due := []db.Monitor{}
+	for _, monitor := range f.monitors {
+		if !monitor.Paused && !monitor.NextRunAt.After(before) {
+			due = append(due, *monitor)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeDB) ClaimMonitorRun(ctx context.Context, monitor *db.Monitor, nextRunAt time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored := f.monitors[monitor.ID]
+	if !stored.NextRunAt.Equal(monitor.NextRunAt) {
+		return false, nil
+	}
+	stored.NextRunAt = nextRunAt
+	return true, nil
+}
+
+func (f *fakeDB) GetSuite(ctx context.Context, userID, suiteID string) (*db.Suite, error) {
+	return &db.Suite{ID: suiteID, UserID: userID, Request: exec.TestExecutionRequest{BaseURL: "http://target"}}, nil
+}
+
+func (f *fakeDB) StoreTestResult(ctx context.Context, userID string, result *exec.CheckResponse) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stored = append(f.stored, result)
+	return nil
+}
+
+func (f *fakeDB) SetSuiteStatus(ctx context.Context, userID, suiteID string, status exec.Status) (exec.Status, error) {
+	return "", nil
+}
+
+func (f *fakeDB) ListWebhooks(ctx context.Context, userID string) ([]db.Webhook, error) {
+	return []db.Webhook{}, nil
+}
+
+func (f *fakeDB) ListChatDestinations(ctx context.Context, userID string) ([]db.ChatDestination, error) {
+	return []db.ChatDestination{}, nil
+}
+
+func newTestScheduler(client db.DatabaseClient, now time.Time) *Scheduler {
+	s := New(client, notify.NewDispatcher(client))
+	s.now = func() time.Time { return now }
+	s.execute = func(ctx context.Context, req exec.TestExecutionRequest) (*exec.CheckResponse, error) {
+		return &exec.CheckResponse{BaseURL: req.BaseURL, Status: exec.StatusPass}, nil
+	}
+	return s
+}
+
+func TestNextRun(t *testing.T) {
+	after := time.Date(2025, 1, 1, 10, 7, 30, 0, time.UTC)
+
+	next, err := NextRun(db.Monitor{Schedule: "*/15 * * * *"}, after)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 10, 15, 0, 0, time.UTC), next)
+
+	next, err = NextRun(db.Monitor{IntervalSeconds: 300}, after)
+	require.NoError(t, err)
+	assert.Equal(t, after.Add(5*time.Minute), next)
+
+	_, err = NextRun(db.Monitor{Schedule: "not a cron"}, after)
+	assert.ErrorContains(t, err, "invalid cron schedule")
+
+	_, err = NextRun(db.Monitor{IntervalSeconds: 5}, after)
+	assert.ErrorContains(t, err, "at least 60")
+
+	_, err = NextRun(db.Monitor{Schedule: "* * * * *", IntervalSeconds: 60}, after)
+	assert.Error(t, err)
+}
+
+func TestTickRunsDueMonitorsOnceAcrossReplicas(t *testing.T) {
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	client := &fakeDB{monitors: map[string]*db.Monitor{
+		"due":    {ID: "due", UserID: "user", SuiteID: "suite-1", IntervalSeconds: 60, NextRunAt: now.Add(-time.Second)},
+		"later":  {ID: "later", UserID: "user", SuiteID: "suite-2", IntervalSeconds: 60, NextRunAt: now.Add(time.Minute)},
+		"paused": {ID: "paused", UserID: "user", SuiteID: "suite-3", IntervalSeconds: 60, NextRunAt: now, Paused: true},
+	}}
+
+	replicas := []*Scheduler{newTestScheduler(client, now), newTestScheduler(client, now)}
+	var wg sync.WaitGroup
+	for _, replica := range replicas {
+		wg.Add(1)
+		go func(s *Scheduler) {
+			defer wg.Done()
+			s.Tick(context.Background())
+			s.Wait()
+		}(replica)
+	}
+	wg.Wait()
+
+	require.Len(t, client.stored, 1)
+	assert.Equal(t, "suite-1", client.stored[0].SuiteID)
+	assert.Equal(t, now.Add(time.Minute), client.monitors["due"].NextRunAt)
+}
@@ -0,0 +1,127 @@
+package stream
+
+import (
+	"sync"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventResult   EventType = "result"
+	EventScenario EventType = "scenario"
+	EventSummary  EventType = "summary"
+	// EventError ends a stream that stopped before the run's summary
+	EventError EventType = "error"
+)
+
+const (
+	subscriberBuffer = 64
+	defaultRetention = 5 * time.Minute
+)
+
+// Event is a single update published for a run.
+type Event struct {
+	Type EventType
+	Data interface{}
+}
+
+type run struct {
+	userID      string
+	events      []Event
+	subscribers map[chan Event]struct{}
+	closed      bool
+}
+
+// Broker fans out the events of in-progress runs to their subscribers.
+// Every event of a run is kept until shortly after it closes, so that a
+// subscriber that connects late still sees the run from the start.
+type Broker struct {
+	mu        sync.Mutex
+	runs      map[string]*run
+	retention time.Duration
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		runs:      map[string]*run{},
+		retention: defaultRetention,
+	}
+}
+
+// Open starts tracking a run owned by the given user.
+func (b *Broker) Open(runID string, userID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.runs[runID] = &run{userID: userID, subscribers: map[chan Event]struct{}{}}
+}
+
+// Publish records an event and sends it to every subscriber of the run.
+// Subscribers that fall too far behind are dropped rather than blocking
+// the run.
+func (b *Broker) Publish(runID string, event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r, ok := b.runs[runID]
+	if !ok || r.closed {
+		return
+	}
+	r.events = append(r.events, event)
+	for subscriber := range r.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			delete(r.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// Close marks the run as finished, ending every subscription. The run's
+// events are forgotten once the retention period has passed.
+func (b *Broker) Close(runID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r, ok := b.runs[runID]
+	if !ok || r.closed {
+		return
+	}
+	r.closed = true
+	for subscriber := range r.subscribers {
+		close(subscriber)
+	}
+	r.subscribers = nil
+	time.AfterFunc(b.retention, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.runs, runID)
+	})
+}
+
+// Subscribe returns the events published so far and, while the run is in
+// progress, a channel of the events that follow. The channel is nil once
+// the run has closed. ok is false if the run is unknown to this broker or
+// belongs to another user.
+func (b *Broker) Subscribe(runID string, userID string) (history []Event, live <-chan Event, cancel func(), ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r, found := b.runs[runID]
+	if !found || r.userID != userID {
+		return nil, nil, func() {}, false
+	}
+	history = append([]Event{}, r.events...)
+	if r.closed {
+		return history, nil, func() {}, true
+	}
+	subscriber := make(chan Event, subscriberBuffer)
+	r.subscribers[subscriber] = struct{}{}
+	cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := r.subscribers[subscriber]; ok {
+			delete(r.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+	return history, subscriber, cancel, true
+}
@@ -0,0 +1,58 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collect(live <-chan Event) []Event {
+	events := []Event{}
+	for event := range live {
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestBrokerReplaysHistoryToLateSubscribers(t *testing.T) {
+	broker := NewBroker()
+	broker.Open("run-1", "user")
+	broker.Publish("run-1", Event{Type: EventResult, Data: 1})
+
+	history, live, cancel, ok := broker.Subscribe("run-1", "user")
+	defer cancel()
+	assert.True(t, ok)
+	assert.Equal(t, []Event{{Type: EventResult, Data: 1}}, history)
+
+	broker.Publish("run-1", Event{Type: EventResult, Data: 2})
+	broker.Publish("run-1", Event{Type: EventSummary, Data: 3})
+	broker.Close("run-1")
+	assert.Equal(t, []Event{{Type: EventResult, Data: 2}, {Type: EventSummary, Data: 3}}, collect(live))
+
+	history, live, _, ok = broker.Subscribe("run-1", "user")
+	assert.True(t, ok)
+	assert.Nil(t, live)
+	assert.Len(t, history, 3)
+}
+
+func TestBrokerRejectsOtherUsersAndUnknownRuns(t *testing.T) {
+	broker := NewBroker()
+	broker.Open("run-1", "user")
+
+	_, _, _, ok := broker.Subscribe("run-1", "someone-else")
+	assert.False(t, ok)
+	_, _, _, ok = broker.Subscribe("run-2", "user")
+	assert.False(t, ok)
+}
+
+func TestBrokerDropsSlowSubscribers(t *testing.T) {
+	broker := NewBroker()
+	broker.Open("run-1", "user")
+	_, live, cancel, _ := broker.Subscribe("run-1", "user")
+	defer cancel()
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		broker.Publish("run-1", Event{Type: EventResult, Data: i})
+	}
+	assert.Len(t, collect(live), subscriberBuffer)
+}
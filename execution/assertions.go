@@ -0,0 +1,263 @@
+package execution
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+type AssertionType string
+
+const (
+	AssertionJSONPath   AssertionType = "jsonpath"
+	AssertionRegex      AssertionType = "regex"
+	AssertionContains   AssertionType = "contains"
+	AssertionHeader     AssertionType = "header"
+	AssertionJSONSchema AssertionType = "json_schema"
+)
+
+// Assertion describes a check made against the response of an endpoint.
+//
+// The fields used depend on the assertion type:
+//   - jsonpath: Path, with either Exists or Value
+//   - regex: Pattern, matched against the body
+//   - contains: Value, a substring of the body
+//   - header: Header, with one of Exists, Value or Pattern
+//   - json_schema: Schema, an inline JSON Schema document
+type Assertion struct {
+	Type    AssertionType   `json:"type" binding:"required,oneof=jsonpath regex contains header json_schema"`
+	Path    string          `json:"path,omitempty"`
+	Header  string          `json:"header,omitempty"`
+	Pattern string          `json:"pattern,omitempty"`
+	Value   interface{}     `json:"value,omitempty"`
+	Exists  *bool           `json:"exists,omitempty"`
+	Schema  json.RawMessage `json:"schema,omitempty"`
+}
+
+// AssertionResult represents the outcome of a single assertion.
+type AssertionResult struct {
+	Type    AssertionType `json:"type"`
+	Target  string        `json:"target,omitempty"`
+	Passed  bool          `json:"passed"`
+	Message string        `json:"message,omitempty"`
+}
+
+// responseData holds the parts of a response that assertions look at.
+// The body is only decoded as JSON once, on first use.
+type responseData struct {
+	header  http.Header
+	body    []byte
+	decoded interface{}
+	decErr  error
+	parsed  bool
+}
+
+func (r *responseData) json() (interface{}, error) {
+	if !r.parsed {
+		r.parsed = true
+		r.decErr = json.Unmarshal(r.body, &r.decoded)
+	}
+	return r.decoded, r.decErr
+}
+
+// EvaluateAssertions runs every assertion against the response.
+func EvaluateAssertions(assertions []Assertion, header http.Header, body []byte) []AssertionResult {
+	return evaluateAssertions(assertions, &responseData{header: header, body: body})
+}
+
+func evaluateAssertions(assertions []Assertion, data *responseData) []AssertionResult {
+	results := make([]AssertionResult, len(assertions))
+	for i, assertion := range assertions {
+		results[i] = assertion.evaluate(data)
+	}
+	return results
+}
+
+func (a Assertion) evaluate(data *responseData) AssertionResult {
+	result := AssertionResult{Type: a.Type, Target: a.target()}
+	err := a.check(data)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	result.Passed = true
+	return result
+}
+
+func (a Assertion) target() string {
+	switch a.Type {
+	case AssertionJSONPath:
+		return a.Path
+	case AssertionHeader:
+		return a.Header
+	case AssertionRegex:
+		return a.Pattern
+	}
+	return ""
+}
+
+func (a Assertion) check(data *responseData) error {
+	switch a.Type {
+	case AssertionJSONPath:
+		return a.checkJSONPath(data)
+	case AssertionRegex:
+		return a.checkRegex(data.body)
+	case AssertionContains:
+		return a.checkContains(data.body)
+	case AssertionHeader:
+		return a.checkHeader(data.header)
+	case AssertionJSONSchema:
+		return a.checkJSONSchema(data)
+	}
+	return fmt.Errorf("unsupported assertion type '%s'", a.Type)
+}
+
+func (a Assertion) checkJSONPath(data *responseData) error {
+	if a.Path == "" {
+		return fmt.Errorf("jsonpath assertion requires a path")
+	}
+	if a.Value == nil && a.Exists == nil {
+		return fmt.Errorf("jsonpath assertion requires one of value or exists")
+	}
+	document, err := data.json()
+	if err != nil {
+		return fmt.Errorf("response body is not valid JSON: %w", err)
+	}
+	actual, err := jsonpath.Get(a.Path, document)
+	found := err == nil
+	if a.Exists != nil {
+		if found != *a.Exists {
+			if *a.Exists {
+				return fmt.Errorf("expected %s to exist", a.Path)
+			}
+			return fmt.Errorf("expected %s not to exist", a.Path)
+		}
+		return nil
+	}
+	if !found {
+		return fmt.Errorf("%s not found in response: %v", a.Path, err)
+	}
+	expected, err := normalizeJSON(a.Value)
+	if err != nil {
+		return fmt.Errorf("invalid expected value for %s: %w", a.Path, err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		return fmt.Errorf("expected %s to equal %s, got %s", a.Path, formatJSON(expected), formatJSON(actual))
+	}
+	return nil
+}
+
+func (a Assertion) checkRegex(body []byte) error {
+	pattern, err := regexp.Compile(a.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid regex '%s': %w", a.Pattern, err)
+	}
+	if !pattern.Match(body) {
+		return fmt.Errorf("response body does not match regex '%s'", a.Pattern)
+	}
+	return nil
+}
+
+func (a Assertion) checkContains(body []byte) error {
+	substring, ok := a.Value.(string)
+	if !ok || substring == "" {
+		return fmt.Errorf("contains assertion requires a string value")
+	}
+	if !bytes.Contains(body, []byte(substring)) {
+		return fmt.Errorf("response body does not contain '%s'", substring)
+	}
+	return nil
+}
+
+func (a Assertion) checkHeader(header http.Header) error {
+	if a.Header == "" {
+		return fmt.Errorf("header assertion requires a header name")
+	}
+	if a.Value == nil && a.Pattern == "" && a.Exists == nil {
+		return fmt.Errorf("header assertion requires one of value, pattern or exists")
+	}
+	values, found := header[http.CanonicalHeaderKey(a.Header)]
+	actual := strings.Join(values, ", ")
+	if a.Exists != nil {
+		if found != *a.Exists {
+			if *a.Exists {
+				return fmt.Errorf("expected header %s to be present", a.Header)
+			}
+			return fmt.Errorf("expected header %s to be absent", a.Header)
+		}
+		return nil
+	}
+	if !found {
+		return fmt.Errorf("header %s not found in response", a.Header)
+	}
+	if a.Pattern != "" {
+		pattern, err := regexp.Compile(a.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid regex '%s': %w", a.Pattern, err)
+		}
+		if !pattern.MatchString(actual) {
+			return fmt.Errorf("header %s value '%s' does not match regex '%s'", a.Header, actual, a.Pattern)
+		}
+		return nil
+	}
+	expected := fmt.Sprint(a.Value)
+	if actual != expected {
+		return fmt.Errorf("expected header %s to equal '%s', got '%s'", a.Header, expected, actual)
+	}
+	return nil
+}
+
+func (a Assertion) checkJSONSchema(data *responseData) error {
+	if len(a.Schema) == 0 {
+		return fmt.Errorf("json_schema assertion requires a schema")
+	}
+	schema, err := jsonschema.CompileString("schema.json", string(a.Schema))
+	if err != nil {
+		return fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	document, err := data.json()
+	if err != nil {
+		return fmt.Errorf("response body is not valid JSON: %w", err)
+	}
+	if err := schema.Validate(document); err != nil {
+		return fmt.Errorf("response does not match schema: %v", err)
+	}
+	return nil
+}
+
+// normalizeJSON round-trips a value through JSON so that it can be
+// compared with values decoded from a response body.
+func normalizeJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+func formatJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func failedAssertionMessages(results []AssertionResult) []string {
+	messages := []string{}
+	for _, result := range results {
+		if !result.Passed {
+			messages = append(messages, result.Message)
+		}
+	}
+	return messages
+}
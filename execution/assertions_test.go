@@ -0,0 +1,179 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+func TestEvaluateAssertions(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	header.Set("X-Request-Id", "abc-123")
+	body := []byte(`{"status":"ok","data":{"items":[{"id":1},{"id":2}],"count":2}}`)
+
+	tests := []struct {
+		name      string
+		assertion Assertion
+		passed    bool
+		message   string
+	}{
+		{
+			name:      "jsonpath equals string",
+			assertion: Assertion{Type: AssertionJSONPath, Path: "$.status", Value: "ok"},
+			passed:    true,
+		},
+		{
+			name:      "jsonpath equals number",
+			assertion: Assertion{Type: AssertionJSONPath, Path: "$.data.count", Value: 2},
+			passed:    true,
+		},
+		{
+			name:      "jsonpath array index",
+			assertion: Assertion{Type: AssertionJSONPath, Path: "$.data.items[1].id", Value: 2},
+			passed:    true,
+		},
+		{
+			name:      "jsonpath value mismatch",
+			assertion: Assertion{Type: AssertionJSONPath, Path: "$.status", Value: "error"},
+			message:   `expected $.status to equal "error", got "ok"`,
+		},
+		{
+			name:      "jsonpath exists",
+			assertion: Assertion{Type: AssertionJSONPath, Path: "$.data.items", Exists: boolPtr(true)},
+			passed:    true,
+		},
+		{
+			name:      "jsonpath missing",
+			assertion: Assertion{Type: AssertionJSONPath, Path: "$.error", Exists: boolPtr(true)},
+			message:   "expected $.error to exist",
+		},
+		{
+			name:      "jsonpath not exists",
+			assertion: Assertion{Type: AssertionJSONPath, Path: "$.error", Exists: boolPtr(false)},
+			passed:    true,
+		},
+		{
+			name:      "jsonpath without expectation",
+			assertion: Assertion{Type: AssertionJSONPath, Path: "$.status"},
+			message:   "jsonpath assertion requires one of value or exists",
+		},
+		{
+			name:      "regex match",
+			assertion: Assertion{Type: AssertionRegex, Pattern: `"id":\d+`},
+			passed:    true,
+		},
+		{
+			name:      "regex no match",
+			assertion: Assertion{Type: AssertionRegex, Pattern: `"error"`},
+			message:   `response body does not match regex '"error"'`,
+		},
+		{
+			name:      "contains",
+			assertion: Assertion{Type: AssertionContains, Value: `"status":"ok"`},
+			passed:    true,
+		},
+		{
+			name:      "does not contain",
+			assertion: Assertion{Type: AssertionContains, Value: "failure"},
+			message:   "response body does not contain 'failure'",
+		},
+		{
+			name:      "header equals",
+			assertion: Assertion{Type: AssertionHeader, Header: "x-request-id", Value: "abc-123"},
+			passed:    true,
+		},
+		{
+			name:      "header pattern",
+			assertion: Assertion{Type: AssertionHeader, Header: "Content-Type", Pattern: "^application/json"},
+			passed:    true,
+		},
+		{
+			name:      "header mismatch",
+			assertion: Assertion{Type: AssertionHeader, Header: "X-Request-Id", Value: "xyz"},
+			message:   "expected header X-Request-Id to equal 'xyz', got 'abc-123'",
+		},
+		{
+			name:      "header absent",
+			assertion: Assertion{Type: AssertionHeader, Header: "X-Debug", Exists: boolPtr(false)},
+			passed:    true,
+		},
+		{
+			name:      "header without expectation",
+			assertion: Assertion{Type: AssertionHeader, Header: "X-Request-Id"},
+			message:   "header assertion requires one of value, pattern or exists",
+		},
+		{
+			name: "schema valid",
+			assertion: Assertion{Type: AssertionJSONSchema, Schema: json.RawMessage(`{
+				"type": "object",
+				"required": ["status", "data"],
+				"properties": {"status": {"type": "string"}}
+			}`)},
+			passed: true,
+		},
+		{
+			name: "schema invalid",
+			assertion: Assertion{Type: AssertionJSONSchema, Schema: json.RawMessage(`{
+				"type": "object",
+				"properties": {"status": {"type": "integer"}}
+			}`)},
+			message: "response does not match schema",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := EvaluateAssertions([]Assertion{tt.assertion}, header, body)
+			require.Len(t, results, 1)
+			assert.Equal(t, tt.passed, results[0].Passed)
+			assert.Contains(t, results[0].Message, tt.message)
+		})
+	}
+}
+
+func TestAssertionsOnInvalidJSON(t *testing.T) {
+	results := EvaluateAssertions([]Assertion{
+		{Type: AssertionJSONPath, Path: "$.status", Value: "ok"},
+	}, http.Header{}, []byte("not json"))
+	assert.False(t, results[0].Passed)
+	assert.Contains(t, results[0].Message, "response body is not valid JSON")
+}
+
+func TestExecuteTestsFailingAssertion(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+		res.Write([]byte(`{"status":"error"}`))
+	})
+	mockServer := httptest.NewServer(mux)
+	defer mockServer.Close()
+	request := TestExecutionRequest{
+		BaseURL: mockServer.URL,
+		Endpoints: []Endpoint{
+			{
+				Path:           "/status",
+				ExpectedStatus: http.StatusOK,
+				Assertions: []Assertion{
+					{Type: AssertionJSONPath, Path: "$.status", Value: "ok"},
+				},
+			},
+		},
+	}
+	results, err := ExecuteTests(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFail, results.Status)
+	assert.Equal(t, "FAIL", results.Results[0].StatusCode)
+	assert.Equal(t, `expected $.status to equal "ok", got "error"`, results.Results[0].Message)
+	require.Len(t, results.Results[0].Assertions, 1)
+	assert.False(t, results.Results[0].Assertions[0].Passed)
+}
@@ -0,0 +1,355 @@
+package execution
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+type AuthType string
+
+const (
+	AuthBearer                  AuthType = "bearer"
+	AuthBasic                   AuthType = "basic"
+	AuthAPIKey                  AuthType = "api_key"
+	AuthOAuth2ClientCredentials AuthType = "oauth2_client_credentials"
+)
+
+const (
+	APIKeyInHeader = "header"
+	APIKeyInQuery  = "query"
+)
+
+const redactedValue = "[REDACTED]"
+
+// ErrRedactedSecret is returned when a credential is the redaction
+// placeholder and there is no stored credential to put back in its place.
+var ErrRedactedSecret = errors.New("credential is the redaction placeholder")
+
+// AuthConfig describes the credentials used to call the target API.
+//
+// The fields used depend on the auth type:
+//   - bearer: Token
+//   - basic: Username and Password
+//   - api_key: KeyName, APIKey, and In (header or query, default header)
+//   - oauth2_client_credentials: TokenURL, ClientID, ClientSecret and Scopes
+type AuthConfig struct {
+	Type         AuthType `json:"type" binding:"required,oneof=bearer basic api_key oauth2_client_credentials"`
+	Token        string   `json:"token,omitempty"`
+	Username     string   `json:"username,omitempty"`
+	Password     string   `json:"password,omitempty"`
+	KeyName      string   `json:"key_name,omitempty"`
+	APIKey       string   `json:"api_key,omitempty"`
+	In           string   `json:"in,omitempty" binding:"omitempty,oneof=header query"`
+	TokenURL     string   `json:"token_url,omitempty" binding:"omitempty,url"`
+	ClientID     string   `json:"client_id,omitempty"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+}
+
+// Redacted returns a copy of the config with every secret masked.
+func (a AuthConfig) Redacted() AuthConfig {
+	mask := func(value string) string {
+		if value == "" {
+			return ""
+		}
+		return redactedValue
+	}
+	a.Token = mask(a.Token)
+	a.Password = mask(a.Password)
+	a.APIKey = mask(a.APIKey)
+	a.ClientSecret = mask(a.ClientSecret)
+	return a
+}
+
+// withStoredSecrets returns a copy of the config with every secret left as
+// the redaction placeholder replaced by the same secret of the stored config.
+// The stored config must be of the same type.
+func (a *AuthConfig) withStoredSecrets(stored *AuthConfig) (*AuthConfig, error) {
+	if a == nil {
+		return nil, nil
+	}
+	if stored == nil || stored.Type != a.Type {
+		stored = &AuthConfig{}
+	}
+	restored := *a
+	for _, secret := range []struct {
+		value  *string
+		stored string
+	}{
+		{&restored.Token, stored.Token},
+		{&restored.Password, stored.Password},
+		{&restored.APIKey, stored.APIKey},
+		{&restored.ClientSecret, stored.ClientSecret},
+	} {
+		if *secret.value != redactedValue {
+			continue
+		}
+		if secret.stored == "" {
+			return nil, ErrRedactedSecret
+		}
+		*secret.value = secret.stored
+	}
+	return &restored, nil
+}
+
+// String keeps credentials out of logs when the config is formatted.
+func (a AuthConfig) String() string {
+	return fmt.Sprintf("%+v", struct {
+		Type     AuthType
+		Username string
+		KeyName  string
+		ClientID string
+	}{a.Type, a.Username, a.KeyName, a.ClientID})
+}
+
+func (a AuthConfig) secrets() []string {
+	secrets := []string{a.Token, a.Password, a.APIKey, a.ClientSecret}
+	if a.Type == AuthBasic {
+		secrets = append(secrets, basicCredentials(a.Username, a.Password))
+	}
+	return secrets
+}
+
+func (a AuthConfig) tokenCacheKey() string {
+	scopes := append([]string{}, a.Scopes...)
+	sort.Strings(scopes)
+	return strings.Join([]string{a.TokenURL, a.ClientID, strings.Join(scopes, " ")}, "|")
+}
+
+func basicCredentials(username string, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
+
+// redactor masks known secret values in text.
+type redactor struct {
+	mu      sync.RWMutex
+	secrets map[string]struct{}
+}
+
+func newRedactor() *redactor {
+	return &redactor{secrets: map[string]struct{}{}}
+}
+
+func (r *redactor) add(secrets ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, secret := range secrets {
+		if secret != "" {
+			r.secrets[secret] = struct{}{}
+		}
+	}
+}
+
+// Redact replaces every known secret in the text.
+func (r *redactor) Redact(text string) string {
+	if r == nil {
+		return text
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for secret := range r.secrets {
+		text = strings.ReplaceAll(text, secret, redactedValue)
+	}
+	return text
+}
+
+type oauthToken struct {
+	token *oauth2.Token
+	err   error
+}
+
+// authenticator applies credentials to outbound requests. OAuth2 tokens
+// are fetched once before the run and reused by every request.
+type authenticator struct {
+	defaultAuth *AuthConfig
+	tokens      map[string]oauthToken
+	redactor    *redactor
+}
+
+func newAuthenticator(defaultAuth *AuthConfig) *authenticator {
+	return &authenticator{
+		defaultAuth: defaultAuth,
+		tokens:      map[string]oauthToken{},
+		redactor:    newRedactor(),
+	}
+}
+
+// prefetch registers every secret for redaction and fetches the tokens
+// for all OAuth2 configs used by the run.
+func (a *authenticator) prefetch(ctx context.Context, client *http.Client, configs []*AuthConfig) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, client)
+	for _, config := range configs {
+		if config == nil {
+			continue
+		}
+		a.redactor.add(config.secrets()...)
+		if config.Type != AuthOAuth2ClientCredentials {
+			continue
+		}
+		key := config.tokenCacheKey()
+		if _, ok := a.tokens[key]; ok {
+			continue
+		}
+		credentials := clientcredentials.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			TokenURL:     config.TokenURL,
+			Scopes:       config.Scopes,
+		}
+		token, err := credentials.Token(ctx)
+		if err != nil {
+			err = fmt.Errorf("failed to fetch OAuth2 token from %s: %w", config.TokenURL, err)
+		} else {
+			a.redactor.add(token.AccessToken)
+		}
+		a.tokens[key] = oauthToken{token: token, err: err}
+	}
+}
+
+// apply adds the endpoint's credentials, or the run default, to the request.
+func (a *authenticator) apply(req *http.Request, config *AuthConfig) error {
+	if a == nil {
+		return nil
+	}
+	if config == nil {
+		config = a.defaultAuth
+	}
+	if config == nil {
+		return nil
+	}
+	a.redactor.add(config.secrets()...)
+
+	switch config.Type {
+	case AuthBearer:
+		req.Header.Set("Authorization", "Bearer "+config.Token)
+	case AuthBasic:
+		req.SetBasicAuth(config.Username, config.Password)
+	case AuthAPIKey:
+		if config.KeyName == "" {
+			return fmt.Errorf("api_key auth requires a key_name")
+		}
+		if config.In == APIKeyInQuery {
+			query := req.URL.Query()
+			query.Set(config.KeyName, config.APIKey)
+			req.URL.RawQuery = query.Encode()
+		} else {
+			req.Header.Set(config.KeyName, config.APIKey)
+		}
+	case AuthOAuth2ClientCredentials:
+		cached, ok := a.tokens[config.tokenCacheKey()]
+		if !ok {
+			return fmt.Errorf("no OAuth2 token was fetched for %s", config.TokenURL)
+		}
+		if cached.err != nil {
+			return cached.err
+		}
+		cached.token.SetAuthHeader(req)
+	default:
+		return fmt.Errorf("unsupported auth type '%s'", config.Type)
+	}
+	return nil
+}
+
+// authConfigs lists every auth config referenced by a request.
+func (r TestExecutionRequest) authConfigs() []*AuthConfig {
+	configs := []*AuthConfig{r.Auth}
+	for _, endpoint := range r.Endpoints {
+		configs = append(configs, endpoint.Auth)
+	}
+	for _, scenario := range r.Scenarios {
+		for _, step := range scenario.Steps {
+			configs = append(configs, step.Auth)
+		}
+	}
+	return configs
+}
+
+// Redacted returns a copy of the request with every credential masked,
+// suitable for returning a stored request to a client.
+func (r TestExecutionRequest) Redacted() TestExecutionRequest {
+	redactAuth := func(config *AuthConfig) *AuthConfig {
+		if config == nil {
+			return nil
+		}
+		redacted := config.Redacted()
+		return &redacted
+	}
+	r.Auth = redactAuth(r.Auth)
+	if r.Endpoints != nil {
+		endpoints := make([]Endpoint, len(r.Endpoints))
+		for i, endpoint := range r.Endpoints {
+			endpoint.Auth = redactAuth(endpoint.Auth)
+			endpoints[i] = endpoint
+		}
+		r.Endpoints = endpoints
+	}
+	if r.Scenarios != nil {
+		scenarios := make([]Scenario, len(r.Scenarios))
+		for i, scenario := range r.Scenarios {
+			steps := make([]ScenarioStep, len(scenario.Steps))
+			for j, step := range scenario.Steps {
+				step.Auth = redactAuth(step.Auth)
+				steps[j] = step
+			}
+			scenario.Steps = steps
+			scenarios[i] = scenario
+		}
+		r.Scenarios = scenarios
+	}
+	return r
+}
+
+// WithStoredSecrets returns a copy of the request in which every credential
+// left as the redaction placeholder is replaced by the credential at the same
+// place in the stored request, so that a request read back from Redacted can
+// be sent again unchanged. It fails with ErrRedactedSecret when there is no
+// stored credential of the same auth type to restore.
+func (r TestExecutionRequest) WithStoredSecrets(stored TestExecutionRequest) (TestExecutionRequest, error) {
+	var err error
+	if r.Auth, err = r.Auth.withStoredSecrets(stored.Auth); err != nil {
+		return r, err
+	}
+	if r.Endpoints != nil {
+		endpoints := make([]Endpoint, len(r.Endpoints))
+		for i, endpoint := range r.Endpoints {
+			var previous *AuthConfig
+			if i < len(stored.Endpoints) {
+				previous = stored.Endpoints[i].Auth
+			}
+			if endpoint.Auth, err = endpoint.Auth.withStoredSecrets(previous); err != nil {
+				return r, err
+			}
+			endpoints[i] = endpoint
+		}
+		r.Endpoints = endpoints
+	}
+	if r.Scenarios != nil {
+		scenarios := make([]Scenario, len(r.Scenarios))
+		for i, scenario := range r.Scenarios {
+			steps := make([]ScenarioStep, len(scenario.Steps))
+			for j, step := range scenario.Steps {
+				var previous *AuthConfig
+				if i < len(stored.Scenarios) && j < len(stored.Scenarios[i].Steps) {
+					previous = stored.Scenarios[i].Steps[j].Auth
+				}
+				if step.Auth, err = step.Auth.withStoredSecrets(previous); err != nil {
+					return r, err
+				}
+				steps[j] = step
+			}
+			scenario.Steps = steps
+			scenarios[i] = scenario
+		}
+		r.Scenarios = scenarios
+	}
+	return r, nil
+}
@@ -0,0 +1,209 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuthCheckingServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/bearer", func(res http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer static-token" {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		res.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/basic", func(res http.ResponseWriter, req *http.Request) {
+		username, password, ok := req.BasicAuth()
+		if !ok || username != "admin" || password != "hunter2" {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		res.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/api-key", func(res http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Api-Key") != "header-key" && req.URL.Query().Get("api_key") != "query-key" {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		res.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/oauth", func(res http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer issued-token" {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		res.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTokenServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		clientID, clientSecret, ok := req.BasicAuth()
+		if !ok || clientID != "client" || clientSecret != "client-secret" || req.FormValue("grant_type") != "client_credentials" {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		res.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(res, `{"access_token":"issued-token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestExecuteTestsWithAuth(t *testing.T) {
+	var tokenRequests atomic.Int32
+	server := newAuthCheckingServer(t)
+	tokenServer := newTokenServer(t, &tokenRequests)
+	oauth := &AuthConfig{
+		Type:         AuthOAuth2ClientCredentials,
+		TokenURL:     tokenServer.URL,
+		ClientID:     "client",
+		ClientSecret: "client-secret",
+	}
+
+	request := TestExecutionRequest{
+		BaseURL: server.URL,
+		Auth:    &AuthConfig{Type: AuthBearer, Token: "static-token"},
+		Endpoints: []Endpoint{
+			{Path: "/bearer", ExpectedStatus: http.StatusOK},
+			{Path: "/basic", ExpectedStatus: http.StatusOK, Auth: &AuthConfig{Type: AuthBasic, Username: "admin", Password: "hunter2"}},
+			{Path: "/api-key", ExpectedStatus: http.StatusOK, Auth: &AuthConfig{Type: AuthAPIKey, KeyName: "X-Api-Key", APIKey: "header-key"}},
+			{Path: "/api-key", ExpectedStatus: http.StatusOK, Auth: &AuthConfig{Type: AuthAPIKey, KeyName: "api_key", APIKey: "query-key", In: APIKeyInQuery}},
+			{Path: "/oauth", ExpectedStatus: http.StatusOK, Auth: oauth},
+			{Path: "/oauth", ExpectedStatus: http.StatusOK, Auth: oauth},
+		},
+	}
+	results, err := ExecuteTests(context.Background(), request)
+	require.NoError(t, err)
+	for _, result := range results.Results {
+		assert.Equal(t, "PASS", result.StatusCode, "%s: %s", result.Path, result.Message)
+	}
+	assert.Equal(t, int32(1), tokenRequests.Load())
+}
+
+func TestExecuteTestsOAuthFailure(t *testing.T) {
+	var tokenRequests atomic.Int32
+	server := newAuthCheckingServer(t)
+	tokenServer := newTokenServer(t, &tokenRequests)
+
+	request := TestExecutionRequest{
+		BaseURL: server.URL,
+		Auth: &AuthConfig{
+			Type:         AuthOAuth2ClientCredentials,
+			TokenURL:     tokenServer.URL,
+			ClientID:     "client",
+			ClientSecret: "wrong-secret",
+		},
+		Endpoints: []Endpoint{{Path: "/oauth", ExpectedStatus: http.StatusOK}},
+	}
+	results, err := ExecuteTests(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, StatusError, results.Status)
+	assert.Equal(t, ErrorCategoryAuth, results.Results[0].Error.Category)
+	assert.NotContains(t, results.Results[0].Message, "wrong-secret")
+}
+
+func TestExecuteTestsRedactsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.NewServeMux())
+	server.Close()
+
+	request := TestExecutionRequest{
+		BaseURL: server.URL,
+		Auth:    &AuthConfig{Type: AuthAPIKey, KeyName: "api_key", APIKey: "super-secret-key", In: APIKeyInQuery},
+		Endpoints: []Endpoint{
+			{Path: "/home", ExpectedStatus: http.StatusOK},
+		},
+	}
+	results, err := ExecuteTests(context.Background(), request)
+	require.NoError(t, err)
+	result := results.Results[0]
+	assert.Equal(t, "ERROR", result.StatusCode)
+	assert.NotContains(t, result.Message, "super-secret-key")
+	assert.NotContains(t, result.Error.Message, "super-secret-key")
+	assert.Contains(t, result.Error.Message, "[REDACTED]")
+}
+
+func TestAuthConfigRedacted(t *testing.T) {
+	config := AuthConfig{Type: AuthBasic, Username: "admin", Password: "hunter2"}
+	redacted := config.Redacted()
+	assert.Equal(t, "admin", redacted.Username)
+	assert.Equal(t, "[REDACTED]", redacted.Password)
+	assert.Empty(t, redacted.Token)
+	assert.NotContains(t, fmt.Sprint(config), "hunter2")
+	assert.NotContains(t, fmt.Sprintf("%v", &config), "hunter2")
+}
+
+func TestTestExecutionRequestRedacted(t *testing.T) {
+	request := TestExecutionRequest{
+		BaseURL: "https://example.com",
+		Auth:    &AuthConfig{Type: AuthBearer, Token: "run-token"},
+		Endpoints: []Endpoint{
+			{Path: "/a", ExpectedStatus: 200, Auth: &AuthConfig{Type: AuthAPIKey, KeyName: "X-Key", APIKey: "endpoint-key"}},
+		},
+		Scenarios: []Scenario{
+			{Name: "flow", Steps: []ScenarioStep{
+				{Endpoint: Endpoint{Path: "/b", ExpectedStatus: 200, Auth: &AuthConfig{Type: AuthBasic, Username: "u", Password: "p"}}},
+			}},
+		},
+	}
+	redacted := request.Redacted()
+	assert.Equal(t, "[REDACTED]", redacted.Auth.Token)
+	assert.Equal(t, "[REDACTED]", redacted.Endpoints[0].Auth.APIKey)
+	assert.Equal(t, "[REDACTED]", redacted.Scenarios[0].Steps[0].Auth.Password)
+
+	// The original request is left untouched
+	assert.Equal(t, "run-token", request.Auth.Token)
+	assert.Equal(t, "endpoint-key", request.Endpoints[0].Auth.APIKey)
+	assert.Equal(t, "p", request.Scenarios[0].Steps[0].Auth.Password)
+}
+
+func TestTestExecutionRequestWithStoredSecrets(t *testing.T) {
+	stored := TestExecutionRequest{
+		BaseURL: "https://example.com",
+		Auth:    &AuthConfig{Type: AuthBearer, Token: "run-token"},
+		Endpoints: []Endpoint{
+			{Path: "/a", ExpectedStatus: 200, Auth: &AuthConfig{Type: AuthAPIKey, KeyName: "X-Key", APIKey: "endpoint-key"}},
+		},
+		Scenarios: []Scenario{
+			{Name: "flow", Steps: []ScenarioStep{
+				{Endpoint: Endpoint{Path: "/b", ExpectedStatus: 200, Auth: &AuthConfig{Type: AuthBasic, Username: "u", Password: "p"}}},
+			}},
+		},
+	}
+
+	restored, err := stored.Redacted().WithStoredSecrets(stored)
+	require.NoError(t, err)
+	assert.Equal(t, stored, restored)
+
+	changed := stored.Redacted()
+	changed.Auth = &AuthConfig{Type: AuthBearer, Token: "new-token"}
+	restored, err = changed.WithStoredSecrets(stored)
+	require.NoError(t, err)
+	assert.Equal(t, "new-token", restored.Auth.Token)
+	assert.Equal(t, "endpoint-key", restored.Endpoints[0].Auth.APIKey)
+	assert.Equal(t, "[REDACTED]", changed.Endpoints[0].Auth.APIKey, "the request itself is left untouched")
+
+	// A placeholder with no stored secret of the same type cannot be restored
+	retyped := stored.Redacted()
+	retyped.Auth = &AuthConfig{Type: AuthBasic, Username: "u", Password: "[REDACTED]"}
+	_, err = retyped.WithStoredSecrets(stored)
+	assert.ErrorIs(t, err, ErrRedactedSecret)
+
+	_, err = stored.Redacted().WithStoredSecrets(TestExecutionRequest{})
+	assert.ErrorIs(t, err, ErrRedactedSecret)
+}
@@ -2,58 +2,115 @@ package execution
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"sync"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jgfranco17/aeternum/api/logging"
 )
 
+// Upper bound on how much of a response body is read for assertions.
+const maxResponseBodyBytes = 10 << 20
+
 type Status string
 
 const (
 	StatusPending     Status = "PENDING"
+	StatusRunning     Status = "RUNNING"
 	StatusFail        Status = "FAIL"
 	StatusPass        Status = "PASS"
 	StatusError       Status = "ERROR"
+	StatusSkipped     Status = "SKIPPED"
+	StatusCancelled   Status = "CANCELLED"
 	StatusUnspecified Status = "UNSPECIFIED"
 )
 
+// Endpoint describes a single request to be made against the target API.
 type Endpoint struct {
-	Path           string `json:"path" binding:"required"`
-	ExpectedStatus int    `json:"expected_status" binding:"required"`
+	Path           string            `json:"path" binding:"required"`
+	Method         string            `json:"method,omitempty" binding:"omitempty,oneofci=GET HEAD POST PUT PATCH DELETE OPTIONS"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Query          map[string]string `json:"query,omitempty"`
+	Body           *RequestBody      `json:"body,omitempty"`
+	ExpectedStatus int               `json:"expected_status" binding:"required"`
+	Assertions     []Assertion       `json:"assertions,omitempty" binding:"omitempty,dive"`
+	MaxResponseMs  *int              `json:"max_response_ms,omitempty" binding:"omitempty,min=1"`
+	Retry          *RetryPolicy      `json:"retry,omitempty"`
+	Auth           *AuthConfig       `json:"auth,omitempty"`
 }
 
 // TestExecutionRequest represents the API health check request payload.
 type TestExecutionRequest struct {
-	BaseURL           string     `json:"base_url" binding:"required,url"`
-	Endpoints         []Endpoint `json:"endpoints" binding:"required,dive"`
-	MaxTimeoutSeconds *int       `json:"max_timeout_seconds,omitempty"`
+	BaseURL           string       `json:"base_url" binding:"required,url"`
+	Endpoints         []Endpoint   `json:"endpoints" binding:"required_without=Scenarios,dive"`
+	Scenarios         []Scenario   `json:"scenarios,omitempty" binding:"omitempty,dive"`
+	MaxTimeoutSeconds *int         `json:"max_timeout_seconds,omitempty"`
+	MaxConcurrency    *int         `json:"max_concurrency,omitempty" binding:"omitempty,min=1"`
+	RequestsPerSecond *int         `json:"requests_per_second,omitempty" binding:"omitempty,min=1"`
+	Retry             *RetryPolicy `json:"retry,omitempty"`
+	Auth              *AuthConfig  `json:"auth,omitempty"`
+	Tags              []string     `json:"tags,omitempty" binding:"omitempty,max=20,dive,min=1,max=64"`
 }
 
 // CheckResult represents the result of an individual API test.
 type CheckResult struct {
-	Path           string `json:"path"`
-	ExpectedStatus int    `json:"expected_status"`
-	ActualStatus   int    `json:"actual_status"`
-	StatusCode     string `json:"status"`
+	Path           string            `json:"path"`
+	Method         string            `json:"method"`
+	ExpectedStatus int               `json:"expected_status"`
+	ActualStatus   int               `json:"actual_status"`
+	StatusCode     string            `json:"status"`
+	Message        string            `json:"message,omitempty"`
+	Assertions     []AssertionResult `json:"assertions,omitempty"`
+	Error          *CheckError       `json:"error,omitempty"`
+	Timings        *Timings          `json:"timings,omitempty"`
+	Attempts       int               `json:"attempts,omitempty"`
+	AttemptHistory []Attempt         `json:"attempt_history,omitempty"`
 }
 
 // CheckResponse represents the full response of an API check.
 type CheckResponse struct {
-	RequestID string        `json:"request_id"`
-	BaseURL   string        `json:"base_url"`
-	Status    Status        `json:"status"`
-	Results   []CheckResult `json:"results"`
+	RequestID string           `json:"request_id"`
+	SuiteID   string           `json:"suite_id,omitempty"`
+	BaseURL   string           `json:"base_url"`
+	Status    Status           `json:"status"`
+	Results   []CheckResult    `json:"results"`
+	Scenarios []ScenarioResult `json:"scenarios,omitempty"`
+	Latency   *LatencySummary  `json:"latency,omitempty"`
+	Tags      []string         `json:"tags,omitempty"`
+}
+
+// RunOptions customises a single run of ExecuteTestsWithOptions.
+type RunOptions struct {
+	// RequestID is used for the run instead of a newly generated one, so
+	// that a run can be referenced before it starts.
+	RequestID string
+	// OnResult is called with each endpoint result as soon as the endpoint
+	// completes, and OnScenario with each finished scenario. Both may be
+	// called concurrently from several workers.
+	OnResult   func(CheckResult)
+	OnScenario func(ScenarioResult)
+}
+
+// NewRequestID generates an ID for a test run.
+func NewRequestID() string {
+	return fmt.Sprintf("aeternum-v0-%s", uuid.New().String())
 }
 
 func ExecuteTests(ctx context.Context, testRequest TestExecutionRequest) (*CheckResponse, error) {
+	return ExecuteTestsWithOptions(ctx, testRequest, RunOptions{})
+}
+
+func ExecuteTestsWithOptions(ctx context.Context, testRequest TestExecutionRequest, opts RunOptions) (*CheckResponse, error) {
 	log := logging.FromContext(ctx)
-	requestID := fmt.Sprintf("aeternum-v0-%s", uuid.New().String())
+	requestID := opts.RequestID
+	if requestID == "" {
+		requestID = NewRequestID()
+	}
 	log.Debugf("Running test requests [ID %s]: %s", requestID, testRequest.BaseURL)
-	var wg sync.WaitGroup
 	results := make([]CheckResult, len(testRequest.Endpoints))
 
 	// Set timeout for API requests
@@ -63,54 +120,207 @@ func ExecuteTests(ctx context.Context, testRequest TestExecutionRequest) (*Check
 	} else {
 		timeout = 5
 	}
-	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
-	requestErrors := []string{}
-	failedTests := []string{}
+	limits := testRequest.effectiveLimits(LimitsFromEnvironment())
+	log.Debugf("Run %s limited to %d workers and %d requests per second", requestID, limits.MaxConcurrency, limits.RequestsPerSecond)
+	r := &runner{
+		client:  &http.Client{Timeout: time.Duration(timeout) * time.Second},
+		baseURL: testRequest.BaseURL,
+		limiter: newHostLimiter(limits.RequestsPerSecond),
+		retry:   testRequest.Retry,
+		auth:    newAuthenticator(testRequest.Auth),
+	}
+	r.auth.prefetch(ctx, r.client, testRequest.authConfigs())
+
+	var scenarioResults []ScenarioResult
+	if len(testRequest.Scenarios) > 0 {
+		scenarioResults = make([]ScenarioResult, len(testRequest.Scenarios))
+	}
+	jobs := []func(){}
 	for i, endpoint := range testRequest.Endpoints {
-		wg.Add(1)
-		go func(i int, e Endpoint) {
-			defer wg.Done()
-			fullURL := testRequest.BaseURL + e.Path
-			resp, err := client.Get(fullURL)
-			actualStatus := 0
-
-			if err != nil {
-				requestErrors = append(requestErrors, err.Error())
-				return
-			}
-			actualStatus = resp.StatusCode
-			resp.Body.Close()
-			status := "FAIL"
-			if actualStatus == e.ExpectedStatus {
-				status = "PASS"
-			} else {
-				failedTests = append(failedTests, e.Path)
+		jobs = append(jobs, func() {
+			results[i], _ = r.checkEndpoint(ctx, endpoint)
+			r.redactResult(&results[i])
+			if opts.OnResult != nil {
+				opts.OnResult(results[i])
 			}
-
-			results[i] = CheckResult{
-				Path:           e.Path,
-				ExpectedStatus: e.ExpectedStatus,
-				ActualStatus:   actualStatus,
-				StatusCode:     status,
+		})
+	}
+	for i, scenario := range testRequest.Scenarios {
+		jobs = append(jobs, func() {
+			scenarioResults[i] = r.runScenario(ctx, scenario)
+			r.redactScenario(&scenarioResults[i])
+			if opts.OnScenario != nil {
+				opts.OnScenario(scenarioResults[i])
 			}
-		}(i, endpoint)
+		})
 	}
-	wg.Wait()
+	runWorkerPool(limits.MaxConcurrency, jobs)
 
-	// Handle error cases
-	if len(requestErrors) > 0 {
-		return nil, fmt.Errorf("Failed to make %d requests: %v", len(requestErrors), requestErrors)
-	}
-	var overallStatus Status
-	if len(failedTests) > 0 {
-		overallStatus = StatusFail
-	} else {
-		overallStatus = StatusPass
+	overallStatus := summarizeStatus(results, scenarioResults)
+	if isCancelled(ctx) {
+		overallStatus = StatusCancelled
+		log.Infof("Test run %s was cancelled with %d endpoints not checked", requestID, countResults(results, StatusCancelled))
+	} else if overallStatus == StatusError {
+		log.Warnf("Test run %s completed with %d endpoint errors", requestID, countResults(results, StatusError))
 	}
 	return &CheckResponse{
 		RequestID: requestID,
 		BaseURL:   testRequest.BaseURL,
 		Results:   results,
+		Scenarios: scenarioResults,
 		Status:    overallStatus,
+		Latency:   summarizeLatency(append(results, scenarioStepResults(scenarioResults)...)),
+		Tags:      testRequest.Tags,
 	}, nil
 }
+
+// summarizeStatus derives the status of a run from its results. Any
+// transport error marks the whole run as ERROR, otherwise any failing
+// check or scenario marks it as FAIL.
+func summarizeStatus(results []CheckResult, scenarios []ScenarioResult) Status {
+	status := StatusPass
+	if countResults(results, StatusFail) > 0 {
+		status = StatusFail
+	}
+	if countResults(results, StatusError) > 0 {
+		status = StatusError
+	}
+	for _, scenario := range scenarios {
+		status = worseStatus(status, scenario.Status)
+	}
+	return status
+}
+
+func scenarioStepResults(scenarios []ScenarioResult) []CheckResult {
+	results := []CheckResult{}
+	for _, scenario := range scenarios {
+		for _, step := range scenario.Steps {
+			if !step.Skipped {
+				results = append(results, step.Result)
+			}
+		}
+	}
+	return results
+}
+
+func countResults(results []CheckResult, status Status) int {
+	count := 0
+	for _, result := range results {
+		if result.StatusCode == string(status) {
+			count++
+		}
+	}
+	return count
+}
+
+// runner holds the state shared by every request of a single run.
+type runner struct {
+	client  *http.Client
+	baseURL string
+	limiter *hostLimiter
+	retry   *RetryPolicy
+	auth    *authenticator
+}
+
+// redactResult masks any credentials that leaked into result messages,
+// such as a token echoed back in an error body.
+func (r *runner) redactResult(result *CheckResult) {
+	redactor := r.auth.redactor
+	result.Message = redactor.Redact(result.Message)
+	if result.Error != nil {
+		result.Error.Message = redactor.Redact(result.Error.Message)
+	}
+	for i := range result.Assertions {
+		result.Assertions[i].Message = redactor.Redact(result.Assertions[i].Message)
+	}
+}
+
+func (r *runner) redactScenario(scenario *ScenarioResult) {
+	scenario.Message = r.auth.redactor.Redact(scenario.Message)
+	for i := range scenario.Steps {
+		r.redactResult(&scenario.Steps[i].Result)
+	}
+}
+
+// attempt sends the request for a single endpoint and evaluates the
+// response against the expected status and any assertions.
+// Transport failures are recorded on the result with an ERROR status. The
+// response data is returned for callers that inspect it further, and is nil
+// if no response was received.
+func (r *runner) attempt(ctx context.Context, e Endpoint) (CheckResult, *responseData) {
+	result := CheckResult{
+		Path:           e.Path,
+		Method:         e.RequestMethod(),
+		ExpectedStatus: e.ExpectedStatus,
+	}
+	recorder := newTimingRecorder()
+	req, err := e.NewRequest(recorder.withTrace(ctx), r.baseURL)
+	if err != nil {
+		return result.withError(newCheckError(ErrorCategoryRequest, err)), nil
+	}
+	if err := r.auth.apply(req, e.Auth); err != nil {
+		return result.withError(newCheckError(ErrorCategoryAuth, err)), nil
+	}
+	if err := r.limiter.Wait(ctx, req.URL); err != nil {
+		if isCancelled(ctx) {
+			return result.cancelled(), nil
+		}
+		return result.withError(newCheckError(categorizeError(err), err)), nil
+	}
+	recorder.start = time.Now()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		result.Timings = recorder.timings(time.Now())
+		if isCancelled(ctx) {
+			return result.cancelled(), nil
+		}
+		return result.withError(newCheckError(categorizeError(err), err)), nil
+	}
+	defer resp.Body.Close()
+	result.ActualStatus = resp.StatusCode
+
+	// The body is always read so that the total time covers the full response
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodyBytes))
+	result.Timings = recorder.timings(time.Now())
+	if err != nil {
+		readErr := fmt.Errorf("failed to read response body from %s: %w", e.Path, err)
+		return result.withError(newCheckError(ErrorCategoryRead, readErr)), nil
+	}
+
+	result.StatusCode = string(StatusPass)
+	failures := []string{}
+	if resp.StatusCode != e.ExpectedStatus {
+		failures = append(failures, fmt.Sprintf("expected status %d, got %d", e.ExpectedStatus, resp.StatusCode))
+	}
+	if e.MaxResponseMs != nil && result.Timings.TotalMs > float64(*e.MaxResponseMs) {
+		failures = append(failures, fmt.Sprintf("response took %.0fms, exceeding max of %dms", result.Timings.TotalMs, *e.MaxResponseMs))
+	}
+	data := &responseData{header: resp.Header, body: body}
+	if len(e.Assertions) > 0 {
+		result.Assertions = evaluateAssertions(e.Assertions, data)
+		failures = append(failures, failedAssertionMessages(result.Assertions)...)
+	}
+	if len(failures) > 0 {
+		result.StatusCode = string(StatusFail)
+		result.Message = strings.Join(failures, "; ")
+	}
+	return result, data
+}
+
+func (r CheckResult) withError(checkErr *CheckError) CheckResult {
+	r.StatusCode = string(StatusError)
+	r.Error = checkErr
+	r.Message = fmt.Sprintf("%s error: %s", checkErr.Category, checkErr.Message)
+	return r
+}
+
+func (r CheckResult) cancelled() CheckResult {
+	r.StatusCode = string(StatusCancelled)
+	r.Error = nil
+	r.Message = "run was cancelled"
+	return r
+}
+
+func isCancelled(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.Canceled)
+}
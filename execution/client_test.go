@@ -2,8 +2,10 @@ package execution
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -33,7 +35,7 @@ func TestExecuteTestsSuccess(t *testing.T) {
 	}
 	results, err := ExecuteTests(context.Background(), request)
 	assert.NoError(t, err)
-	assert.Equal(t, "PASS", results.Status)
+	assert.Equal(t, StatusPass, results.Status)
 }
 
 func TestExecuteTestsStatusCodeDoNotMatch(t *testing.T) {
@@ -61,12 +63,48 @@ func TestExecuteTestsStatusCodeDoNotMatch(t *testing.T) {
 	}
 	results, err := ExecuteTests(context.Background(), request)
 	assert.NoError(t, err)
-	assert.Equal(t, "FAIL", results.Status)
+	assert.Equal(t, StatusFail, results.Status)
+}
+
+func TestExecuteTestsWithMethodHeadersAndBody(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users", func(res http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.Header.Get("X-Trace") != "abc" || req.URL.Query().Get("dry_run") != "true" {
+			res.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var payload map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil || payload["name"] != "aeternum" {
+			res.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		res.WriteHeader(http.StatusCreated)
+	})
+	mockServer := httptest.NewServer(mux)
+	defer mockServer.Close()
+	request := TestExecutionRequest{
+		BaseURL: mockServer.URL,
+		Endpoints: []Endpoint{
+			{
+				Path:           "/users",
+				Method:         http.MethodPost,
+				Headers:        map[string]string{"X-Trace": "abc"},
+				Query:          map[string]string{"dry_run": "true"},
+				Body:           &RequestBody{JSON: map[string]string{"name": "aeternum"}},
+				ExpectedStatus: http.StatusCreated,
+			},
+		},
+	}
+	results, err := ExecuteTests(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPass, results.Status)
+	assert.Equal(t, http.MethodPost, results.Results[0].Method)
 }
 
 func TestExecuteTestsFailedRequest(t *testing.T) {
 	mux := http.NewServeMux()
-	mockServer := httptest.NewUnstartedServer(mux)
+	mockServer := httptest.NewServer(mux)
+	mockServer.Close()
 	request := TestExecutionRequest{
 		BaseURL: mockServer.URL,
 		Endpoints: []Endpoint{
@@ -77,6 +115,122 @@ func TestExecuteTestsFailedRequest(t *testing.T) {
 		},
 		MaxTimeoutSeconds: nil,
 	}
-	_, err := ExecuteTests(context.Background(), request)
-	assert.Errorf(t, err, "Failed to make 1 requests")
+	results, err := ExecuteTests(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusError, results.Status)
+	assert.Equal(t, "ERROR", results.Results[0].StatusCode)
+	assert.Equal(t, ErrorCategoryConnect, results.Results[0].Error.Category)
+}
+
+func TestExecuteTestsPartialErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/broken", func(res http.ResponseWriter, req *http.Request) {
+		conn, _, err := res.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	})
+	mockServer := httptest.NewServer(mux)
+	defer mockServer.Close()
+	request := TestExecutionRequest{
+		BaseURL: mockServer.URL,
+		Endpoints: []Endpoint{
+			{
+				Path:           "/healthz",
+				ExpectedStatus: http.StatusOK,
+			},
+			{
+				Path:           "/broken",
+				ExpectedStatus: http.StatusOK,
+			},
+		},
+	}
+	results, err := ExecuteTests(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusError, results.Status)
+	assert.Equal(t, "PASS", results.Results[0].StatusCode)
+	assert.Equal(t, "ERROR", results.Results[1].StatusCode)
+	assert.Equal(t, ErrorCategoryRead, results.Results[1].Error.Category)
+}
+
+func TestExecuteTestsWithOptionsReportsEachResult(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	}))
+	defer mockServer.Close()
+	request := TestExecutionRequest{
+		BaseURL: mockServer.URL,
+		Endpoints: []Endpoint{
+			{Path: "/a", ExpectedStatus: http.StatusOK},
+			{Path: "/b", ExpectedStatus: http.StatusOK},
+		},
+		Scenarios: []Scenario{
+			{Name: "single", Steps: []ScenarioStep{{Endpoint: Endpoint{Path: "/c", ExpectedStatus: http.StatusOK}}}},
+		},
+	}
+
+	var mu sync.Mutex
+	paths := []string{}
+	scenarios := []string{}
+	response, err := ExecuteTestsWithOptions(context.Background(), request, RunOptions{
+		RequestID: "fixed-id",
+		OnResult: func(result CheckResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			paths = append(paths, result.Path)
+		},
+		OnScenario: func(scenario ScenarioResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			scenarios = append(scenarios, scenario.Name)
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "fixed-id", response.RequestID)
+	assert.ElementsMatch(t, []string{"/a", "/b"}, paths)
+	assert.Equal(t, []string{"single"}, scenarios)
+}
+
+func TestExecuteTestsCancelled(t *testing.T) {
+	release := make(chan struct{})
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/fast" {
+			res.WriteHeader(http.StatusOK)
+			return
+		}
+		select {
+		case <-release:
+		case <-req.Context().Done():
+		}
+	}))
+	defer mockServer.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	request := TestExecutionRequest{
+		BaseURL: mockServer.URL,
+		Endpoints: []Endpoint{
+			{Path: "/fast", ExpectedStatus: http.StatusOK},
+			{Path: "/slow", ExpectedStatus: http.StatusOK},
+			{Path: "/never", ExpectedStatus: http.StatusOK},
+		},
+		MaxConcurrency: intPtr(1),
+	}
+	response, err := ExecuteTestsWithOptions(ctx, request, RunOptions{
+		OnResult: func(result CheckResult) {
+			if result.Path == "/fast" {
+				cancel()
+			}
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusCancelled, response.Status)
+	assert.Equal(t, string(StatusPass), response.Results[0].StatusCode)
+	for _, result := range response.Results[1:] {
+		assert.Equal(t, string(StatusCancelled), result.StatusCode)
+		assert.Nil(t, result.Error)
+	}
 }
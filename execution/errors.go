@@ -0,0 +1,76 @@
+package execution
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+type ErrorCategory string
+
+const (
+	ErrorCategoryDNS     ErrorCategory = "dns"
+	ErrorCategoryConnect ErrorCategory = "connect"
+	ErrorCategoryTLS     ErrorCategory = "tls"
+	ErrorCategoryTimeout ErrorCategory = "timeout"
+	ErrorCategoryRead    ErrorCategory = "read"
+	ErrorCategoryRequest ErrorCategory = "request"
+	ErrorCategoryAuth    ErrorCategory = "auth"
+	ErrorCategoryUnknown ErrorCategory = "unknown"
+)
+
+// CheckError describes a transport failure that prevented an endpoint
+// from being checked.
+type CheckError struct {
+	Category ErrorCategory `json:"category"`
+	Message  string        `json:"message"`
+}
+
+func newCheckError(category ErrorCategory, err error) *CheckError {
+	return &CheckError{Category: category, Message: err.Error()}
+}
+
+// categorizeError maps an error returned by the HTTP client onto the
+// stage of the request that failed.
+func categorizeError(err error) ErrorCategory {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrorCategoryTimeout
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return ErrorCategoryDNS
+	}
+
+	var recordErr tls.RecordHeaderError
+	var verifyErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	if errors.As(err, &recordErr) || errors.As(err, &verifyErr) || errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) {
+		return ErrorCategoryTLS
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		switch opErr.Op {
+		case "dial":
+			return ErrorCategoryConnect
+		case "read":
+			return ErrorCategoryRead
+		}
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return ErrorCategoryConnect
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrorCategoryRead
+	}
+	return ErrorCategoryUnknown
+}
@@ -0,0 +1,69 @@
+package execution
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategorizeError(t *testing.T) {
+	wrap := func(err error) error {
+		return &url.Error{Op: "Get", URL: "https://example.com", Err: err}
+	}
+	tests := []struct {
+		name     string
+		err      error
+		expected ErrorCategory
+	}{
+		{
+			name:     "DNS lookup failure",
+			err:      wrap(&net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "example.com"}}),
+			expected: ErrorCategoryDNS,
+		},
+		{
+			name:     "connection refused",
+			err:      wrap(&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}),
+			expected: ErrorCategoryConnect,
+		},
+		{
+			name:     "unknown certificate authority",
+			err:      wrap(x509.UnknownAuthorityError{}),
+			expected: ErrorCategoryTLS,
+		},
+		{
+			name:     "context deadline",
+			err:      wrap(context.DeadlineExceeded),
+			expected: ErrorCategoryTimeout,
+		},
+		{
+			name:     "connection closed",
+			err:      wrap(io.EOF),
+			expected: ErrorCategoryRead,
+		},
+		{
+			name:     "read failure",
+			err:      wrap(&net.OpError{Op: "read", Err: syscall.ECONNRESET}),
+			expected: ErrorCategoryRead,
+		},
+		{
+			name:     "other",
+			err:      errors.New("something else"),
+			expected: ErrorCategoryUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, categorizeError(fmt.Errorf("request failed: %w", tt.err)))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package execution
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	"github.com/jgfranco17/aeternum/api/environment"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultMaxConcurrency = 50
+)
+
+// ExecutionLimits bounds how hard a run may hit its target. A zero
+// RequestsPerSecond means requests are not rate limited.
+type ExecutionLimits struct {
+	MaxConcurrency    int
+	RequestsPerSecond int
+}
+
+// LimitsFromEnvironment returns the server-side ceiling applied to every
+// run, which users cannot override upward.
+func LimitsFromEnvironment() ExecutionLimits {
+	return ExecutionLimits{
+		MaxConcurrency:    environment.GetIntEnvWithDefault(environment.ENV_KEY_MAX_CONCURRENCY, defaultMaxConcurrency),
+		RequestsPerSecond: environment.GetIntEnvWithDefault(environment.ENV_KEY_MAX_REQUESTS_PER_SECOND, 0),
+	}
+}
+
+// effectiveLimits combines the options requested by the user with the
+// server-side ceiling, always choosing the stricter of the two.
+func (r TestExecutionRequest) effectiveLimits(ceiling ExecutionLimits) ExecutionLimits {
+	limits := ceiling
+	if limits.MaxConcurrency <= 0 {
+		limits.MaxConcurrency = defaultMaxConcurrency
+	}
+	if r.MaxConcurrency != nil && *r.MaxConcurrency < limits.MaxConcurrency {
+		limits.MaxConcurrency = *r.MaxConcurrency
+	}
+	if r.RequestsPerSecond != nil {
+		if limits.RequestsPerSecond <= 0 || *r.RequestsPerSecond < limits.RequestsPerSecond {
+			limits.RequestsPerSecond = *r.RequestsPerSecond
+		}
+	}
+	return limits
+}
+
+// hostLimiter applies a token bucket per target host.
+type hostLimiter struct {
+	mu       sync.Mutex
+	perHost  map[string]*rate.Limiter
+	rps      int
+	disabled bool
+}
+
+func newHostLimiter(requestsPerSecond int) *hostLimiter {
+	return &hostLimiter{
+		perHost:  map[string]*rate.Limiter{},
+		rps:      requestsPerSecond,
+		disabled: requestsPerSecond <= 0,
+	}
+}
+
+// Wait blocks until a request to the given URL is allowed to proceed.
+func (l *hostLimiter) Wait(ctx context.Context, target *url.URL) error {
+	if l == nil || l.disabled {
+		return nil
+	}
+	l.mu.Lock()
+	limiter, ok := l.perHost[target.Host]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(l.rps), 1)
+		l.perHost[target.Host] = limiter
+	}
+	l.mu.Unlock()
+	return limiter.Wait(ctx)
+}
+
+// runWorkerPool runs every job using at most the given number of workers.
+func runWorkerPool(workers int, jobs []func()) {
+	if workers <= 0 || workers > len(jobs) {
+		workers = len(jobs)
+	}
+	queue := make(chan func())
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				job()
+			}
+		}()
+	}
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+}
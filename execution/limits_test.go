@@ -0,0 +1,123 @@
+package execution
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestEffectiveLimits(t *testing.T) {
+	tests := []struct {
+		name           string
+		request        TestExecutionRequest
+		ceiling        ExecutionLimits
+		expectedLimits ExecutionLimits
+	}{
+		{
+			name:           "defaults to ceiling",
+			request:        TestExecutionRequest{},
+			ceiling:        ExecutionLimits{MaxConcurrency: 20, RequestsPerSecond: 0},
+			expectedLimits: ExecutionLimits{MaxConcurrency: 20, RequestsPerSecond: 0},
+		},
+		{
+			name:           "user values below ceiling",
+			request:        TestExecutionRequest{MaxConcurrency: intPtr(5), RequestsPerSecond: intPtr(10)},
+			ceiling:        ExecutionLimits{MaxConcurrency: 20, RequestsPerSecond: 50},
+			expectedLimits: ExecutionLimits{MaxConcurrency: 5, RequestsPerSecond: 10},
+		},
+		{
+			name:           "user cannot exceed ceiling",
+			request:        TestExecutionRequest{MaxConcurrency: intPtr(500), RequestsPerSecond: intPtr(1000)},
+			ceiling:        ExecutionLimits{MaxConcurrency: 20, RequestsPerSecond: 50},
+			expectedLimits: ExecutionLimits{MaxConcurrency: 20, RequestsPerSecond: 50},
+		},
+		{
+			name:           "user rate limit without ceiling",
+			request:        TestExecutionRequest{RequestsPerSecond: intPtr(3)},
+			ceiling:        ExecutionLimits{MaxConcurrency: 20},
+			expectedLimits: ExecutionLimits{MaxConcurrency: 20, RequestsPerSecond: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedLimits, tt.request.effectiveLimits(tt.ceiling))
+		})
+	}
+}
+
+func TestLimitsFromEnvironment(t *testing.T) {
+	t.Setenv("AETERNUM_MAX_CONCURRENCY", "8")
+	t.Setenv("AETERNUM_MAX_REQUESTS_PER_SECOND", "30")
+	assert.Equal(t, ExecutionLimits{MaxConcurrency: 8, RequestsPerSecond: 30}, LimitsFromEnvironment())
+}
+
+func TestExecuteTestsRespectsConcurrencyLimit(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(res http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		res.WriteHeader(http.StatusOK)
+	})
+	mockServer := httptest.NewServer(mux)
+	defer mockServer.Close()
+
+	endpoints := []Endpoint{}
+	for range 10 {
+		endpoints = append(endpoints, Endpoint{Path: "/slow", ExpectedStatus: http.StatusOK})
+	}
+	request := TestExecutionRequest{
+		BaseURL:        mockServer.URL,
+		Endpoints:      endpoints,
+		MaxConcurrency: intPtr(2),
+	}
+	results, err := ExecuteTests(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPass, results.Status)
+	assert.LessOrEqual(t, peak, 2)
+}
+
+func TestExecuteTestsRespectsRateLimit(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	})
+	mockServer := httptest.NewServer(mux)
+	defer mockServer.Close()
+
+	endpoints := []Endpoint{}
+	for range 5 {
+		endpoints = append(endpoints, Endpoint{Path: "/healthz", ExpectedStatus: http.StatusOK})
+	}
+	request := TestExecutionRequest{
+		BaseURL:           mockServer.URL,
+		Endpoints:         endpoints,
+		RequestsPerSecond: intPtr(20),
+	}
+	start := time.Now()
+	results, err := ExecuteTests(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPass, results.Status)
+	// 5 requests at 20/s with a burst of 1 need at least 4 intervals of 50ms
+	assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+}
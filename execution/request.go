@@ -0,0 +1,108 @@
+package execution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RequestBody describes the payload sent along with an endpoint request.
+// Exactly one of Raw, JSON or Form should be set.
+type RequestBody struct {
+	Raw         string            `json:"raw,omitempty"`
+	JSON        interface{}       `json:"json,omitempty"`
+	Form        map[string]string `json:"form,omitempty"`
+	ContentType string            `json:"content_type,omitempty"`
+}
+
+// Encode serializes the body and returns the reader along with the
+// content type that should be sent with it.
+func (b *RequestBody) Encode() (io.Reader, string, error) {
+	setCount := 0
+	if b.Raw != "" {
+		setCount++
+	}
+	if b.JSON != nil {
+		setCount++
+	}
+	if len(b.Form) > 0 {
+		setCount++
+	}
+	if setCount > 1 {
+		return nil, "", fmt.Errorf("only one of raw, json or form may be set in a request body")
+	}
+
+	switch {
+	case b.JSON != nil:
+		data, err := json.Marshal(b.JSON)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to encode JSON body: %w", err)
+		}
+		return bytes.NewReader(data), withDefault(b.ContentType, "application/json"), nil
+	case len(b.Form) > 0:
+		form := url.Values{}
+		for key, value := range b.Form {
+			form.Set(key, value)
+		}
+		return strings.NewReader(form.Encode()), withDefault(b.ContentType, "application/x-www-form-urlencoded"), nil
+	case b.Raw != "":
+		return strings.NewReader(b.Raw), withDefault(b.ContentType, "text/plain"), nil
+	}
+	return nil, "", nil
+}
+
+// RequestMethod returns the HTTP method for the endpoint, defaulting to GET.
+func (e Endpoint) RequestMethod() string {
+	if e.Method == "" {
+		return http.MethodGet
+	}
+	return strings.ToUpper(e.Method)
+}
+
+// NewRequest builds the outbound HTTP request for an endpoint.
+func (e Endpoint) NewRequest(ctx context.Context, baseURL string) (*http.Request, error) {
+	fullURL, err := url.Parse(baseURL + e.Path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL for path %s: %w", e.Path, err)
+	}
+	if len(e.Query) > 0 {
+		query := fullURL.Query()
+		for key, value := range e.Query {
+			query.Set(key, value)
+		}
+		fullURL.RawQuery = query.Encode()
+	}
+
+	var body io.Reader
+	contentType := ""
+	if e.Body != nil {
+		body, contentType, err = e.Body.Encode()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, e.RequestMethod(), fullURL.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", e.Path, err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for key, value := range e.Headers {
+		req.Header.Set(key, value)
+	}
+	return req, nil
+}
+
+func withDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
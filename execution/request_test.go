@@ -0,0 +1,81 @@
+package execution
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestBodyEncode(t *testing.T) {
+	tests := []struct {
+		name                string
+		body                RequestBody
+		expectedContent     string
+		expectedContentType string
+	}{
+		{
+			name:                "raw body",
+			body:                RequestBody{Raw: "hello"},
+			expectedContent:     "hello",
+			expectedContentType: "text/plain",
+		},
+		{
+			name:                "raw body with content type",
+			body:                RequestBody{Raw: "<a/>", ContentType: "application/xml"},
+			expectedContent:     "<a/>",
+			expectedContentType: "application/xml",
+		},
+		{
+			name:                "JSON body",
+			body:                RequestBody{JSON: map[string]int{"count": 2}},
+			expectedContent:     `{"count":2}`,
+			expectedContentType: "application/json",
+		},
+		{
+			name:                "form body",
+			body:                RequestBody{Form: map[string]string{"a": "1", "b": "x y"}},
+			expectedContent:     "a=1&b=x+y",
+			expectedContentType: "application/x-www-form-urlencoded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, contentType, err := tt.body.Encode()
+			require.NoError(t, err)
+			content, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedContent, string(content))
+			assert.Equal(t, tt.expectedContentType, contentType)
+		})
+	}
+}
+
+func TestRequestBodyEncodeMultipleFields(t *testing.T) {
+	body := RequestBody{Raw: "hello", Form: map[string]string{"a": "1"}}
+	_, _, err := body.Encode()
+	assert.ErrorContains(t, err, "only one of raw, json or form")
+}
+
+func TestEndpointNewRequest(t *testing.T) {
+	endpoint := Endpoint{
+		Path:    "/items?sort=asc",
+		Method:  "delete",
+		Headers: map[string]string{"X-Api-Version": "2"},
+		Query:   map[string]string{"force": "true"},
+	}
+	req, err := endpoint.NewRequest(context.Background(), "https://example.com/api")
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodDelete, req.Method)
+	assert.Equal(t, "https://example.com/api/items?force=true&sort=asc", req.URL.String())
+	assert.Equal(t, "2", req.Header.Get("X-Api-Version"))
+	assert.Empty(t, req.Header.Get("Content-Type"))
+}
+
+func TestEndpointDefaultMethod(t *testing.T) {
+	assert.Equal(t, http.MethodGet, Endpoint{Path: "/"}.RequestMethod())
+}
@@ -0,0 +1,110 @@
+package execution
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+const (
+	defaultInitialBackoffMs = 200
+	defaultMaxBackoffMs     = 5000
+)
+
+// RetryPolicy controls how a failed endpoint check is retried. Only
+// transport errors and the listed status codes are retried; failing
+// assertions are not.
+type RetryPolicy struct {
+	MaxAttempts      int   `json:"max_attempts" binding:"required,min=1,max=10"`
+	RetryOnStatus    []int `json:"retry_on_status,omitempty"`
+	RetryOnError     bool  `json:"retry_on_error,omitempty"`
+	InitialBackoffMs int   `json:"initial_backoff_ms,omitempty" binding:"omitempty,min=1"`
+	MaxBackoffMs     int   `json:"max_backoff_ms,omitempty" binding:"omitempty,min=1"`
+}
+
+// Attempt records the outcome of a single try of an endpoint check.
+type Attempt struct {
+	Number       int           `json:"number"`
+	ActualStatus int           `json:"actual_status,omitempty"`
+	Error        ErrorCategory `json:"error,omitempty"`
+}
+
+func (p *RetryPolicy) shouldRetry(result CheckResult) bool {
+	if result.Error != nil {
+		return p.RetryOnError && result.Error.Category != ErrorCategoryRequest
+	}
+	return slices.Contains(p.RetryOnStatus, result.ActualStatus)
+}
+
+// backoff returns the delay before the given retry, growing exponentially
+// from the initial backoff up to the maximum, with jitter applied so that
+// concurrent retries do not line up.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	initial := p.InitialBackoffMs
+	if initial <= 0 {
+		initial = defaultInitialBackoffMs
+	}
+	maximum := p.MaxBackoffMs
+	if maximum <= 0 {
+		maximum = defaultMaxBackoffMs
+	}
+	delay := time.Duration(initial) * time.Millisecond
+	for i := 1; i < retry && delay < time.Duration(maximum)*time.Millisecond; i++ {
+		delay *= 2
+	}
+	delay = min(delay, time.Duration(maximum)*time.Millisecond)
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+func newAttempt(number int, result CheckResult) Attempt {
+	attempt := Attempt{Number: number, ActualStatus: result.ActualStatus}
+	if result.Error != nil {
+		attempt.Error = result.Error.Category
+	}
+	return attempt
+}
+
+// checkEndpoint runs an endpoint check, retrying according to the
+// endpoint's retry policy or the run-wide default.
+func (r *runner) checkEndpoint(ctx context.Context, e Endpoint) (CheckResult, *responseData) {
+	policy := e.Retry
+	if policy == nil {
+		policy = r.retry
+	}
+	if isCancelled(ctx) {
+		result := CheckResult{Path: e.Path, Method: e.RequestMethod(), ExpectedStatus: e.ExpectedStatus}
+		return result.cancelled(), nil
+	}
+	result, data := r.attempt(ctx, e)
+	if policy == nil || policy.MaxAttempts <= 1 {
+		result.Attempts = 1
+		return result, data
+	}
+
+	history := []Attempt{newAttempt(1, result)}
+	for number := 2; number <= policy.MaxAttempts && policy.shouldRetry(result); number++ {
+		timer := time.NewTimer(policy.backoff(number - 1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			result.Attempts = len(history)
+			result.AttemptHistory = history
+			// A run cancelled before the retry is not a failure of the
+			// endpoint, so the previous attempt is not reported
+			if isCancelled(ctx) {
+				return result.cancelled(), nil
+			}
+			return result, data
+		case <-timer.C:
+		}
+		result, data = r.attempt(ctx, e)
+		history = append(history, newAttempt(number, result))
+	}
+	result.Attempts = len(history)
+	if len(history) > 1 {
+		result.AttemptHistory = history
+	}
+	return result, data
+}
@@ -0,0 +1,133 @@
+package execution
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoffMs: 100, MaxBackoffMs: 1000}
+	tests := []struct {
+		retry   int
+		maximum time.Duration
+	}{
+		{retry: 1, maximum: 100 * time.Millisecond},
+		{retry: 2, maximum: 200 * time.Millisecond},
+		{retry: 3, maximum: 400 * time.Millisecond},
+		{retry: 10, maximum: 1000 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		delay := policy.backoff(tt.retry)
+		assert.LessOrEqual(t, delay, tt.maximum)
+		assert.GreaterOrEqual(t, delay, tt.maximum/2)
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, RetryOnStatus: []int{502, 503}, RetryOnError: true}
+	assert.True(t, policy.shouldRetry(CheckResult{ActualStatus: 503}))
+	assert.False(t, policy.shouldRetry(CheckResult{ActualStatus: 500}))
+	assert.True(t, policy.shouldRetry(CheckResult{Error: &CheckError{Category: ErrorCategoryConnect}}))
+	assert.False(t, policy.shouldRetry(CheckResult{Error: &CheckError{Category: ErrorCategoryRequest}}))
+}
+
+func TestExecuteTestsRetriesFlakyEndpoint(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/flaky", func(res http.ResponseWriter, req *http.Request) {
+		if calls.Add(1) < 3 {
+			res.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		res.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/dead", func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusBadGateway)
+	})
+	mockServer := httptest.NewServer(mux)
+	defer mockServer.Close()
+
+	request := TestExecutionRequest{
+		BaseURL: mockServer.URL,
+		Endpoints: []Endpoint{
+			{Path: "/flaky", ExpectedStatus: http.StatusOK},
+			{
+				Path:           "/dead",
+				ExpectedStatus: http.StatusOK,
+				Retry:          &RetryPolicy{MaxAttempts: 2, RetryOnStatus: []int{502}, InitialBackoffMs: 1},
+			},
+		},
+		Retry: &RetryPolicy{MaxAttempts: 4, RetryOnStatus: []int{502, 503, 504}, InitialBackoffMs: 1},
+	}
+	results, err := ExecuteTests(context.Background(), request)
+	require.NoError(t, err)
+
+	flaky := results.Results[0]
+	assert.Equal(t, "PASS", flaky.StatusCode)
+	assert.Equal(t, 3, flaky.Attempts)
+	assert.Equal(t, []Attempt{
+		{Number: 1, ActualStatus: 503},
+		{Number: 2, ActualStatus: 503},
+		{Number: 3, ActualStatus: 200},
+	}, flaky.AttemptHistory)
+
+	dead := results.Results[1]
+	assert.Equal(t, "FAIL", dead.StatusCode)
+	assert.Equal(t, 2, dead.Attempts)
+	assert.Len(t, dead.AttemptHistory, 2)
+}
+
+func TestExecuteTestsRetriesTransportErrors(t *testing.T) {
+	mockServer := httptest.NewServer(http.NewServeMux())
+	mockServer.Close()
+
+	request := TestExecutionRequest{
+		BaseURL:   mockServer.URL,
+		Endpoints: []Endpoint{{Path: "/home", ExpectedStatus: http.StatusOK}},
+		Retry:     &RetryPolicy{MaxAttempts: 3, RetryOnError: true, InitialBackoffMs: 1},
+	}
+	results, err := ExecuteTests(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, StatusError, results.Status)
+	assert.Equal(t, 3, results.Results[0].Attempts)
+	assert.Equal(t, ErrorCategoryConnect, results.Results[0].AttemptHistory[2].Error)
+}
+
+func TestExecuteTestsCancelledDuringBackoff(t *testing.T) {
+	attempted := make(chan struct{}, 1)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusServiceUnavailable)
+		attempted <- struct{}{}
+	}))
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-attempted
+		// Leave the first attempt time to complete before the run is cancelled
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	request := TestExecutionRequest{
+		BaseURL:   mockServer.URL,
+		Endpoints: []Endpoint{{Path: "/flaky", ExpectedStatus: http.StatusOK}},
+		Retry:     &RetryPolicy{MaxAttempts: 3, RetryOnStatus: []int{503}, InitialBackoffMs: 10000},
+	}
+	results, err := ExecuteTests(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCancelled, results.Status)
+	result := results.Results[0]
+	assert.Equal(t, string(StatusCancelled), result.StatusCode)
+	assert.Equal(t, "run was cancelled", result.Message)
+	assert.Equal(t, 1, result.Attempts)
+	assert.Equal(t, []Attempt{{Number: 1, ActualStatus: 503}}, result.AttemptHistory)
+}
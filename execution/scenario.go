@@ -0,0 +1,293 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PaesslerAG/jsonpath"
+)
+
+type FailurePolicy string
+
+const (
+	FailurePolicyStop     FailurePolicy = "stop"
+	FailurePolicyContinue FailurePolicy = "continue"
+)
+
+type ExtractionSource string
+
+const (
+	ExtractFromJSONPath ExtractionSource = "jsonpath"
+	ExtractFromHeader   ExtractionSource = "header"
+	ExtractFromRegex    ExtractionSource = "regex"
+)
+
+// Extraction captures a value from a step response into a named variable.
+// For regex extractions, the first capture group is used if the pattern
+// has one, otherwise the whole match.
+type Extraction struct {
+	Name    string           `json:"name" binding:"required"`
+	Source  ExtractionSource `json:"source" binding:"required,oneof=jsonpath header regex"`
+	Path    string           `json:"path,omitempty"`
+	Header  string           `json:"header,omitempty"`
+	Pattern string           `json:"pattern,omitempty"`
+}
+
+// ScenarioStep is an endpoint check that runs as part of a scenario.
+// The path, query, headers and body may reference variables as {{name}}.
+type ScenarioStep struct {
+	Name string `json:"name,omitempty"`
+	Endpoint
+	Extract []Extraction `json:"extract,omitempty" binding:"omitempty,dive"`
+}
+
+// Scenario is an ordered list of steps that share variables.
+type Scenario struct {
+	Name      string            `json:"name" binding:"required"`
+	Steps     []ScenarioStep    `json:"steps" binding:"required,min=1,dive"`
+	Variables map[string]string `json:"variables,omitempty"`
+	OnFailure FailurePolicy     `json:"on_failure,omitempty" binding:"omitempty,oneof=stop continue"`
+}
+
+// StepResult represents the outcome of a single scenario step.
+type StepResult struct {
+	Index     int         `json:"index"`
+	Name      string      `json:"name,omitempty"`
+	Skipped   bool        `json:"skipped,omitempty"`
+	Extracted []string    `json:"extracted,omitempty"`
+	Result    CheckResult `json:"result"`
+}
+
+// ScenarioResult represents the outcome of a full scenario.
+type ScenarioResult struct {
+	Name       string       `json:"name"`
+	Status     Status       `json:"status"`
+	FailedStep *int         `json:"failed_step,omitempty"`
+	Message    string       `json:"message,omitempty"`
+	Steps      []StepResult `json:"steps"`
+}
+
+var templateVariable = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// runScenario executes the steps of a scenario in order, threading any
+// extracted variables into later steps.
+func (r *runner) runScenario(ctx context.Context, scenario Scenario) ScenarioResult {
+	variables := map[string]string{}
+	for key, value := range scenario.Variables {
+		variables[key] = value
+	}
+	policy := scenario.OnFailure
+	if policy == "" {
+		policy = FailurePolicyStop
+	}
+
+	result := ScenarioResult{
+		Name:   scenario.Name,
+		Status: StatusPass,
+		Steps:  make([]StepResult, len(scenario.Steps)),
+	}
+	stopped := false
+	for i, step := range scenario.Steps {
+		stepResult := StepResult{Index: i, Name: step.Name}
+		if stopped {
+			stepResult.Skipped = true
+			stepResult.Result = CheckResult{
+				Path:           step.Path,
+				Method:         step.RequestMethod(),
+				ExpectedStatus: step.ExpectedStatus,
+				StatusCode:     string(StatusSkipped),
+			}
+			result.Steps[i] = stepResult
+			continue
+		}
+
+		stepResult.Result, stepResult.Extracted = r.runStep(ctx, step, variables)
+		result.Steps[i] = stepResult
+
+		if stepResult.Result.StatusCode != string(StatusPass) {
+			if result.FailedStep == nil {
+				index := i
+				result.FailedStep = &index
+			}
+			result.Status = worseStatus(result.Status, Status(stepResult.Result.StatusCode))
+			if policy == FailurePolicyStop {
+				stopped = true
+			}
+		}
+	}
+	result.Message = describeScenarioFailure(result)
+	return result
+}
+
+func (r *runner) runStep(ctx context.Context, step ScenarioStep, variables map[string]string) (CheckResult, []string) {
+	endpoint, err := step.Endpoint.withVariables(variables)
+	if err != nil {
+		result := CheckResult{
+			Path:           step.Path,
+			Method:         step.RequestMethod(),
+			ExpectedStatus: step.ExpectedStatus,
+		}
+		return result.withError(newCheckError(ErrorCategoryRequest, err)), nil
+	}
+
+	result, data := r.checkEndpoint(ctx, endpoint)
+	if result.StatusCode != string(StatusPass) || len(step.Extract) == 0 {
+		return result, nil
+	}
+
+	extracted := []string{}
+	for _, extraction := range step.Extract {
+		value, err := extraction.extract(data)
+		if err != nil {
+			result.StatusCode = string(StatusFail)
+			result.Message = fmt.Sprintf("failed to extract '%s': %v", extraction.Name, err)
+			return result, extracted
+		}
+		variables[extraction.Name] = value
+		extracted = append(extracted, extraction.Name)
+	}
+	return result, extracted
+}
+
+func (x Extraction) extract(data *responseData) (string, error) {
+	switch x.Source {
+	case ExtractFromJSONPath:
+		document, err := data.json()
+		if err != nil {
+			return "", fmt.Errorf("response body is not valid JSON: %w", err)
+		}
+		value, err := jsonpath.Get(x.Path, document)
+		if err != nil {
+			return "", fmt.Errorf("%s not found in response: %w", x.Path, err)
+		}
+		if text, ok := value.(string); ok {
+			return text, nil
+		}
+		return formatJSON(value), nil
+	case ExtractFromHeader:
+		value := data.header.Get(x.Header)
+		if value == "" {
+			return "", fmt.Errorf("header %s not found in response", x.Header)
+		}
+		return value, nil
+	case ExtractFromRegex:
+		pattern, err := regexp.Compile(x.Pattern)
+		if err != nil {
+			return "", fmt.Errorf("invalid regex '%s': %w", x.Pattern, err)
+		}
+		match := pattern.FindSubmatch(data.body)
+		if match == nil {
+			return "", fmt.Errorf("response body does not match regex '%s'", x.Pattern)
+		}
+		if len(match) > 1 {
+			return string(match[1]), nil
+		}
+		return string(match[0]), nil
+	}
+	return "", fmt.Errorf("unsupported extraction source '%s'", x.Source)
+}
+
+// withVariables returns a copy of the endpoint with {{name}} placeholders
+// replaced in its path, query, headers, body and static credentials.
+func (e Endpoint) withVariables(variables map[string]string) (Endpoint, error) {
+	var renderErr error
+	render := func(text string) string {
+		return templateVariable.ReplaceAllStringFunc(text, func(match string) string {
+			name := templateVariable.FindStringSubmatch(match)[1]
+			value, ok := variables[name]
+			if !ok && renderErr == nil {
+				renderErr = fmt.Errorf("undefined variable '%s'", name)
+			}
+			return value
+		})
+	}
+
+	rendered := e
+	rendered.Path = render(e.Path)
+	rendered.Query = renderMap(e.Query, render)
+	rendered.Headers = renderMap(e.Headers, render)
+	if e.Auth != nil {
+		auth := *e.Auth
+		auth.Token = render(auth.Token)
+		auth.Username = render(auth.Username)
+		auth.Password = render(auth.Password)
+		auth.APIKey = render(auth.APIKey)
+		rendered.Auth = &auth
+	}
+	if e.Body != nil {
+		body := *e.Body
+		body.Raw = render(body.Raw)
+		body.Form = renderMap(body.Form, render)
+		if body.JSON != nil {
+			renderedJSON, err := renderJSON(body.JSON, render)
+			if err != nil {
+				return e, err
+			}
+			body.JSON = renderedJSON
+		}
+		rendered.Body = &body
+	}
+	return rendered, renderErr
+}
+
+func renderMap(values map[string]string, render func(string) string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	rendered := make(map[string]string, len(values))
+	for key, value := range values {
+		rendered[key] = render(value)
+	}
+	return rendered
+}
+
+// renderJSON applies the template to every string in a JSON document.
+func renderJSON(value interface{}, render func(string) string) (interface{}, error) {
+	normalized, err := normalizeJSON(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %w", err)
+	}
+	var walk func(node interface{}) interface{}
+	walk = func(node interface{}) interface{} {
+		switch typed := node.(type) {
+		case string:
+			return render(typed)
+		case map[string]interface{}:
+			for key, child := range typed {
+				typed[key] = walk(child)
+			}
+			return typed
+		case []interface{}:
+			for i, child := range typed {
+				typed[i] = walk(child)
+			}
+			return typed
+		}
+		return node
+	}
+	return json.RawMessage(formatJSON(walk(normalized))), nil
+}
+
+// worseStatus returns the more severe of two statuses.
+func worseStatus(current Status, next Status) Status {
+	severity := map[Status]int{StatusPass: 0, StatusFail: 1, StatusError: 2, StatusCancelled: 3}
+	if severity[next] > severity[current] {
+		return next
+	}
+	return current
+}
+
+func describeScenarioFailure(result ScenarioResult) string {
+	if result.FailedStep == nil {
+		return ""
+	}
+	step := result.Steps[*result.FailedStep]
+	name := step.Name
+	if strings.TrimSpace(name) == "" {
+		name = fmt.Sprintf("%s %s", step.Result.Method, step.Result.Path)
+	}
+	return fmt.Sprintf("scenario '%s' broke at step %d (%s): %s", result.Name, step.Index, name, step.Result.Message)
+}
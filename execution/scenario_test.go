@@ -0,0 +1,181 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newResourceServer serves a tiny in-memory CRUD API for /items.
+func newResourceServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	items := map[string]string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /items", func(res http.ResponseWriter, req *http.Request) {
+		var payload map[string]string
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			res.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		items["42"] = payload["name"]
+		mu.Unlock()
+		res.Header().Set("Location", "/items/42")
+		res.WriteHeader(http.StatusCreated)
+		res.Write([]byte(`{"id": 42, "name": "` + payload["name"] + `"}`))
+	})
+	mux.HandleFunc("GET /items/{id}", func(res http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		name, ok := items[req.PathValue("id")]
+		mu.Unlock()
+		if !ok {
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+		res.WriteHeader(http.StatusOK)
+		res.Write([]byte(`{"name": "` + name + `"}`))
+	})
+	mux.HandleFunc("DELETE /items/{id}", func(res http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		delete(items, req.PathValue("id"))
+		mu.Unlock()
+		res.WriteHeader(http.StatusNoContent)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestRunner(server *httptest.Server) *runner {
+	return &runner{client: server.Client(), baseURL: server.URL}
+}
+
+func createReadDeleteScenario() Scenario {
+	return Scenario{
+		Name:      "item lifecycle",
+		Variables: map[string]string{"item_name": "widget"},
+		Steps: []ScenarioStep{
+			{
+				Name: "create",
+				Endpoint: Endpoint{
+					Path:           "/items",
+					Method:         http.MethodPost,
+					Body:           &RequestBody{JSON: map[string]string{"name": "{{item_name}}"}},
+					ExpectedStatus: http.StatusCreated,
+				},
+				Extract: []Extraction{
+					{Name: "id", Source: ExtractFromJSONPath, Path: "$.id"},
+					{Name: "location", Source: ExtractFromHeader, Header: "Location"},
+				},
+			},
+			{
+				Name: "read",
+				Endpoint: Endpoint{
+					Path:           "/items/{{id}}",
+					ExpectedStatus: http.StatusOK,
+					Assertions: []Assertion{
+						{Type: AssertionJSONPath, Path: "$.name", Value: "widget"},
+					},
+				},
+			},
+			{
+				Name: "delete",
+				Endpoint: Endpoint{
+					Path:           "{{location}}",
+					Method:         http.MethodDelete,
+					ExpectedStatus: http.StatusNoContent,
+				},
+			},
+		},
+	}
+}
+
+func TestRunScenarioChainsVariables(t *testing.T) {
+	server := newResourceServer(t)
+	result := newTestRunner(server).runScenario(context.Background(), createReadDeleteScenario())
+
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Nil(t, result.FailedStep)
+	require.Len(t, result.Steps, 3)
+	assert.Equal(t, []string{"id", "location"}, result.Steps[0].Extracted)
+	assert.Equal(t, "/items/42", result.Steps[1].Result.Path)
+	assert.Equal(t, "/items/42", result.Steps[2].Result.Path)
+}
+
+func TestRunScenarioStopsOnFailure(t *testing.T) {
+	server := newResourceServer(t)
+	scenario := createReadDeleteScenario()
+	scenario.Steps[0].ExpectedStatus = http.StatusOK
+
+	result := newTestRunner(server).runScenario(context.Background(), scenario)
+	assert.Equal(t, StatusFail, result.Status)
+	require.NotNil(t, result.FailedStep)
+	assert.Equal(t, 0, *result.FailedStep)
+	assert.Contains(t, result.Message, "broke at step 0 (create)")
+	assert.True(t, result.Steps[1].Skipped)
+	assert.Equal(t, "SKIPPED", result.Steps[2].Result.StatusCode)
+}
+
+func TestRunScenarioContinuesOnFailure(t *testing.T) {
+	server := newResourceServer(t)
+	scenario := createReadDeleteScenario()
+	scenario.OnFailure = FailurePolicyContinue
+	scenario.Steps[1].Assertions[0].Value = "gadget"
+
+	result := newTestRunner(server).runScenario(context.Background(), scenario)
+	assert.Equal(t, StatusFail, result.Status)
+	require.NotNil(t, result.FailedStep)
+	assert.Equal(t, 1, *result.FailedStep)
+	assert.False(t, result.Steps[2].Skipped)
+	assert.Equal(t, "PASS", result.Steps[2].Result.StatusCode)
+}
+
+func TestRunScenarioUndefinedVariable(t *testing.T) {
+	server := newResourceServer(t)
+	scenario := Scenario{
+		Name: "missing variable",
+		Steps: []ScenarioStep{
+			{Endpoint: Endpoint{Path: "/items/{{unknown}}", ExpectedStatus: http.StatusOK}},
+		},
+	}
+	result := newTestRunner(server).runScenario(context.Background(), scenario)
+	assert.Equal(t, StatusError, result.Status)
+	assert.Equal(t, ErrorCategoryRequest, result.Steps[0].Result.Error.Category)
+	assert.Contains(t, result.Steps[0].Result.Message, "undefined variable 'unknown'")
+}
+
+func TestExecuteTestsWithScenarios(t *testing.T) {
+	server := newResourceServer(t)
+	request := TestExecutionRequest{
+		BaseURL:   server.URL,
+		Scenarios: []Scenario{createReadDeleteScenario()},
+	}
+	results, err := ExecuteTests(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPass, results.Status)
+	require.Len(t, results.Scenarios, 1)
+	assert.NotNil(t, results.Latency)
+}
+
+func TestEndpointWithVariables(t *testing.T) {
+	endpoint := Endpoint{
+		Path:    "/users/{{ user_id }}",
+		Headers: map[string]string{"Authorization": "Bearer {{token}}"},
+		Query:   map[string]string{"expand": "{{field}}"},
+		Body:    &RequestBody{JSON: map[string]interface{}{"tags": []string{"{{field}}"}}},
+	}
+	rendered, err := endpoint.withVariables(map[string]string{"user_id": "7", "token": "abc", "field": "roles"})
+	require.NoError(t, err)
+	assert.Equal(t, "/users/7", rendered.Path)
+	assert.Equal(t, "Bearer abc", rendered.Headers["Authorization"])
+	assert.Equal(t, "roles", rendered.Query["expand"])
+	assert.JSONEq(t, `{"tags":["roles"]}`, string(rendered.Body.JSON.(json.RawMessage)))
+	assert.Equal(t, "Bearer {{token}}", endpoint.Headers["Authorization"])
+}
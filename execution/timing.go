@@ -0,0 +1,123 @@
+package execution
+
+import (
+	"context"
+	"crypto/tls"
+	"math"
+	"net/http/httptrace"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Timings holds the duration of each phase of a request, in milliseconds.
+// Phases that did not happen (e.g. TLS on plain HTTP, or DNS when the
+// connection was reused) are left at zero.
+type Timings struct {
+	DNSLookupMs       float64 `json:"dns_lookup_ms"`
+	TCPConnectMs      float64 `json:"tcp_connect_ms"`
+	TLSHandshakeMs    float64 `json:"tls_handshake_ms"`
+	TimeToFirstByteMs float64 `json:"time_to_first_byte_ms"`
+	TotalMs           float64 `json:"total_ms"`
+}
+
+// LatencySummary aggregates the total response times of a run.
+type LatencySummary struct {
+	MinMs float64 `json:"min_ms"`
+	MaxMs float64 `json:"max_ms"`
+	P50Ms float64 `json:"p50_ms"`
+	P95Ms float64 `json:"p95_ms"`
+}
+
+// timingRecorder collects timestamps from httptrace hooks. Hooks may fire
+// from different goroutines, so access is guarded by a mutex.
+type timingRecorder struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+}
+
+func newTimingRecorder() *timingRecorder {
+	return &timingRecorder{start: time.Now()}
+}
+
+// withTrace returns a context that reports request phases to the recorder.
+func (r *timingRecorder) withTrace(ctx context.Context) context.Context {
+	record := func(target *time.Time) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if target.IsZero() {
+			*target = time.Now()
+		}
+	}
+	trace := &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { record(&r.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { record(&r.dnsDone) },
+		ConnectStart:         func(string, string) { record(&r.connectStart) },
+		ConnectDone:          func(string, string, error) { record(&r.connectDone) },
+		TLSHandshakeStart:    func() { record(&r.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { record(&r.tlsDone) },
+		GotFirstResponseByte: func() { record(&r.firstByte) },
+	}
+	return httptrace.WithClientTrace(ctx, trace)
+}
+
+// timings computes the phase durations, using end as the completion time.
+func (r *timingRecorder) timings(end time.Time) *Timings {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Timings{
+		DNSLookupMs:       phaseMs(r.dnsStart, r.dnsDone),
+		TCPConnectMs:      phaseMs(r.connectStart, r.connectDone),
+		TLSHandshakeMs:    phaseMs(r.tlsStart, r.tlsDone),
+		TimeToFirstByteMs: phaseMs(r.start, r.firstByte),
+		TotalMs:           phaseMs(r.start, end),
+	}
+}
+
+func phaseMs(start time.Time, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return durationMs(end.Sub(start))
+}
+
+func durationMs(d time.Duration) float64 {
+	return math.Round(float64(d.Microseconds())) / 1000
+}
+
+// summarizeLatency computes min, max and percentile response times over
+// every result that has timings. Returns nil if there are none.
+func summarizeLatency(results []CheckResult) *LatencySummary {
+	totals := []float64{}
+	for _, result := range results {
+		if result.Timings != nil && result.Error == nil {
+			totals = append(totals, result.Timings.TotalMs)
+		}
+	}
+	if len(totals) == 0 {
+		return nil
+	}
+	sort.Float64s(totals)
+	return &LatencySummary{
+		MinMs: totals[0],
+		MaxMs: totals[len(totals)-1],
+		P50Ms: percentile(totals, 50),
+		P95Ms: percentile(totals, 95),
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
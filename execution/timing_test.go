@@ -0,0 +1,73 @@
+package execution
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeLatency(t *testing.T) {
+	results := []CheckResult{}
+	for _, total := range []float64{40, 10, 30, 20, 50, 60, 70, 80, 90, 100} {
+		results = append(results, CheckResult{Timings: &Timings{TotalMs: total}})
+	}
+	results = append(results, CheckResult{
+		Timings: &Timings{TotalMs: 5000},
+		Error:   &CheckError{Category: ErrorCategoryTimeout},
+	})
+	results = append(results, CheckResult{})
+
+	summary := summarizeLatency(results)
+	require.NotNil(t, summary)
+	assert.Equal(t, 10.0, summary.MinMs)
+	assert.Equal(t, 100.0, summary.MaxMs)
+	assert.Equal(t, 50.0, summary.P50Ms)
+	assert.Equal(t, 100.0, summary.P95Ms)
+}
+
+func TestSummarizeLatencyEmpty(t *testing.T) {
+	assert.Nil(t, summarizeLatency([]CheckResult{}))
+}
+
+func TestExecuteTestsRecordsTimings(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/fast", func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/slow", func(res http.ResponseWriter, req *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		res.WriteHeader(http.StatusOK)
+	})
+	mockServer := httptest.NewServer(mux)
+	defer mockServer.Close()
+
+	maxResponseMs := 20
+	request := TestExecutionRequest{
+		BaseURL: mockServer.URL,
+		Endpoints: []Endpoint{
+			{Path: "/fast", ExpectedStatus: http.StatusOK},
+			{Path: "/slow", ExpectedStatus: http.StatusOK, MaxResponseMs: &maxResponseMs},
+		},
+	}
+	results, err := ExecuteTests(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFail, results.Status)
+
+	fast := results.Results[0]
+	require.NotNil(t, fast.Timings)
+	assert.Greater(t, fast.Timings.TotalMs, 0.0)
+	assert.Greater(t, fast.Timings.TimeToFirstByteMs, 0.0)
+
+	slow := results.Results[1]
+	assert.Equal(t, "FAIL", slow.StatusCode)
+	assert.GreaterOrEqual(t, slow.Timings.TotalMs, 50.0)
+	assert.Contains(t, slow.Message, "exceeding max of 20ms")
+
+	require.NotNil(t, results.Latency)
+	assert.Equal(t, slow.Timings.TotalMs, results.Latency.MaxMs)
+}
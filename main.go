@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"flag"
 
 	"github.com/jgfranco17/aeternum/api/db"
 	env "github.com/jgfranco17/aeternum/api/environment"
+	"github.com/jgfranco17/aeternum/api/jobs"
+	"github.com/jgfranco17/aeternum/api/notify"
+	"github.com/jgfranco17/aeternum/api/retention"
 	"github.com/jgfranco17/aeternum/api/router"
 	"github.com/jgfranco17/aeternum/api/router/system"
+	"github.com/jgfranco17/aeternum/api/scheduler"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
@@ -29,21 +34,41 @@ func init() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	prometheus.Register(system.HttpLastRequestReceivedTime)
+	prometheus.Register(jobs.QueueDepth)
+	prometheus.Register(jobs.QueueWorkers)
+	prometheus.Register(jobs.QueueBusyWorkers)
+	prometheus.Register(retention.PurgedResults)
 }
 
 func main() {
 	flag.Parse()
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(context.Background(), flag.Args()[1:]); err != nil {
+			logrus.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
 	if *devMode {
 		logrus.Infof("Running API server on port %d in dev mode", *port)
 	} else {
 		logrus.Infof("Running API production server on port %d", *port)
 		gin.SetMode(gin.ReleaseMode)
 	}
-	dbClient, err := db.NewClient()
+	dbConfig := db.ConfigFromEnvironment()
+	logrus.Infof("Using %s storage backend", dbConfig.Driver)
+	dbClient, err := db.Open(context.Background(), dbConfig)
 	if err != nil {
 		logrus.Fatalf("Error initializing database client: %v", err)
 	}
-	service, err := router.CreateNewService(*port, dbClient)
+	if err := db.CheckSchema(context.Background(), dbClient); err != nil {
+		logrus.Fatalf("Refusing to start: %v; run 'aeternum migrate up' first", err)
+	}
+	// One dispatcher serves both API and scheduled runs, since alert
+	// deduplication is kept in memory per dispatcher
+	notifier := notify.NewDispatcher(dbClient)
+	scheduler.New(dbClient, notifier).Start(context.Background())
+	retention.New(dbClient).Start(context.Background())
+	service, err := router.CreateNewService(*port, dbClient, notifier)
 	if err != nil {
 		logrus.Fatalf("Error creating the server: %v", err)
 	}